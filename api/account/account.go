@@ -0,0 +1,55 @@
+package account
+
+import (
+	"errors"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// Types of account.
+const (
+	TypeCash       = "cash"
+	TypeBank       = "bank"
+	TypeCreditCard = "credit_card"
+)
+
+// DefaultCurrency is the currency of an account created without one.
+const DefaultCurrency = currency.Default
+
+// Account is where a spender keeps money. Balance is OpeningBalance plus
+// the account's confirmed incomes and incoming transfers, less its
+// confirmed expenses and outgoing transfers, converted to the account's
+// currency where they were made in another; a credit card's balance is
+// negative while money is owed on it.
+type Account struct {
+	ID             int          `json:"id"`
+	SpenderID      int          `json:"spender_id"`
+	Name           string       `json:"name"`
+	Type           string       `json:"type"`
+	Currency       string       `json:"currency"`
+	OpeningBalance money.Amount `json:"opening_balance"`
+	Balance        money.Amount `json:"balance"`
+}
+
+type CreateRequest struct {
+	Name           string       `json:"name"`
+	Type           string       `json:"type"`
+	Currency       string       `json:"currency"`
+	OpeningBalance money.Amount `json:"opening_balance"`
+}
+
+const maxNameLength = 50
+
+var (
+	ErrNotFound        = errors.New("account not found")
+	ErrInvalidName     = errors.New("name is required and must be at most 50 characters")
+	ErrNameTaken       = errors.New("an account with this name already exists")
+	ErrInvalidType     = errors.New("type must be cash, bank or credit_card")
+	ErrInvalidCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrHasTransactions = errors.New("account still has transactions")
+)
+
+func validType(t string) bool {
+	return t == TypeCash || t == TypeBank || t == TypeCreditCard
+}
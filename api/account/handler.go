@@ -0,0 +1,118 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	List(c echo.Context) error
+	Get(c echo.Context) error
+	Create(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) List(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	result, err := h.service.List(caller.SpenderID)
+	if err != nil {
+		return h.fail(c, "list accounts error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Get(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid account ID"})
+	}
+
+	result, err := h.service.Get(caller.SpenderID, id)
+	if err != nil {
+		return h.fail(c, "get account error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Create(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var request CreateRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Create(caller.SpenderID, request)
+	if err != nil {
+		return h.fail(c, "create account error", err)
+	}
+
+	mlog.L(c).Info("create account successfully", zap.Int("id", result.ID))
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h handler) Delete(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid account ID"})
+	}
+
+	if err := h.service.Delete(caller.SpenderID, id); err != nil {
+		return h.fail(c, "delete account error", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	case errors.Is(err, ErrNameTaken), errors.Is(err, ErrHasTransactions):
+		return c.JSON(http.StatusConflict, errs.Build(err))
+	case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidType), errors.Is(err, ErrInvalidCurrency):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	case errors.Is(err, currency.ErrMissingRate):
+		return c.JSON(http.StatusUnprocessableEntity, errs.Build(err))
+	default:
+		mlog.L(c).Error(msg, zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+}
@@ -0,0 +1,145 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(spenderID int) ([]Account, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]Account), args.Error(1)
+}
+
+func (m *MockService) Get(spenderID int, id int) (Account, error) {
+	args := m.Called(spenderID, id)
+	return args.Get(0).(Account), args.Error(1)
+}
+
+func (m *MockService) Create(spenderID int, request CreateRequest) (Account, error) {
+	args := m.Called(spenderID, request)
+	return args.Get(0).(Account), args.Error(1)
+}
+
+func (m *MockService) Delete(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func newAuthenticatedContext(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/accounts", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3})
+	return c, rec
+}
+
+func TestHandler_List(t *testing.T) {
+	c, rec := newAuthenticatedContext(http.MethodGet, "")
+	mockService := new(MockService)
+	mockService.On("List", 3).Return([]Account{{ID: 1, SpenderID: 3, Name: "Bank", Type: TypeBank, Currency: "THB", OpeningBalance: 1000_00, Balance: 700_50}}, nil)
+
+	err := NewHandler(mockService).List(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id": 1, "spender_id": 3, "name": "Bank", "type": "bank", "currency": "THB", "opening_balance": 1000, "balance": 700.5}]`, rec.Body.String())
+}
+
+func TestHandler_Get(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockError      error
+		expectedStatus int
+	}{
+		{"found", "1", nil, http.StatusOK},
+		{"invalid id", "abc", nil, http.StatusBadRequest},
+		{"not found", "1", ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodGet, "")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			mockService := new(MockService)
+			mockService.On("Get", 3, 1).Return(Account{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Get(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Create(t *testing.T) {
+	request := CreateRequest{Name: "Card", Type: TypeCreditCard, Currency: "THB"}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid type", ErrInvalidType, http.StatusBadRequest},
+		{"invalid currency", ErrInvalidCurrency, http.StatusBadRequest},
+		{"name taken", ErrNameTaken, http.StatusConflict},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, `{"name": "Card", "type": "credit_card", "currency": "THB"}`)
+			mockService := new(MockService)
+			mockService.On("Create", 3, request).Return(Account{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not found", ErrNotFound, http.StatusNotFound},
+		{"in use", ErrHasTransactions, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodDelete, "")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			mockService := new(MockService)
+			mockService.On("Delete", 3, 1).Return(tt.mockError)
+
+			err := NewHandler(mockService).Delete(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
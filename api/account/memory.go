@@ -0,0 +1,112 @@
+package account
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// Transactions is what the in-memory repository needs from transaction
+// storage: the net amount recorded against each of a spender's accounts in
+// the account's currency, and whether an account has any transaction at all.
+type Transactions interface {
+	NetByAccount(spenderID int, currencies map[int]string) (map[int]money.Amount, error)
+	CountByAccount(accountID int) int
+}
+
+type memoryRepository struct {
+	mu           sync.Mutex
+	accounts     map[int]Account
+	nextID       int
+	transactions Transactions
+}
+
+// NewMemoryRepository keeps accounts in process memory for the
+// --storage=memory server mode. transactions may be nil, in which case
+// every account is treated as having none.
+func NewMemoryRepository(transactions Transactions) Repository {
+	return &memoryRepository{
+		accounts:     map[int]Account{},
+		nextID:       1,
+		transactions: transactions,
+	}
+}
+
+func (r *memoryRepository) List(spenderID int) ([]Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	net, err := r.net(spenderID)
+	if err != nil {
+		return nil, err
+	}
+	accounts := []Account{}
+	for _, a := range r.accounts {
+		if a.SpenderID == spenderID {
+			a.Balance = a.OpeningBalance + net[a.ID]
+			accounts = append(accounts, a)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+
+	return accounts, nil
+}
+
+func (r *memoryRepository) Get(spenderID int, id int) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.accounts[id]
+	if !ok || a.SpenderID != spenderID {
+		return Account{}, ErrNotFound
+	}
+	net, err := r.net(spenderID)
+	if err != nil {
+		return Account{}, err
+	}
+	a.Balance = a.OpeningBalance + net[a.ID]
+
+	return a, nil
+}
+
+func (r *memoryRepository) Create(a Account) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a.ID = r.nextID
+	r.nextID++
+	a.Balance = a.OpeningBalance
+	r.accounts[a.ID] = a
+
+	return a, nil
+}
+
+func (r *memoryRepository) Delete(spenderID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.accounts[id]
+	if !ok || a.SpenderID != spenderID {
+		return ErrNotFound
+	}
+	if r.transactions != nil && r.transactions.CountByAccount(id) > 0 {
+		return ErrHasTransactions
+	}
+	delete(r.accounts, id)
+
+	return nil
+}
+
+func (r *memoryRepository) net(spenderID int) (map[int]money.Amount, error) {
+	if r.transactions == nil {
+		return nil, nil
+	}
+	currencies := map[int]string{}
+	for _, a := range r.accounts {
+		if a.SpenderID == spenderID {
+			currencies[a.ID] = a.Currency
+		}
+	}
+	return r.transactions.NetByAccount(spenderID, currencies)
+}
@@ -0,0 +1,133 @@
+package account
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+)
+
+// Repository stores accounts. Every method is scoped to one spender;
+// accounts of another spender are reported as ErrNotFound. Balances are
+// worked out from the account's transactions on every read, and reads
+// report currency.ErrMissingRate when one of them cannot be converted.
+type Repository interface {
+	List(spenderID int) ([]Account, error)
+	Get(spenderID int, id int) (Account, error)
+	Create(a Account) (Account, error)
+	Delete(spenderID int, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	columns = `a.id, a.spender_id, a.name, a.type, a.currency, a.opening_balance, ` +
+		`a.opening_balance + COALESCE(SUM(CASE WHEN t.transaction_type = 'income' OR t.entry = 'credit' THEN 1 ELSE -1 END * ` +
+		`convert_currency(t.amount, t.currency, a.currency, t.date)), 0)`
+	balances   = ` FROM account a LEFT JOIN transaction t ON t.account_id = a.id AND t.status = 'confirmed'`
+	listStmt   = `SELECT ` + columns + balances + ` WHERE a.spender_id = $1 GROUP BY a.id ORDER BY a.id`
+	getStmt    = `SELECT ` + columns + balances + ` WHERE a.id = $1 AND a.spender_id = $2 GROUP BY a.id`
+	createStmt = `INSERT INTO account (spender_id, name, type, currency, opening_balance) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	lockStmt     = `SELECT id FROM account WHERE id = $1 AND spender_id = $2 FOR UPDATE`
+	countTxnStmt = `SELECT COUNT(*) FROM transaction WHERE account_id = $1`
+	deleteStmt   = `DELETE FROM account WHERE id = $1`
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAccount(row scanner) (Account, error) {
+	var a Account
+	err := row.Scan(&a.ID, &a.SpenderID, &a.Name, &a.Type, &a.Currency, &a.OpeningBalance, &a.Balance)
+	return a, err
+}
+
+func (r repository) List(spenderID int) ([]Account, error) {
+	rows, err := r.db.Query(listStmt, spenderID)
+	if err != nil {
+		return nil, rateError(err)
+	}
+	defer rows.Close()
+
+	accounts := []Account{}
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, rateError(err)
+		}
+		accounts = append(accounts, a)
+	}
+
+	return accounts, rateError(rows.Err())
+}
+
+func (r repository) Get(spenderID int, id int) (Account, error) {
+	a, err := scanAccount(r.db.QueryRow(getStmt, id, spenderID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Account{}, ErrNotFound
+	}
+	if err != nil {
+		return Account{}, rateError(err)
+	}
+
+	return a, nil
+}
+
+func rateError(err error) error {
+	if currency.IsMissingRate(err) {
+		return currency.ErrMissingRate
+	}
+	return err
+}
+
+func (r repository) Create(a Account) (Account, error) {
+	err := r.db.QueryRow(createStmt, a.SpenderID, a.Name, a.Type, a.Currency, a.OpeningBalance).Scan(&a.ID)
+	if err != nil {
+		return Account{}, err
+	}
+	a.Balance = a.OpeningBalance
+
+	return a, nil
+}
+
+// Delete removes an account that no transaction was recorded against. The
+// account row is locked while its transactions are counted so none can be
+// added in between.
+func (r repository) Delete(spenderID int, id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(lockStmt, id, spenderID).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow(countTxnStmt, id).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrHasTransactions
+	}
+
+	if _, err := tx.Exec(deleteStmt, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,111 @@
+package account
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var accountColumns = []string{"id", "spender_id", "name", "type", "currency", "opening_balance", "balance"}
+
+func TestRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	rows := sqlmock.NewRows(accountColumns).
+		AddRow(1, 3, "Bank", "bank", "THB", "1000.00", "700.00").
+		AddRow(2, 3, "Card", "credit_card", "THB", "0.00", "-450.50")
+	mock.ExpectQuery(listStmt).WithArgs(3).WillReturnRows(rows)
+
+	accounts, err := NewRepository(db).List(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Account{
+		{ID: 1, SpenderID: 3, Name: "Bank", Type: TypeBank, Currency: "THB", OpeningBalance: 1000_00, Balance: 700_00},
+		{ID: 2, SpenderID: 3, Name: "Card", Type: TypeCreditCard, Currency: "THB", Balance: -450_50},
+	}, accounts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Get(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows(accountColumns).AddRow(1, 3, "Wallet", "cash", "THB", "200.00", "150.00")
+		mock.ExpectQuery(getStmt).WithArgs(1, 3).WillReturnRows(rows)
+
+		a, err := NewRepository(db).Get(3, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Account{ID: 1, SpenderID: 3, Name: "Wallet", Type: TypeCash, Currency: "THB", OpeningBalance: 200_00, Balance: 150_00}, a)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(getStmt).WithArgs(1, 4).WillReturnError(sql.ErrNoRows)
+
+		_, err := NewRepository(db).Get(4, 1)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectQuery(createStmt).WithArgs(3, "Bank", "bank", "THB", "1000.00").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+	a, err := NewRepository(db).Create(Account{SpenderID: 3, Name: "Bank", Type: TypeBank, Currency: "THB", OpeningBalance: 1000_00})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Account{ID: 9, SpenderID: 3, Name: "Bank", Type: TypeBank, Currency: "THB", OpeningBalance: 1000_00, Balance: 1000_00}, a)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  bool
+		count   int
+		deleted bool
+		err     error
+	}{
+		{"unused account", true, 0, true, nil},
+		{"account with transactions", true, 2, false, ErrHasTransactions},
+		{"account of another spender", false, 0, false, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			defer db.Close()
+
+			mock.ExpectBegin()
+			locked := sqlmock.NewRows([]string{"id"})
+			if tt.locked {
+				locked.AddRow(1)
+			}
+			mock.ExpectQuery(lockStmt).WithArgs(1, 3).WillReturnRows(locked)
+			if tt.locked {
+				mock.ExpectQuery(countTxnStmt).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.count))
+			}
+			if tt.deleted {
+				mock.ExpectExec(deleteStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			err := NewRepository(db).Delete(3, 1)
+
+			assert.Equal(t, tt.err, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
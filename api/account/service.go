@@ -0,0 +1,75 @@
+package account
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+)
+
+type service struct {
+	repository Repository
+}
+
+type Service interface {
+	List(spenderID int) ([]Account, error)
+	Get(spenderID int, id int) (Account, error)
+	Create(spenderID int, request CreateRequest) (Account, error)
+	Delete(spenderID int, id int) error
+}
+
+func NewService(repository Repository) Service {
+	return service{repository: repository}
+}
+
+// List returns the spender's accounts with their balances, by id.
+func (s service) List(spenderID int) ([]Account, error) {
+	return s.repository.List(spenderID)
+}
+
+func (s service) Get(spenderID int, id int) (Account, error) {
+	return s.repository.Get(spenderID, id)
+}
+
+// Create adds an account for the spender, in DefaultCurrency unless another
+// is given. Names must not repeat one of the spender's accounts in any case.
+func (s service) Create(spenderID int, request CreateRequest) (Account, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return Account{}, ErrInvalidName
+	}
+	if !validType(request.Type) {
+		return Account{}, ErrInvalidType
+	}
+	code := currency.Normalize(request.Currency)
+	if code == "" {
+		code = DefaultCurrency
+	}
+	if !currency.Valid(code) {
+		return Account{}, ErrInvalidCurrency
+	}
+
+	accounts, err := s.repository.List(spenderID)
+	if err != nil {
+		return Account{}, err
+	}
+	for _, a := range accounts {
+		if strings.EqualFold(a.Name, name) {
+			return Account{}, ErrNameTaken
+		}
+	}
+
+	return s.repository.Create(Account{
+		SpenderID:      spenderID,
+		Name:           name,
+		Type:           request.Type,
+		Currency:       code,
+		OpeningBalance: request.OpeningBalance,
+	})
+}
+
+// Delete removes an account no transaction was recorded against; one in use
+// reports ErrHasTransactions.
+func (s service) Delete(spenderID int, id int) error {
+	return s.repository.Delete(spenderID, id)
+}
@@ -0,0 +1,92 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  CreateRequest
+		expected Account
+		err      error
+	}{
+		{"bank account in the default currency", CreateRequest{Name: " Savings ", Type: TypeBank, OpeningBalance: 1000_00},
+			Account{Name: "Savings", Type: TypeBank, Currency: DefaultCurrency, OpeningBalance: 1000_00, Balance: 1000_00}, nil},
+		{"credit card owing money", CreateRequest{Name: "Visa", Type: TypeCreditCard, Currency: "usd", OpeningBalance: -250_50},
+			Account{Name: "Visa", Type: TypeCreditCard, Currency: "USD", OpeningBalance: -250_50, Balance: -250_50}, nil},
+		{"missing name", CreateRequest{Name: " ", Type: TypeCash}, Account{}, ErrInvalidName},
+		{"name too long", CreateRequest{Name: "an account name that is much longer than fifty chars", Type: TypeCash}, Account{}, ErrInvalidName},
+		{"unknown type", CreateRequest{Name: "Piggy bank", Type: "jar"}, Account{}, ErrInvalidType},
+		{"invalid currency", CreateRequest{Name: "Wallet", Type: TypeCash, Currency: "baht"}, Account{}, ErrInvalidCurrency},
+		{"name already used", CreateRequest{Name: "WALLET", Type: TypeCash}, Account{}, ErrNameTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(NewMemoryRepository(nil))
+			_, _ = s.Create(7, CreateRequest{Name: "Wallet", Type: TypeCash})
+
+			a, err := s.Create(7, tt.request)
+
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				tt.expected.ID = 2
+				tt.expected.SpenderID = 7
+				assert.Equal(t, tt.expected, a)
+			}
+		})
+	}
+
+	t.Run("names are per spender", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(1, CreateRequest{Name: "Wallet", Type: TypeCash})
+
+		_, err := s.Create(2, CreateRequest{Name: "Wallet", Type: TypeCash})
+
+		assert.NoError(t, err)
+	})
+}
+
+type fakeTransactions struct {
+	net   map[int]money.Amount
+	count map[int]int
+}
+
+func (f fakeTransactions) NetByAccount(int, map[int]string) (map[int]money.Amount, error) {
+	return f.net, nil
+}
+func (f fakeTransactions) CountByAccount(id int) int { return f.count[id] }
+
+func TestService_Balances(t *testing.T) {
+	s := NewService(NewMemoryRepository(fakeTransactions{net: map[int]money.Amount{1: -300_00, 2: 300_00}}))
+	_, _ = s.Create(1, CreateRequest{Name: "Bank", Type: TypeBank, OpeningBalance: 1000_00})
+	_, _ = s.Create(1, CreateRequest{Name: "Card", Type: TypeCreditCard, OpeningBalance: -300_00})
+
+	accounts, err := s.List(1)
+	card, getErr := s.Get(1, 2)
+	_, otherErr := s.Get(2, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(700_00), accounts[0].Balance)
+	assert.Equal(t, money.Amount(0), accounts[1].Balance)
+	assert.NoError(t, getErr)
+	assert.Equal(t, money.Amount(0), card.Balance)
+	assert.Equal(t, ErrNotFound, otherErr)
+}
+
+func TestService_Delete(t *testing.T) {
+	s := NewService(NewMemoryRepository(fakeTransactions{count: map[int]int{1: 2}}))
+	bank, _ := s.Create(1, CreateRequest{Name: "Bank", Type: TypeBank})
+	cash, _ := s.Create(1, CreateRequest{Name: "Cash", Type: TypeCash})
+
+	assert.Equal(t, ErrHasTransactions, s.Delete(1, bank.ID))
+	assert.Equal(t, ErrNotFound, s.Delete(2, cash.ID), "only the owner may delete")
+	assert.NoError(t, s.Delete(1, cash.ID))
+
+	accounts, _ := s.List(1)
+	assert.Len(t, accounts, 1)
+}
@@ -1,57 +1,210 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/account"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/apikey"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/budget"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/eslip"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/health"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/recurring"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/session"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
 )
 
+// Server is the HTTP API together with the workers that process what it
+// queues and the scheduler of recurring transactions. The caller starts and
+// drains Jobs and Scheduler alongside the server.
 type Server struct {
 	*echo.Echo
+	Jobs      *job.Pool
+	Scheduler *recurring.Scheduler
 }
 
-func New(db *sql.DB, cfg config.Config, logger *zap.Logger) *Server {
+func New(store Storage, cfg config.Config, logger *zap.Logger) *Server {
 	e := echo.New()
 
 	e.Use(middleware.Logger())
 	e.Use(mlog.Middleware(logger))
 
 	v1 := e.Group("/api/v1")
+	slipStore := newBlobStore(cfg.Blob, logger)
+	jobs := job.NewPool(store.Jobs, cfg.Jobs, logger)
+	processor := eslip.NewProcessor(cfg.Upload.MaxFileSize, slipStore, store.Slips, newExtractor(cfg.OCR), cfg.OCR.MinConfidence, logger)
+	jobs.Handle(eslip.JobExtract, processor.Process)
 
 	v1.GET("/slow", health.Slow)
-	v1.GET("/health", health.Check(db))
-	v1.POST("/upload", eslip.Upload)
+	v1.GET("/health", health.Check(store.DB))
+
+	users := user.NewService(store.Users, cfg.Auth)
+	userHandler := user.NewHandler(users)
+	v1.POST("/users", userHandler.Register)
 
-	v1.Use(middleware.BasicAuth(AuthCheck))
+	signer := newSigner(cfg.Auth, logger)
+	{
+		h := session.NewHandler(session.NewService(store.Sessions, users, signer, cfg.Auth))
+		v1.POST("/auth/login", h.Login)
+		v1.POST("/auth/refresh", h.Refresh)
+		v1.POST("/auth/logout", h.Logout)
+	}
+
+	// Called by the slip extraction service, which signs its requests
+	// instead of logging in.
+	{
+		h := eslip.NewWebhookHandler(cfg.Webhook, store.Slips, store.Transactions)
+		v1.POST("/slips/:key/extraction", h.Extraction)
+	}
+
+	apiKeys := apikey.NewService(store.APIKeys)
+
+	v1.Use(auth.BearerAuth(signer))
+	v1.Use(apikey.Auth(apiKeys))
+	v1.Use(middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+		Skipper:   auth.Authenticated,
+		Validator: AuthCheck(users, cfg.FeatureFlag),
+	}))
+
+	v1.PUT("/users/me/password", userHandler.ChangePassword, auth.Require(auth.ScopeAccountManage))
+	{
+		h := eslip.NewHandler(cfg.Upload, slipStore, store.Slips, store.Jobs)
+		v1.POST("/upload", h.Upload, auth.Require(auth.ScopeEslipWrite))
+		v1.GET("/slips/:key", h.Get, auth.Require(auth.ScopeEslipRead))
+		v1.GET("/slips/:key/status", h.Status, auth.Require(auth.ScopeEslipRead))
+	}
+
+	{
+		h := apikey.NewHandler(apiKeys)
+		v1.POST("/apikeys", h.Create, auth.Require(auth.ScopeAccountManage))
+		v1.GET("/apikeys", h.List, auth.Require(auth.ScopeAccountManage))
+		v1.DELETE("/apikeys/:id", h.Revoke, auth.Require(auth.ScopeAccountManage))
+	}
 
 	{
 		middlewareService := transaction.NewMiddlewareService()
 		middlewareHandler := transaction.NewMiddleware(middlewareService)
 
-		repository := transaction.NewRepository(db)
-		service := transaction.NewService(repository)
+		service := transaction.NewService(store.Transactions)
 		handler := transaction.NewHandler(service)
-		v1.GET("/transactions", handler.GetAll, middlewareHandler.SetFilterExpense, middlewareHandler.SetPagination)
-		v1.POST("/transactions", handler.Create)
-		v1.GET("/transactions/expense/detail", handler.GetExpenses)
-		v1.GET("/transactions/summary", handler.GetSummary)
-		v1.GET("/transactions/balance", handler.GetBalance)
-		v1.PUT("/transactions/:id", handler.UpdateExpense)
-		v1.DELETE("/transactions/:id", handler.DeleteExpense)
+		// Spenders only ever see their own rows; callers with
+		// PermTransactionsReadAny may list across spenders.
+		read := auth.Require(auth.ScopeTransactionsRead)
+		write := auth.Require(auth.ScopeTransactionsWrite)
+		v1.GET("/transactions", handler.GetAll, read, middlewareHandler.SetFilterExpense, middlewareHandler.SetPagination)
+		v1.POST("/transactions", handler.Create, auth.Require(auth.ScopeTransactionsCreate))
+		v1.POST("/transfers", handler.CreateTransfer, auth.Require(auth.ScopeTransactionsCreate))
+		v1.GET("/transactions/expense/detail", handler.GetExpenses, read, middlewareHandler.SetFilterExpense, middlewareHandler.SetPagination, middlewareHandler.SetSort)
+		v1.GET("/transactions/summary", handler.GetSummary, read)
+		v1.GET("/transactions/balance", handler.GetBalance, read)
+		v1.GET("/reports/categories", handler.GetCategoryReport, read, middlewareHandler.SetFilterExpense)
+		v1.PUT("/transactions/:id", handler.UpdateExpense, write)
+		v1.DELETE("/transactions/:id", handler.DeleteExpense, write)
+		v1.POST("/transactions/:id/confirm", handler.Confirm, write)
+		v1.POST("/transactions/:id/reject", handler.Reject, write)
+	}
+
+	{
+		h := category.NewHandler(category.NewService(store.Categories))
+		v1.GET("/categories", h.List, auth.Require(auth.ScopeTransactionsRead))
+		v1.POST("/categories", h.Create, auth.Require(auth.ScopeTransactionsWrite))
+		v1.DELETE("/categories/:id", h.Delete, auth.Require(auth.ScopeTransactionsWrite))
+	}
+
+	{
+		h := account.NewHandler(account.NewService(store.Accounts))
+		v1.GET("/accounts", h.List, auth.Require(auth.ScopeTransactionsRead))
+		v1.POST("/accounts", h.Create, auth.Require(auth.ScopeTransactionsWrite))
+		v1.GET("/accounts/:id", h.Get, auth.Require(auth.ScopeTransactionsRead))
+		v1.DELETE("/accounts/:id", h.Delete, auth.Require(auth.ScopeTransactionsWrite))
 	}
 
 	{
-		h := spender.New(cfg.FeatureFlag, db)
-		v1.GET("/spenders", h.GetAll)
-		v1.POST("/spenders", h.Create)
+		h := currency.NewHandler(currency.NewService(store.Rates))
+		write := auth.Require(auth.PermExchangeRatesWrite)
+		v1.GET("/exchange-rates", h.List, auth.Require(auth.ScopeTransactionsRead))
+		v1.PUT("/exchange-rates", h.Save, write)
+		v1.POST("/exchange-rates/import", h.Import, write)
+		v1.DELETE("/exchange-rates/:currency/:date", h.Delete, write)
+	}
+
+	{
+		h := budget.NewHandler(budget.NewService(store.Budgets, store.Categories, store.Transactions, spenderSettings{spenders: store.Spenders}))
+		read := auth.Require(auth.ScopeTransactionsRead)
+		write := auth.Require(auth.ScopeTransactionsWrite)
+		v1.GET("/budgets", h.List, read)
+		v1.POST("/budgets", h.Create, write)
+		v1.GET("/budgets/status", h.Status, read)
+		v1.GET("/budgets/:id", h.Get, read)
+		v1.PUT("/budgets/:id", h.Update, write)
+		v1.DELETE("/budgets/:id", h.Delete, write)
+	}
+
+	recurringService := recurring.NewService(store.Recurring, store.Categories, spenderSettings{spenders: store.Spenders})
+	{
+		h := recurring.NewHandler(recurringService)
+		read := auth.Require(auth.ScopeTransactionsRead)
+		write := auth.Require(auth.ScopeTransactionsWrite)
+		v1.GET("/recurring", h.List, read)
+		v1.POST("/recurring", h.Create, write)
+		v1.GET("/recurring/upcoming", h.Upcoming, read)
+		v1.GET("/recurring/:id", h.Get, read)
+		v1.PUT("/recurring/:id", h.Update, write)
+		v1.DELETE("/recurring/:id", h.Delete, write)
+		v1.POST("/recurring/:id/skip", h.Skip, write)
+	}
+
+	{
+		h := spender.NewHandler(cfg.FeatureFlag, spender.NewService(store.Spenders))
+		v1.GET("/spenders", h.GetAll, auth.Require(auth.ScopeSpendersRead))
+		v1.POST("/spenders", h.Create, auth.Require(auth.ScopeSpendersWrite))
+		v1.GET("/spenders/:id", h.Get, auth.RequireSelfOr(auth.ScopeSpendersRead))
+		v1.PUT("/spenders/:id", h.Update, auth.RequireSelfOr(auth.ScopeSpendersWrite))
+		v1.PATCH("/spenders/:id", h.Patch, auth.RequireSelfOr(auth.ScopeSpendersWrite))
+		v1.DELETE("/spenders/:id", h.Delete, auth.Require(auth.ScopeSpendersWrite))
+	}
+
+	return &Server{Echo: e, Jobs: jobs, Scheduler: recurring.NewScheduler(recurringService, cfg.Recurring.Interval, logger)}
+}
+
+func newSigner(cfg config.Auth, logger *zap.Logger) auth.Signer {
+	signer, err := auth.NewSigner(cfg)
+	if errors.Is(err, auth.ErrNoSigningKeys) {
+		logger.Warn("AUTH_JWT_KEYS is not set, access tokens are signed with an ephemeral key and will not survive a restart")
+		signer, err = auth.NewEphemeralSigner(cfg.AccessTokenTTL)
+	}
+	if err != nil {
+		logger.Fatal("invalid JWT configuration", zap.Error(err))
+	}
+
+	return signer
+}
+
+// newExtractor returns nil when no OCR command is configured, so only QR
+// codes are read.
+func newExtractor(cfg config.OCR) eslip.Extractor {
+	if cfg.Command == "" {
+		return nil
+	}
+	return eslip.NewExtractor(eslip.NewTesseract(cfg.Command, cfg.Languages))
+}
+
+func newBlobStore(cfg config.Blob, logger *zap.Logger) blob.BlobStore {
+	store, err := blob.New(cfg)
+	if err != nil {
+		logger.Fatal("invalid blob storage configuration", zap.Error(err))
 	}
 
-	return &Server{e}
+	return store
 }
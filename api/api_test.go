@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNew_MemoryStorage(t *testing.T) {
+	cfg := config.Config{
+		Auth: config.Auth{MaxFailedAttempts: 5},
+		Blob: config.Blob{Backend: "local", LocalDir: t.TempDir()},
+	}
+	s := New(NewMemoryStorage(), cfg, zap.NewNop())
+
+	do := func(method, path, body, username string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if username != "" {
+			req.SetBasicAuth(username, "p@ssw0rd!")
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/api/v1/health", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodPost, "/api/v1/users", `{"username": "hongjot", "password": "p@ssw0rd!", "name": "HongJot", "email": "hong@jot.ok"}`, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = do(http.MethodPost, "/api/v1/users", `{"username": "jothong", "password": "p@ssw0rd!", "name": "JotHong", "email": "jot@jot.ok"}`, "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = do(http.MethodPost, "/api/v1/transactions", `{"amount": 100, "category": "food", "transaction_type": "expense"}`, "hongjot")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodGet, "/api/v1/transactions", "", "hongjot")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"category":"food"`)
+
+	rec = do(http.MethodGet, "/api/v1/transactions", "", "jothong")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"category":"food"`)
+
+	rec = do(http.MethodGet, "/api/v1/spenders/1", "", "hongjot")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"email":"hong@jot.ok"`)
+}
+
+func TestNew_MemoryStorage_ChangePasswordRevokesRefreshTokens(t *testing.T) {
+	cfg := config.Config{
+		Auth: config.Auth{
+			MaxFailedAttempts: 5,
+			JWTKeys:           map[string]string{"test": "test-secret-test-secret-test-secret"},
+			JWTActiveKeyID:    "test",
+			AccessTokenTTL:    time.Minute,
+			RefreshTokenTTL:   time.Hour,
+		},
+		Blob: config.Blob{Backend: "local", LocalDir: t.TempDir()},
+	}
+	s := New(NewMemoryStorage(), cfg, zap.NewNop())
+
+	do := func(method, path, body, password string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if password != "" {
+			req.SetBasicAuth("hongjot", password)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/v1/users", `{"username": "hongjot", "password": "p@ssw0rd!", "name": "HongJot", "email": "hong@jot.ok"}`, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = do(http.MethodPost, "/api/v1/auth/login", `{"username": "hongjot", "password": "p@ssw0rd!"}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var login struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &login))
+
+	rec = do(http.MethodPut, "/api/v1/users/me/password", `{"old_password": "p@ssw0rd!", "new_password": "n3w-p@ssw0rd"}`, "p@ssw0rd!")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(http.MethodPost, "/api/v1/auth/refresh", `{"refresh_token": "`+login.RefreshToken+`"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,41 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+)
+
+type APIKey struct {
+	ID         int        `json:"id"`
+	SpenderID  int        `json:"spender_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+func (k APIKey) Identity() auth.Identity {
+	return auth.Identity{
+		Username:  "apikey:" + k.Name,
+		SpenderID: k.SpenderID,
+		Role:      auth.RoleService,
+		APIKeyID:  k.ID,
+		Scopes:    k.Scopes,
+	}
+}
+
+type CreateRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreateResponse carries the plaintext key. It is only ever returned once, at
+// creation; afterwards only its hash is kept.
+type CreateResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	Create(c echo.Context) error
+	List(c echo.Context) error
+	Revoke(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) Create(c echo.Context) error {
+	logger := mlog.L(c)
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var request CreateRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Create(caller.SpenderID, request)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidScope):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	default:
+		logger.Error("create api key error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	logger.Info("create api key successfully", zap.Int("id", result.ID), zap.Strings("scopes", result.Scopes))
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h handler) List(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	result, err := h.service.List(caller.SpenderID)
+	if err != nil {
+		mlog.L(c).Error("list api keys error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Revoke(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid api key ID"})
+	}
+
+	err = h.service.Revoke(caller.SpenderID, id)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	default:
+		mlog.L(c).Error("revoke api key error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Create(spenderID int, request CreateRequest) (CreateResponse, error) {
+	args := m.Called(spenderID, request)
+	return args.Get(0).(CreateResponse), args.Error(1)
+}
+
+func (m *MockService) List(spenderID int) ([]APIKey, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *MockService) Revoke(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func (m *MockService) Authenticate(key string) (APIKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func newAuthenticatedContext(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/apikeys", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3})
+	return c, rec
+}
+
+func TestHandler_Create(t *testing.T) {
+	request := CreateRequest{Name: "lambda", Scopes: []string{auth.ScopeTransactionsCreate}}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid scope", ErrInvalidScope, http.StatusBadRequest},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, `{"name": "lambda", "scopes": ["transactions:create"]}`)
+			mockService := new(MockService)
+			mockService.On("Create", 3, request).Return(CreateResponse{Key: "hj_secret"}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_List(t *testing.T) {
+	c, rec := newAuthenticatedContext(http.MethodGet, "")
+	mockService := new(MockService)
+	mockService.On("List", 3).Return([]APIKey{{ID: 1, Name: "lambda", Prefix: "hj_abcdefgh", KeyHash: "hash"}}, nil)
+	h := NewHandler(mockService)
+
+	err := h.List(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hash")
+}
+
+func TestHandler_Revoke(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"revoked", nil, http.StatusNoContent},
+		{"not found when key belongs to another spender", ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodDelete, "")
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			mockService := new(MockService)
+			mockService.On("Revoke", 3, 1).Return(tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Revoke(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
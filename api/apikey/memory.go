@@ -0,0 +1,91 @@
+package apikey
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu     sync.Mutex
+	keys   map[int]APIKey
+	nextID int
+}
+
+// NewMemoryRepository keeps API keys in process memory for the
+// --storage=memory server mode.
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		keys:   map[int]APIKey{},
+		nextID: 1,
+	}
+}
+
+func (r *memoryRepository) Create(key APIKey) (APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.ID = r.nextID
+	key.CreatedAt = time.Now()
+	r.nextID++
+	r.keys[key.ID] = key
+
+	return key, nil
+}
+
+func (r *memoryRepository) List(spenderID int) ([]APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := []APIKey{}
+	for _, k := range r.keys {
+		if k.SpenderID == spenderID {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (r *memoryRepository) Revoke(spenderID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.keys[id]
+	if !ok || k.SpenderID != spenderID || k.RevokedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	r.keys[id] = k
+
+	return nil
+}
+
+func (r *memoryRepository) FindActiveByHash(keyHash string) (APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash && k.RevokedAt == nil {
+			return k, nil
+		}
+	}
+	return APIKey{}, ErrNotFound
+}
+
+func (r *memoryRepository) TouchLastUsed(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.keys[id]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	k.LastUsedAt = &now
+	r.keys[id] = k
+
+	return nil
+}
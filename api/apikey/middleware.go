@@ -0,0 +1,40 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const scheme = "apikey "
+
+// Auth authenticates requests carrying "Authorization: ApiKey <key>". Like
+// auth.BearerAuth it lets other schemes through for the next authenticator.
+func Auth(s Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if len(header) < len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+				return next(c)
+			}
+
+			k, err := s.Authenticate(header[len(scheme):])
+			if errors.Is(err, ErrInvalidKey) {
+				return c.JSON(http.StatusUnauthorized, errs.Build(err))
+			}
+			if err != nil {
+				mlog.L(c).Error("api key authentication error", zap.Error(err))
+				return c.JSON(http.StatusInternalServerError, errs.Build(err))
+			}
+
+			auth.SetIdentity(c, k.Identity())
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name           string
+		authorization  string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{"valid key", "ApiKey hj_good", http.StatusOK, "key 1 spender 3"},
+		{"invalid key", "ApiKey hj_bad", http.StatusUnauthorized, ""},
+		{"other scheme passes through", "Bearer token", http.StatusOK, "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("Authenticate", "hj_good").Return(APIKey{ID: 1, SpenderID: 3, Name: "lambda"}, nil)
+			mockService.On("Authenticate", "hj_bad").Return(APIKey{}, ErrInvalidKey)
+
+			e := echo.New()
+			e.Use(Auth(mockService))
+			e.GET("/", func(c echo.Context) error {
+				id, ok := auth.IdentityFrom(c)
+				if !ok {
+					return c.String(http.StatusOK, "anonymous")
+				}
+				return c.String(http.StatusOK, fmt.Sprintf("key %d spender %d", id.APIKeyID, id.SpenderID))
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
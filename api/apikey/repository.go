@@ -0,0 +1,106 @@
+package apikey
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var ErrNotFound = errors.New("api key not found")
+
+type Repository interface {
+	Create(key APIKey) (APIKey, error)
+	List(spenderID int) ([]APIKey, error)
+	Revoke(spenderID int, id int) error
+	FindActiveByHash(keyHash string) (APIKey, error)
+	TouchLastUsed(id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const selectAPIKeyQuery = `SELECT id, spender_id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at FROM api_key`
+
+func (r repository) Create(key APIKey) (APIKey, error) {
+	query := `INSERT INTO api_key (spender_id, name, prefix, key_hash, scopes) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at;`
+	err := r.db.QueryRow(query, key.SpenderID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes)).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (r repository) List(spenderID int) ([]APIKey, error) {
+	rows, err := r.db.Query(selectAPIKeyQuery+` WHERE spender_id = $1 ORDER BY id`, spenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r repository) Revoke(spenderID int, id int) error {
+	query := `UPDATE api_key SET revoked_at = now() WHERE id = $1 AND spender_id = $2 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, id, spenderID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r repository) FindActiveByHash(keyHash string) (APIKey, error) {
+	row := r.db.QueryRow(selectAPIKeyQuery+` WHERE key_hash = $1 AND revoked_at IS NULL`, keyHash)
+	k, err := scanAPIKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return APIKey{}, ErrNotFound
+	}
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return k, nil
+}
+
+// TouchLastUsed records usage at most once a minute per key so that a busy
+// client does not turn every request into a write.
+func (r repository) TouchLastUsed(id int) error {
+	query := `UPDATE api_key SET last_used_at = now() WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (APIKey, error) {
+	var k APIKey
+	err := row.Scan(&k.ID, &k.SpenderID, &k.Name, &k.Prefix, &k.KeyHash, pq.Array(&k.Scopes), &k.CreatedAt, &k.LastUsedAt, &k.RevokedAt)
+	return k, err
+}
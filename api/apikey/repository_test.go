@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindActiveByHash(t *testing.T) {
+	t.Run("should return key with scopes", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows([]string{"id", "spender_id", "name", "prefix", "key_hash", "scopes", "created_at", "last_used_at", "revoked_at"}).
+			AddRow(1, 3, "lambda", "hj_abcdefgh", "hash", "{transactions:create,eslip:write}", createdAt, nil, nil)
+		mock.ExpectQuery(`SELECT (.+) FROM api_key WHERE key_hash = \$1 AND revoked_at IS NULL`).WithArgs("hash").WillReturnRows(rows)
+
+		k, err := repo.FindActiveByHash("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, APIKey{
+			ID: 1, SpenderID: 3, Name: "lambda", Prefix: "hj_abcdefgh", KeyHash: "hash",
+			Scopes: []string{"transactions:create", "eslip:write"}, CreatedAt: createdAt,
+		}, k)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectQuery(`SELECT (.+) FROM api_key`).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindActiveByHash("hash")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     error
+	}{
+		{"revoked own key", 1, nil},
+		{"not found when key belongs to another spender", 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+			repo := NewRepository(db)
+			mock.ExpectExec(`UPDATE api_key SET revoked_at = now\(\) WHERE id = \$1 AND spender_id = \$2 AND revoked_at IS NULL`).
+				WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err = repo.Revoke(3, 1)
+
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(key APIKey) (APIKey, error) {
+	args := m.Called(key)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func (m *MockRepository) List(spenderID int) ([]APIKey, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]APIKey), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindActiveByHash(keyHash string) (APIKey, error) {
+	args := m.Called(keyHash)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func (m *MockRepository) TouchLastUsed(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestService_Create(t *testing.T) {
+	t.Run("should store hash and return plaintext key once", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := NewService(repo)
+		var stored APIKey
+		repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(APIKey)
+		}).Return(APIKey{ID: 1, SpenderID: 3, Name: "lambda"}, nil)
+
+		result, err := s.Create(3, CreateRequest{Name: " lambda ", Scopes: []string{auth.ScopeTransactionsCreate}})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Key, "hj_"))
+		assert.Equal(t, hashKey(result.Key), stored.KeyHash)
+		assert.Equal(t, result.Key[:displayLength], stored.Prefix)
+		assert.Equal(t, "lambda", stored.Name)
+		assert.Equal(t, 3, stored.SpenderID)
+		assert.Equal(t, []string{auth.ScopeTransactionsCreate}, stored.Scopes)
+	})
+
+	t.Run("should validate request", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			request  CreateRequest
+			expected error
+		}{
+			{"missing name", CreateRequest{Scopes: []string{auth.ScopeEslipWrite}}, ErrInvalidName},
+			{"missing scopes", CreateRequest{Name: "lambda"}, ErrInvalidScope},
+			{"unknown scope", CreateRequest{Name: "lambda", Scopes: []string{"everything"}}, ErrInvalidScope},
+			{"non grantable scope", CreateRequest{Name: "lambda", Scopes: []string{auth.ScopeAccountManage}}, ErrInvalidScope},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(MockRepository)
+				s := NewService(repo)
+
+				_, err := s.Create(3, tt.request)
+
+				assert.ErrorIs(t, err, tt.expected)
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+			})
+		}
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	t.Run("should return key and record usage", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := NewService(repo)
+		repo.On("FindActiveByHash", hashKey("hj_secret")).Return(APIKey{ID: 1, SpenderID: 3}, nil)
+		repo.On("TouchLastUsed", 1).Return(nil).Once()
+
+		k, err := s.Authenticate("hj_secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, k.ID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown or revoked key", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := NewService(repo)
+		repo.On("FindActiveByHash", hashKey("hj_secret")).Return(APIKey{}, ErrNotFound)
+
+		_, err := s.Authenticate("hj_secret")
+
+		assert.Equal(t, ErrInvalidKey, err)
+	})
+
+	t.Run("should reject malformed key without querying", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := NewService(repo)
+
+		_, err := s.Authenticate("secret")
+
+		assert.Equal(t, ErrInvalidKey, err)
+		repo.AssertNotCalled(t, "FindActiveByHash", mock.Anything)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := NewService(repo)
+		repo.On("FindActiveByHash", mock.Anything).Return(APIKey{}, errors.New("db down"))
+
+		_, err := s.Authenticate("hj_secret")
+
+		assert.EqualError(t, err, "db down")
+	})
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/labstack/echo/v4"
+)
+
+const bearerScheme = "bearer "
+
+// BearerAuth authenticates requests carrying "Authorization: Bearer <token>".
+// Requests using another scheme pass through untouched so the next
+// authenticator in the chain can handle them.
+func BearerAuth(s Signer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			if len(header) < len(bearerScheme) || !strings.EqualFold(header[:len(bearerScheme)], bearerScheme) {
+				return next(c)
+			}
+
+			id, err := s.Verify(header[len(bearerScheme):])
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return c.JSON(http.StatusUnauthorized, errs.Build(err))
+			}
+
+			SetIdentity(c, id)
+			return next(c)
+		}
+	}
+}
+
+// Authenticated reports whether an earlier middleware already identified the
+// caller. It is used as the Skipper of authenticators later in the chain.
+func Authenticated(c echo.Context) bool {
+	_, ok := IdentityFrom(c)
+	return ok
+}
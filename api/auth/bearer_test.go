@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSigner struct {
+	tokens map[string]Identity
+}
+
+func (s stubSigner) Sign(id Identity) (string, time.Time, error) {
+	return "", time.Time{}, errors.New("not implemented")
+}
+
+func (s stubSigner) Verify(token string) (Identity, error) {
+	id, ok := s.tokens[token]
+	if !ok {
+		return Identity{}, ErrInvalidToken
+	}
+	return id, nil
+}
+
+func TestBearerAuth(t *testing.T) {
+	s := stubSigner{tokens: map[string]Identity{"good": {UserID: 7, SpenderID: 3}}}
+
+	tests := []struct {
+		name           string
+		authorization  string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{"valid token", "Bearer good", http.StatusOK, "3"},
+		{"scheme is case insensitive", "bearer good", http.StatusOK, "3"},
+		{"invalid token", "Bearer bad", http.StatusUnauthorized, ""},
+		{"other scheme passes through", "Basic dXNlcjpzZWNyZXQ=", http.StatusOK, "anonymous"},
+		{"no header passes through", "", http.StatusOK, "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(BearerAuth(s))
+			e.GET("/", func(c echo.Context) error {
+				id, ok := IdentityFrom(c)
+				if !ok {
+					return c.String(http.StatusOK, "anonymous")
+				}
+				return c.String(http.StatusOK, fmt.Sprint(id.SpenderID))
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
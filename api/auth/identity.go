@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+const key = "identity"
+
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserID    int
+	Username  string
+	SpenderID int
+	Role      string
+	// APIKeyID is set when the caller authenticated with an API key, in
+	// which case Scopes lists everything the key may do.
+	APIKeyID int
+	Scopes   []string
+}
+
+// Can reports whether the caller's role grants permission and, for API keys,
+// whether the key was also given it as a scope.
+func (id Identity) Can(permission string) bool {
+	return RoleHas(id.Role, permission) && id.HasScope(permission)
+}
+
+func (id Identity) HasScope(scope string) bool {
+	if id.APIKeyID == 0 {
+		return true
+	}
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func SetIdentity(c echo.Context, id Identity) {
+	c.Set(key, id)
+}
+
+// IdentityFrom returns the caller set by the auth middleware. It reports false
+// when the request has not been authenticated or is not bound to a spender.
+func IdentityFrom(c echo.Context) (Identity, bool) {
+	id, ok := c.Get(key).(Identity)
+	if !ok || id.SpenderID <= 0 {
+		return Identity{}, false
+	}
+
+	return id, true
+}
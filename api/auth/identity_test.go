@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityFrom(t *testing.T) {
+	t.Run("should return identity set on context", func(t *testing.T) {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		SetIdentity(c, Identity{Username: "user", SpenderID: 1})
+
+		id, ok := IdentityFrom(c)
+
+		assert.True(t, ok)
+		assert.Equal(t, Identity{Username: "user", SpenderID: 1}, id)
+	})
+
+	t.Run("should report false when request is not authenticated", func(t *testing.T) {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		_, ok := IdentityFrom(c)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("should report false when identity is not bound to a spender", func(t *testing.T) {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+		SetIdentity(c, Identity{Username: "user"})
+
+		_, ok := IdentityFrom(c)
+
+		assert.False(t, ok)
+	})
+}
@@ -4,9 +4,22 @@ import (
 	"crypto/subtle"
 )
 
-func Check(username, password string) bool {
-	isUserValid := subtle.ConstantTimeCompare([]byte(username), []byte("user")) == 1
-	isPassValid := subtle.ConstantTimeCompare([]byte(password), []byte("secret")) == 1
+const (
+	legacyUsername  = "user"
+	legacyPassword  = "secret"
+	legacySpenderID = 1
+)
+
+// Check validates the workshop credential and returns the identity bound to
+// its spender row. The credential is shared and well known, so it only acts
+// as that spender; admin access comes from users and scoped API keys.
+func Check(username, password string) (Identity, bool) {
+	isUserValid := subtle.ConstantTimeCompare([]byte(username), []byte(legacyUsername)) == 1
+	isPassValid := subtle.ConstantTimeCompare([]byte(password), []byte(legacyPassword)) == 1
+
+	if !isUserValid || !isPassValid {
+		return Identity{}, false
+	}
 
-	return isUserValid && isPassValid
+	return Identity{Username: username, SpenderID: legacySpenderID, Role: RoleSpender}, true
 }
@@ -10,12 +10,19 @@ func TestCheckUsernameAndPassword(t *testing.T) {
 	}{
 		{"user", "secret", true},
 		{"user", "wrong-secret", false},
+		{"wrong-user", "secret", false},
 	}
 
 	for _, tc := range cases {
-		got := Check(tc.username, tc.password)
+		id, got := Check(tc.username, tc.password)
 		if got != tc.want {
 			t.Errorf("Check(%s, %s) = %v; want %v", tc.username, tc.password, got, tc.want)
 		}
+		if got && id.SpenderID != legacySpenderID {
+			t.Errorf("Check(%s, %s) spender = %d; want %d", tc.username, tc.password, id.SpenderID, legacySpenderID)
+		}
+		if got && id.Role != RoleSpender {
+			t.Errorf("Check(%s, %s) role = %q; want %q", tc.username, tc.password, id.Role, RoleSpender)
+		}
 	}
 }
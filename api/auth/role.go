@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	RoleAdmin   = "admin"
+	RoleSpender = "spender"
+	// RoleService is held by API keys. What a key may actually do is further
+	// narrowed by its scopes.
+	RoleService = "service"
+)
+
+// PermTransactionsReadAny lets the caller list transactions of every spender
+// instead of only its own.
+const PermTransactionsReadAny = "transactions:read:any"
+
+// PermSpendersSelf lets the caller read and edit its own spender profile.
+const PermSpendersSelf = "spenders:self"
+
+// PermExchangeRatesWrite lets the caller load and correct the exchange rates
+// every spender's reports are converted with.
+const PermExchangeRatesWrite = "exchange_rates:write"
+
+// Permissions are named like scopes so a route declares one requirement that
+// covers both roles and API key scopes.
+var rolePermissions = map[string][]string{
+	RoleAdmin: {
+		ScopeTransactionsRead,
+		ScopeTransactionsCreate,
+		ScopeTransactionsWrite,
+		PermTransactionsReadAny,
+		ScopeEslipRead,
+		ScopeEslipWrite,
+		ScopeSpendersRead,
+		ScopeSpendersWrite,
+		PermSpendersSelf,
+		ScopeAccountManage,
+		PermExchangeRatesWrite,
+	},
+	RoleSpender: {
+		ScopeTransactionsRead,
+		ScopeTransactionsCreate,
+		ScopeTransactionsWrite,
+		ScopeEslipRead,
+		ScopeEslipWrite,
+		PermSpendersSelf,
+		ScopeAccountManage,
+	},
+	RoleService: {
+		ScopeTransactionsRead,
+		ScopeTransactionsCreate,
+		ScopeTransactionsWrite,
+		ScopeEslipRead,
+		ScopeEslipWrite,
+	},
+}
+
+func RoleHas(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Require rejects callers whose role, or API key scopes, do not grant
+// permission. Denials are logged with the caller so they can be audited.
+func Require(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok := IdentityFrom(c)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, errs.Build(ErrUnauthenticated))
+			}
+			if !id.Can(permission) {
+				return deny(c, id, permission)
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireSelfOr lets a caller with PermSpendersSelf act on the spender named
+// by the :id path parameter when it is its own, and otherwise requires
+// permission.
+func RequireSelfOr(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok := IdentityFrom(c)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, errs.Build(ErrUnauthenticated))
+			}
+			self := c.Param("id") == strconv.Itoa(id.SpenderID) && id.Can(PermSpendersSelf)
+			if !self && !id.Can(permission) {
+				return deny(c, id, permission)
+			}
+			return next(c)
+		}
+	}
+}
+
+func deny(c echo.Context, id Identity, permission string) error {
+	mlog.L(c).Warn("permission denied",
+		zap.String("permission", permission),
+		zap.String("method", c.Request().Method),
+		zap.String("path", c.Path()),
+		zap.Int("user_id", id.UserID),
+		zap.String("username", id.Username),
+		zap.Int("spender_id", id.SpenderID),
+		zap.String("role", id.Role),
+		zap.Int("api_key_id", id.APIKeyID),
+	)
+	return c.JSON(http.StatusForbidden, errs.Build(ErrForbidden))
+}
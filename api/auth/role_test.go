@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name           string
+		permission     string
+		identity       *Identity
+		wantStatusCode int
+	}{
+		{"spender within role", ScopeTransactionsCreate, &Identity{UserID: 7, SpenderID: 3, Role: RoleSpender}, http.StatusOK},
+		{"spender outside role", ScopeSpendersRead, &Identity{UserID: 7, SpenderID: 3, Role: RoleSpender}, http.StatusForbidden},
+		{"admin", ScopeSpendersRead, &Identity{UserID: 1, SpenderID: 1, Role: RoleAdmin}, http.StatusOK},
+		{"identity without role", ScopeTransactionsRead, &Identity{UserID: 7, SpenderID: 3}, http.StatusForbidden},
+		{"api key with scope", ScopeTransactionsCreate, &Identity{SpenderID: 3, Role: RoleService, APIKeyID: 1, Scopes: []string{ScopeTransactionsCreate}}, http.StatusOK},
+		{"api key without scope", ScopeTransactionsCreate, &Identity{SpenderID: 3, Role: RoleService, APIKeyID: 1, Scopes: []string{ScopeEslipWrite}}, http.StatusForbidden},
+		{"api key scope beyond role", ScopeSpendersRead, &Identity{SpenderID: 3, Role: RoleService, APIKeyID: 1, Scopes: []string{ScopeSpendersRead}}, http.StatusForbidden},
+		{"not authenticated", ScopeTransactionsRead, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.identity != nil {
+				SetIdentity(c, *tt.identity)
+			}
+
+			h := Require(tt.permission)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			err := h(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+		})
+	}
+}
+
+func TestRequireSelfOr(t *testing.T) {
+	tests := []struct {
+		name           string
+		param          string
+		identity       Identity
+		wantStatusCode int
+	}{
+		{"spender on own profile", "3", Identity{UserID: 7, SpenderID: 3, Role: RoleSpender}, http.StatusOK},
+		{"spender on another profile", "4", Identity{UserID: 7, SpenderID: 3, Role: RoleSpender}, http.StatusForbidden},
+		{"admin on another profile", "4", Identity{UserID: 1, SpenderID: 1, Role: RoleAdmin}, http.StatusOK},
+		{"api key on own spender", "3", Identity{SpenderID: 3, Role: RoleService, APIKeyID: 1}, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.param)
+			SetIdentity(c, tt.identity)
+
+			h := RequireSelfOr(ScopeSpendersRead)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			err := h(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+		})
+	}
+}
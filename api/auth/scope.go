@@ -0,0 +1,33 @@
+package auth
+
+const (
+	ScopeTransactionsRead   = "transactions:read"
+	ScopeTransactionsCreate = "transactions:create"
+	ScopeTransactionsWrite  = "transactions:write"
+	ScopeEslipRead          = "eslip:read"
+	ScopeEslipWrite         = "eslip:write"
+	ScopeSpendersRead       = "spenders:read"
+	ScopeSpendersWrite      = "spenders:write"
+	// ScopeAccountManage guards credential management (passwords, API keys).
+	// It is deliberately not grantable so a leaked key cannot mint new keys.
+	ScopeAccountManage = "account:manage"
+)
+
+// GrantableScopes are the scopes an API key may be created with. They are
+// limited to what RoleService can do, so a key never outranks its role.
+var GrantableScopes = []string{
+	ScopeTransactionsRead,
+	ScopeTransactionsCreate,
+	ScopeTransactionsWrite,
+	ScopeEslipRead,
+	ScopeEslipWrite,
+}
+
+func IsGrantable(scope string) bool {
+	for _, s := range GrantableScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGrantable(t *testing.T) {
+	assert.True(t, IsGrantable(ScopeTransactionsCreate))
+	assert.False(t, IsGrantable(ScopeAccountManage))
+	assert.False(t, IsGrantable(ScopeSpendersRead))
+	assert.False(t, IsGrantable("unknown"))
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	issuer = "hongjot"
+	// minKeyLength follows RFC 7518: an HS256 key must be at least as long as
+	// the hash output.
+	minKeyLength = 32
+	ephemeralKID = "ephemeral"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired access token")
+	ErrNoSigningKeys = errors.New("no JWT signing keys configured")
+)
+
+type Claims struct {
+	Username  string `json:"username"`
+	SpenderID int    `json:"spender_id"`
+	Role      string `json:"role"`
+	jwt.StandardClaims
+}
+
+// Signer issues and verifies short-lived access tokens.
+type Signer interface {
+	Sign(id Identity) (string, time.Time, error)
+	Verify(token string) (Identity, error)
+}
+
+type signer struct {
+	keys      map[string][]byte
+	activeKID string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+func NewSigner(cfg config.Auth) (Signer, error) {
+	if len(cfg.JWTKeys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+
+	keys := make(map[string][]byte, len(cfg.JWTKeys))
+	for kid, secret := range cfg.JWTKeys {
+		if len(secret) < minKeyLength {
+			return nil, fmt.Errorf("JWT key %q must be at least %d bytes", kid, minKeyLength)
+		}
+		keys[kid] = []byte(secret)
+	}
+
+	if _, ok := keys[cfg.JWTActiveKeyID]; !ok {
+		return nil, fmt.Errorf("active JWT key %q is not in AUTH_JWT_KEYS", cfg.JWTActiveKeyID)
+	}
+
+	return signer{keys: keys, activeKID: cfg.JWTActiveKeyID, ttl: cfg.AccessTokenTTL, now: time.Now}, nil
+}
+
+// NewEphemeralSigner signs with a random key that only lives as long as the
+// process. It lets a local server run without AUTH_JWT_KEYS; every restart
+// invalidates the tokens it issued.
+func NewEphemeralSigner(ttl time.Duration) (Signer, error) {
+	key := make([]byte, minKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return signer{keys: map[string][]byte{ephemeralKID: key}, activeKID: ephemeralKID, ttl: ttl, now: time.Now}, nil
+}
+
+func (s signer) Sign(id Identity) (string, time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.ttl)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		Username:  id.Username,
+		SpenderID: id.SpenderID,
+		Role:      id.Role,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   strconv.Itoa(id.UserID),
+			Issuer:    issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	})
+	token.Header["kid"] = s.activeKID
+
+	signed, err := token.SignedString(s.keys[s.activeKID])
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (s signer) Verify(tokenString string) (Identity, error) {
+	// Claims are validated below against s.now rather than the package-level
+	// jwt.TimeFunc.
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Alg()}, SkipClaimsValidation: true}
+	claims := Claims{}
+
+	token, err := parser.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	})
+	if err != nil || !token.Valid {
+		return Identity{}, ErrInvalidToken
+	}
+
+	if !claims.VerifyIssuer(issuer, true) || !claims.VerifyExpiresAt(s.now().Unix(), true) {
+		return Identity{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	return Identity{UserID: userID, Username: claims.Username, SpenderID: claims.SpenderID, Role: claims.Role}, nil
+}
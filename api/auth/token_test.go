@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	newSecret = "new-secret-new-secret-new-secret"
+	oldSecret = "old-secret-old-secret-old-secret"
+)
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.Auth
+		wantErr bool
+	}{
+		{"valid", config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "new"}, false},
+		{"no keys", config.Auth{}, true},
+		{"short key", config.Auth{JWTKeys: map[string]string{"new": "short"}, JWTActiveKeyID: "new"}, true},
+		{"unknown active key", config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "other"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSigner(tt.cfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestSigner_SignAndVerify(t *testing.T) {
+	id := Identity{UserID: 7, Username: "hongjot", SpenderID: 3, Role: RoleSpender}
+
+	t.Run("should verify token it signed", func(t *testing.T) {
+		s, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "new", AccessTokenTTL: time.Minute})
+
+		token, expiresAt, err := s.Sign(id)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), expiresAt, time.Second)
+
+		got, err := s.Verify(token)
+		assert.NoError(t, err)
+		assert.Equal(t, id, got)
+	})
+
+	t.Run("should verify token signed with a rotated-out key", func(t *testing.T) {
+		before, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"old": oldSecret}, JWTActiveKeyID: "old", AccessTokenTTL: time.Minute})
+		after, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"new": newSecret, "old": oldSecret}, JWTActiveKeyID: "new", AccessTokenTTL: time.Minute})
+
+		token, _, _ := before.Sign(id)
+
+		got, err := after.Verify(token)
+		assert.NoError(t, err)
+		assert.Equal(t, id, got)
+	})
+
+	t.Run("should reject token signed with a removed key", func(t *testing.T) {
+		before, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"old": oldSecret}, JWTActiveKeyID: "old", AccessTokenTTL: time.Minute})
+		after, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "new", AccessTokenTTL: time.Minute})
+
+		token, _, _ := before.Sign(id)
+
+		_, err := after.Verify(token)
+		assert.Equal(t, ErrInvalidToken, err)
+	})
+
+	t.Run("should reject expired token", func(t *testing.T) {
+		s, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "new", AccessTokenTTL: time.Minute})
+		token, _, _ := s.Sign(id)
+
+		later := s.(signer)
+		later.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		_, err := later.Verify(token)
+		assert.Equal(t, ErrInvalidToken, err)
+	})
+
+	t.Run("should reject token using another algorithm", func(t *testing.T) {
+		s, _ := NewSigner(config.Auth{JWTKeys: map[string]string{"new": newSecret}, JWTActiveKeyID: "new", AccessTokenTTL: time.Minute})
+		token := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{SpenderID: 3})
+		token.Header["kid"] = "new"
+		unsigned, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+		_, err := s.Verify(unsigned)
+		assert.Equal(t, ErrInvalidToken, err)
+	})
+}
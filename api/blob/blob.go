@@ -0,0 +1,77 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/google/uuid"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// keyPattern keeps keys to a single, harmless path segment so no backend
+// can be tricked into escaping its directory or bucket prefix.
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+var extPattern = regexp.MustCompile(`^\.[a-z0-9]{1,8}$`)
+
+type Object struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// BlobStore stores opaque objects under flat keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (Object, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (Object, error)
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// New returns the backend selected by cfg.Backend.
+func New(cfg config.Blob) (BlobStore, error) {
+	switch cfg.Backend {
+	case BackendLocal:
+		return NewLocal(cfg.LocalDir)
+	case BackendS3:
+		return NewS3(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown blob backend %q, want %s or %s", cfg.Backend, BackendLocal, BackendS3)
+}
+
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// NewKey returns a random key that keeps ext when it is a plain file
+// extension. Client file names are never used as keys.
+func NewKey(ext string) string {
+	ext = strings.ToLower(ext)
+	if !extPattern.MatchString(ext) {
+		ext = ""
+	}
+	return uuid.NewString() + ext
+}
@@ -0,0 +1,137 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type local struct {
+	dir string
+}
+
+// NewLocal stores objects as files in dir, creating it when missing.
+func NewLocal(dir string) (BlobStore, error) {
+	if dir == "" {
+		return nil, errors.New("local blob directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return local{dir: dir}, nil
+}
+
+// Put writes to a temporary file first so readers never see a partial
+// object.
+func (l local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (Object, error) {
+	if !ValidKey(key) {
+		return Object{}, ErrInvalidKey
+	}
+
+	tmp, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return Object{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return Object{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return Object{}, err
+	}
+	if err := os.Rename(tmp.Name(), l.path(key)); err != nil {
+		return Object{}, err
+	}
+
+	return l.Stat(ctx, key)
+}
+
+func (l local) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	obj, err := l.Stat(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	f, err := os.Open(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, Object{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	return f, obj, nil
+}
+
+func (l local) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	err := os.Remove(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (l local) Stat(ctx context.Context, key string) (Object, error) {
+	if !ValidKey(key) {
+		return Object{}, ErrInvalidKey
+	}
+
+	info, err := os.Stat(l.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	return l.object(key, info), nil
+}
+
+func (l local) List(ctx context.Context, prefix string) ([]Object, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []Object{}
+	for _, entry := range entries {
+		key := entry.Name()
+		if entry.IsDir() || !ValidKey(key) || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, l.object(key, info))
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	return objects, nil
+}
+
+func (l local) path(key string) string {
+	return filepath.Join(l.dir, key)
+}
+
+// object derives the content type from the key's extension, which NewKey
+// keeps, instead of storing it alongside the file.
+func (l local) object(key string, info fs.FileInfo) Object {
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Object{Key: key, Size: info.Size(), ContentType: contentType, ModTime: info.ModTime()}
+}
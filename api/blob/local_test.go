@@ -0,0 +1,67 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	obj, err := store.Put(ctx, "a.png", strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+	assert.Equal(t, Object{Key: "a.png", Size: 3, ContentType: "image/png", ModTime: obj.ModTime}, obj)
+	_, err = store.Put(ctx, "b.pdf", strings.NewReader("pdf"), 3, "application/pdf")
+	require.NoError(t, err)
+
+	body, obj, err := store.Get(ctx, "a.png")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png", string(content))
+	assert.Equal(t, int64(3), obj.Size)
+
+	objects, err := store.List(ctx, "")
+	require.NoError(t, err)
+	if assert.Len(t, objects, 2) {
+		assert.Equal(t, "a.png", objects[0].Key)
+		assert.Equal(t, "b.pdf", objects[1].Key)
+	}
+	objects, err = store.List(ctx, "b")
+	require.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	assert.NoError(t, store.Delete(ctx, "a.png"))
+	_, err = store.Stat(ctx, "a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "a.png"), ErrNotFound)
+	_, _, err = store.Get(ctx, "a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocal_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "..", "../a.png", "a/b.png", ".hidden"} {
+		_, err := store.Put(ctx, key, strings.NewReader("x"), 1, "")
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+		_, err = store.Stat(ctx, key)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	assert.True(t, strings.HasSuffix(NewKey(".JPG"), ".jpg"))
+	assert.True(t, ValidKey(NewKey(".jpg")))
+	assert.NotContains(t, NewKey("./../x"), ".")
+	assert.NotEqual(t, NewKey(".png"), NewKey(".png"))
+}
@@ -0,0 +1,232 @@
+package blob
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type S3Config struct {
+	// Endpoint is the base URL of the S3-compatible service, e.g.
+	// "http://localhost:9000" for a local MinIO. Buckets are addressed
+	// path-style: <endpoint>/<bucket>/<key>.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+type s3 struct {
+	endpoint *url.URL
+	bucket   string
+	signer   sigv4
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3(cfg S3Config) (BlobStore, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return s3{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		signer: sigv4{
+			accessKeyID:     cfg.AccessKeyID,
+			secretAccessKey: cfg.SecretAccessKey,
+			region:          region,
+			service:         "s3",
+		},
+		client: &http.Client{Timeout: 30 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+func (s s3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (Object, error) {
+	if !ValidKey(key) {
+		return Object{}, ErrInvalidKey
+	}
+
+	req, err := s.request(ctx, http.MethodPut, key, nil, r)
+	if err != nil {
+		return Object{}, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return Object{}, err
+	}
+	resp.Body.Close()
+
+	return Object{Key: key, Size: size, ContentType: contentType, ModTime: s.now()}, nil
+}
+
+func (s s3) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	if !ValidKey(key) {
+		return nil, Object{}, ErrInvalidKey
+	}
+
+	req, err := s.request(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	return resp.Body, objectFromHeader(key, resp), nil
+}
+
+func (s s3) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	// S3 answers a DELETE of a missing key with 204, so check first to keep
+	// the same contract as the local backend.
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+
+	req, err := s.request(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s s3) Stat(ctx context.Context, key string) (Object, error) {
+	if !ValidKey(key) {
+		return Object{}, ErrInvalidKey
+	}
+
+	req, err := s.request(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return Object{}, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return Object{}, err
+	}
+	resp.Body.Close()
+
+	return objectFromHeader(key, resp), nil
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2. Content types are not part of the
+// listing and are left empty.
+func (s s3) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects := []Object{}
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := s.request(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, Object{Key: c.Key, Size: c.Size, ModTime: c.LastModified})
+		}
+		if !result.IsTruncated {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s s3) request(ctx context.Context, method, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s s3) do(req *http.Request) (*http.Response, error) {
+	s.signer.sign(req, unsignedPayload, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, msg)
+	}
+
+	return resp, nil
+}
+
+func objectFromHeader(key string, resp *http.Response) Object {
+	size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	if err != nil {
+		size = resp.ContentLength
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return Object{
+		Key:         key,
+		Size:        size,
+		ContentType: resp.Header.Get("Content-Type"),
+		ModTime:     modTime,
+	}
+}
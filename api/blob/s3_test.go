@@ -0,0 +1,110 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 serves a single bucket from memory, just enough of the S3 API for
+// the backend.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key = strings.TrimPrefix(key, "/")
+
+	if key == "" && r.Method == http.MethodGet {
+		prefix := r.URL.Query().Get("prefix")
+		fmt.Fprint(w, "<ListBucketResult>")
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2024-01-01T00:00:00Z</LastModified></Contents>", k, len(v))
+			}
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeS3{bucket: "slips", objects: map[string][]byte{}, types: map[string]string{}})
+	defer server.Close()
+	store, err := NewS3(S3Config{Endpoint: server.URL, Bucket: "slips", AccessKeyID: "key", SecretAccessKey: "secret"})
+	require.NoError(t, err)
+
+	_, err = store.Put(ctx, "a.png", strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+
+	body, obj, err := store.Get(ctx, "a.png")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "png", string(content))
+	assert.Equal(t, "image/png", obj.ContentType)
+	assert.Equal(t, int64(3), obj.Size)
+
+	objects, err := store.List(ctx, "a")
+	require.NoError(t, err)
+	if assert.Len(t, objects, 1) {
+		assert.Equal(t, "a.png", objects[0].Key)
+	}
+
+	assert.NoError(t, store.Delete(ctx, "a.png"))
+	_, err = store.Stat(ctx, "a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "a.png"), ErrNotFound)
+}
+
+func TestNewS3_RequiresEndpointAndBucket(t *testing.T) {
+	_, err := NewS3(S3Config{Bucket: "slips"})
+	assert.Error(t, err)
+	_, err = NewS3(S3Config{Endpoint: "http://localhost:9000"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package budget
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// Periods of a Budget. Monthly and weekly budgets repeat every calendar
+// month or week (weeks start on Monday) from StartDate; a custom budget
+// covers StartDate to EndDate once.
+const (
+	PeriodMonthly = "monthly"
+	PeriodWeekly  = "weekly"
+	PeriodCustom  = "custom"
+)
+
+// Flags of a Status.
+const (
+	FlagOK        = "ok"
+	FlagNearLimit = "near_limit"
+	FlagOverLimit = "over_limit"
+)
+
+// NearLimitPercent is the share of the limit from which a budget is flagged
+// as near its limit.
+const NearLimitPercent = 80
+
+const dateLayout = "2006-01-02"
+
+// Budget limits what a spender spends in a period, on one category and its
+// subcategories or, without a CategoryID, overall. Dates are YYYY-MM-DD on
+// the spender's calendar; EndDate is optional for repeating budgets. With
+// Rollover, what was left of a repeating budget in the previous period is
+// added to the limit of the current one.
+type Budget struct {
+	ID         int          `json:"id"`
+	SpenderID  int          `json:"spender_id"`
+	CategoryID *int         `json:"category_id"`
+	Period     string       `json:"period"`
+	Amount     money.Amount `json:"amount"`
+	StartDate  string       `json:"start_date"`
+	EndDate    *string      `json:"end_date"`
+	Rollover   bool         `json:"rollover"`
+}
+
+// Request creates or replaces a budget. StartDate defaults to the start of
+// the current period for repeating budgets.
+type Request struct {
+	CategoryID *int         `json:"category_id"`
+	Period     string       `json:"period"`
+	Amount     money.Amount `json:"amount"`
+	StartDate  string       `json:"start_date"`
+	EndDate    *string      `json:"end_date"`
+	Rollover   bool         `json:"rollover"`
+}
+
+// Status is where a budget stands in the period containing a day. Spent
+// covers the period up to that day and Projected extends its daily average
+// to the whole period. Limit is Amount plus what RolledOver from the
+// previous period.
+type Status struct {
+	Budget
+	PeriodStart string       `json:"period_start"`
+	PeriodEnd   string       `json:"period_end"`
+	RolledOver  money.Amount `json:"rolled_over"`
+	Limit       money.Amount `json:"limit"`
+	Spent       money.Amount `json:"spent"`
+	Remaining   money.Amount `json:"remaining"`
+	Projected   money.Amount `json:"projected"`
+	Flag        string       `json:"flag"`
+}
+
+var (
+	ErrNotFound        = errors.New("budget not found")
+	ErrInvalidPeriod   = errors.New("period must be monthly, weekly or custom")
+	ErrInvalidAmount   = errors.New("amount must be greater than 0")
+	ErrInvalidDate     = errors.New("dates must be formatted as YYYY-MM-DD")
+	ErrInvalidRange    = errors.New("custom budgets need start_date and end_date, and end_date must not be before start_date")
+	ErrInvalidRollover = errors.New("rollover applies to monthly and weekly budgets only")
+	ErrInvalidCategory = errors.New("category_id must be a system category or one of your own")
+)
+
+// period returns the period of the budget containing day, a midnight in
+// loc, and whether the budget applies on that day. The first period of a
+// repeating budget starts on StartDate rather than at the start of its
+// month or week, so nothing spent before the budget existed counts.
+func (b Budget) period(day time.Time, loc *time.Location) (start, end time.Time, ok bool) {
+	first, err := time.ParseInLocation(dateLayout, b.StartDate, loc)
+	if err != nil || day.Before(first) {
+		return time.Time{}, time.Time{}, false
+	}
+	if b.EndDate != nil {
+		last, err := time.ParseInLocation(dateLayout, *b.EndDate, loc)
+		if err != nil || day.After(last) {
+			return time.Time{}, time.Time{}, false
+		}
+		if b.Period == PeriodCustom {
+			return first, last, true
+		}
+	}
+
+	start, end = calendarPeriod(b.Period, day)
+	if start.Before(first) {
+		start = first
+	}
+	return start, end, true
+}
+
+// calendarPeriod returns the first and last day of the month or week
+// containing day.
+func calendarPeriod(period string, day time.Time) (time.Time, time.Time) {
+	if period == PeriodWeekly {
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 6)
+	}
+	start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	return start, start.AddDate(0, 1, -1)
+}
+
+// days counts the calendar days from start to end inclusive. Rounding keeps
+// days that are 23 or 25 hours long around a DST change whole.
+func days(start, end time.Time) int {
+	return int(math.Round(end.Sub(start).Hours()/24)) + 1
+}
@@ -0,0 +1,167 @@
+package budget
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	List(c echo.Context) error
+	Get(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+	Status(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) List(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	result, err := h.service.List(caller.SpenderID)
+	if err != nil {
+		mlog.L(c).Error("list budgets error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Get(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid budget ID"})
+	}
+
+	result, err := h.service.Get(caller.SpenderID, id)
+	if err != nil {
+		return h.fail(c, "get budget error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Create(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var request Request
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Create(caller.SpenderID, request)
+	if err != nil {
+		return h.fail(c, "create budget error", err)
+	}
+
+	mlog.L(c).Info("create budget successfully", zap.Int("id", result.ID))
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h handler) Update(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid budget ID"})
+	}
+
+	var request Request
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Update(caller.SpenderID, id, request)
+	if err != nil {
+		return h.fail(c, "update budget error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Delete(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid budget ID"})
+	}
+
+	if err := h.service.Delete(caller.SpenderID, id); err != nil {
+		return h.fail(c, "delete budget error", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Status reports the caller's budgets on the day given by the date query
+// parameter, today by default.
+func (h handler) Status(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var day *time.Time
+	if param := c.QueryParam("date"); param != "" {
+		d, err := time.Parse(dateLayout, param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidDate))
+		}
+		day = &d
+	}
+
+	result, err := h.service.Status(caller.SpenderID, day)
+	if err != nil {
+		return h.fail(c, "budget status error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	case errors.Is(err, ErrInvalidPeriod), errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrInvalidDate),
+		errors.Is(err, ErrInvalidRange), errors.Is(err, ErrInvalidRollover), errors.Is(err, ErrInvalidCategory):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	default:
+		mlog.L(c).Error(msg, zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+}
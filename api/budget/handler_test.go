@@ -0,0 +1,197 @@
+package budget
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(spenderID int) ([]Budget, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]Budget), args.Error(1)
+}
+
+func (m *MockService) Get(spenderID int, id int) (Budget, error) {
+	args := m.Called(spenderID, id)
+	return args.Get(0).(Budget), args.Error(1)
+}
+
+func (m *MockService) Create(spenderID int, request Request) (Budget, error) {
+	args := m.Called(spenderID, request)
+	return args.Get(0).(Budget), args.Error(1)
+}
+
+func (m *MockService) Update(spenderID int, id int, request Request) (Budget, error) {
+	args := m.Called(spenderID, id, request)
+	return args.Get(0).(Budget), args.Error(1)
+}
+
+func (m *MockService) Delete(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func (m *MockService) Status(spenderID int, day *time.Time) ([]Status, error) {
+	args := m.Called(spenderID, day)
+	return args.Get(0).([]Status), args.Error(1)
+}
+
+func newAuthenticatedContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3})
+	return c, rec
+}
+
+func TestHandler_Create(t *testing.T) {
+	request := Request{Period: PeriodMonthly, Amount: 5000_00, Rollover: true}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid period", ErrInvalidPeriod, http.StatusBadRequest},
+		{"invalid amount", ErrInvalidAmount, http.StatusBadRequest},
+		{"invalid category", ErrInvalidCategory, http.StatusBadRequest},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, "/budgets", `{"period": "monthly", "amount": 5000, "rollover": true}`)
+			mockService := new(MockService)
+			mockService.On("Create", 3, request).Return(Budget{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Create_RejectsFractionsOfSatang(t *testing.T) {
+	c, rec := newAuthenticatedContext(http.MethodPost, "/budgets", `{"period": "monthly", "amount": 19.999}`)
+	mockService := new(MockService)
+
+	err := NewHandler(mockService).Create(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockError      error
+		expectedStatus int
+	}{
+		{"updated", "1", nil, http.StatusOK},
+		{"not found", "1", ErrNotFound, http.StatusNotFound},
+		{"invalid range", "1", ErrInvalidRange, http.StatusBadRequest},
+		{"invalid id", "abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPut, "/budgets/"+tt.id, `{"period": "weekly", "amount": 800}`)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			mockService := new(MockService)
+			mockService.On("Update", 3, 1, Request{Period: PeriodWeekly, Amount: 800_00}).Return(Budget{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Update(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_GetListAndDelete(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("List", 3).Return([]Budget{{ID: 1, SpenderID: 3}}, nil)
+	mockService.On("Get", 3, 2).Return(Budget{}, ErrNotFound)
+	mockService.On("Delete", 3, 1).Return(nil)
+	h := NewHandler(mockService)
+
+	listCtx, listRec := newAuthenticatedContext(http.MethodGet, "/budgets", "")
+	getCtx, getRec := newAuthenticatedContext(http.MethodGet, "/budgets/2", "")
+	getCtx.SetParamNames("id")
+	getCtx.SetParamValues("2")
+	deleteCtx, deleteRec := newAuthenticatedContext(http.MethodDelete, "/budgets/1", "")
+	deleteCtx.SetParamNames("id")
+	deleteCtx.SetParamValues("1")
+
+	assert.NoError(t, h.List(listCtx))
+	assert.NoError(t, h.Get(getCtx))
+	assert.NoError(t, h.Delete(deleteCtx))
+
+	assert.Equal(t, http.StatusOK, listRec.Code)
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+	assert.Equal(t, http.StatusNoContent, deleteRec.Code)
+}
+
+func TestHandler_Status(t *testing.T) {
+	t.Run("on a given day", func(t *testing.T) {
+		c, rec := newAuthenticatedContext(http.MethodGet, "/budgets/status?date=2024-05-10", "")
+		day := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)
+		mockService := new(MockService)
+		mockService.On("Status", 3, &day).Return([]Status{{
+			Budget:      Budget{ID: 1, SpenderID: 3, Period: PeriodMonthly, Amount: 5000_00, StartDate: "2024-05-01"},
+			PeriodStart: "2024-05-01", PeriodEnd: "2024-05-31",
+			Limit: 5000_00, Spent: 4200_00, Remaining: 800_00, Projected: 13020_00, Flag: FlagNearLimit,
+		}}, nil)
+
+		err := NewHandler(mockService).Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{
+			"id": 1, "spender_id": 3, "category_id": null, "period": "monthly", "amount": 5000,
+			"start_date": "2024-05-01", "end_date": null, "rollover": false,
+			"period_start": "2024-05-01", "period_end": "2024-05-31", "rolled_over": 0,
+			"limit": 5000, "spent": 4200, "remaining": 800, "projected": 13020, "flag": "near_limit"
+		}]`, rec.Body.String())
+	})
+
+	t.Run("today by default", func(t *testing.T) {
+		c, rec := newAuthenticatedContext(http.MethodGet, "/budgets/status", "")
+		mockService := new(MockService)
+		mockService.On("Status", 3, (*time.Time)(nil)).Return([]Status{}, nil)
+
+		err := NewHandler(mockService).Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[]`, rec.Body.String())
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		c, rec := newAuthenticatedContext(http.MethodGet, "/budgets/status?date=10-05-2024", "")
+
+		err := NewHandler(new(MockService)).Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
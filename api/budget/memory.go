@@ -0,0 +1,82 @@
+package budget
+
+import (
+	"sort"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu      sync.Mutex
+	budgets map[int]Budget
+	nextID  int
+}
+
+// NewMemoryRepository keeps budgets in process memory for the
+// --storage=memory server mode.
+func NewMemoryRepository() Repository {
+	return &memoryRepository{budgets: map[int]Budget{}, nextID: 1}
+}
+
+func (r *memoryRepository) List(spenderID int) ([]Budget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budgets := []Budget{}
+	for _, b := range r.budgets {
+		if b.SpenderID == spenderID {
+			budgets = append(budgets, b)
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool { return budgets[i].ID < budgets[j].ID })
+
+	return budgets, nil
+}
+
+func (r *memoryRepository) Get(spenderID int, id int) (Budget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.budgets[id]
+	if !ok || b.SpenderID != spenderID {
+		return Budget{}, ErrNotFound
+	}
+
+	return b, nil
+}
+
+func (r *memoryRepository) Create(b Budget) (Budget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b.ID = r.nextID
+	r.nextID++
+	r.budgets[b.ID] = b
+
+	return b, nil
+}
+
+func (r *memoryRepository) Update(b Budget) (Budget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.budgets[b.ID]
+	if !ok || current.SpenderID != b.SpenderID {
+		return Budget{}, ErrNotFound
+	}
+	r.budgets[b.ID] = b
+
+	return b, nil
+}
+
+func (r *memoryRepository) Delete(spenderID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.budgets[id]
+	if !ok || b.SpenderID != spenderID {
+		return ErrNotFound
+	}
+	delete(r.budgets, id)
+
+	return nil
+}
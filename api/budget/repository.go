@@ -0,0 +1,115 @@
+package budget
+
+import (
+	"database/sql"
+)
+
+// Repository stores budgets. Every method is scoped to one spender;
+// budgets of another spender are reported as ErrNotFound.
+type Repository interface {
+	List(spenderID int) ([]Budget, error)
+	Get(spenderID int, id int) (Budget, error)
+	Create(b Budget) (Budget, error)
+	Update(b Budget) (Budget, error)
+	Delete(spenderID int, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	columns    = `id, spender_id, category_id, period, amount, start_date::text, end_date::text, rollover`
+	listStmt   = `SELECT ` + columns + ` FROM budget WHERE spender_id = $1 ORDER BY id`
+	getStmt    = `SELECT ` + columns + ` FROM budget WHERE id = $1 AND spender_id = $2`
+	createStmt = `INSERT INTO budget (spender_id, category_id, period, amount, start_date, end_date, rollover) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	updateStmt = `UPDATE budget SET category_id = $1, period = $2, amount = $3, start_date = $4, end_date = $5, rollover = $6 WHERE id = $7 AND spender_id = $8`
+	deleteStmt = `DELETE FROM budget WHERE id = $1 AND spender_id = $2`
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBudget(row scanner) (Budget, error) {
+	var b Budget
+	err := row.Scan(&b.ID, &b.SpenderID, &b.CategoryID, &b.Period, &b.Amount, &b.StartDate, &b.EndDate, &b.Rollover)
+	return b, err
+}
+
+func (r repository) List(spenderID int) ([]Budget, error) {
+	rows, err := r.db.Query(listStmt, spenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []Budget{}
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+
+	return budgets, rows.Err()
+}
+
+func (r repository) Get(spenderID int, id int) (Budget, error) {
+	b, err := scanBudget(r.db.QueryRow(getStmt, id, spenderID))
+	if err == sql.ErrNoRows {
+		return Budget{}, ErrNotFound
+	}
+	if err != nil {
+		return Budget{}, err
+	}
+
+	return b, nil
+}
+
+func (r repository) Create(b Budget) (Budget, error) {
+	err := r.db.QueryRow(createStmt, b.SpenderID, b.CategoryID, b.Period, b.Amount, b.StartDate, b.EndDate, b.Rollover).Scan(&b.ID)
+	if err != nil {
+		return Budget{}, err
+	}
+
+	return b, nil
+}
+
+func (r repository) Update(b Budget) (Budget, error) {
+	result, err := r.db.Exec(updateStmt, b.CategoryID, b.Period, b.Amount, b.StartDate, b.EndDate, b.Rollover, b.ID, b.SpenderID)
+	if err != nil {
+		return Budget{}, err
+	}
+	if err := affectedOne(result); err != nil {
+		return Budget{}, err
+	}
+
+	return b, nil
+}
+
+func (r repository) Delete(spenderID int, id int) error {
+	result, err := r.db.Exec(deleteStmt, id, spenderID)
+	if err != nil {
+		return err
+	}
+
+	return affectedOne(result)
+}
+
+func affectedOne(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package budget
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var budgetColumns = []string{"id", "spender_id", "category_id", "period", "amount", "start_date", "end_date", "rollover"}
+
+func TestRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	rows := sqlmock.NewRows(budgetColumns).
+		AddRow(1, 3, 1, "monthly", "5000.00", "2024-05-01", nil, true).
+		AddRow(2, 3, nil, "custom", "20000.00", "2024-12-20", "2025-01-05", false)
+	mock.ExpectQuery(listStmt).WithArgs(3).WillReturnRows(rows)
+
+	budgets, err := NewRepository(db).List(3)
+
+	food := 1
+	assert.NoError(t, err)
+	assert.Equal(t, []Budget{
+		{ID: 1, SpenderID: 3, CategoryID: &food, Period: PeriodMonthly, Amount: 5000_00, StartDate: "2024-05-01", Rollover: true},
+		{ID: 2, SpenderID: 3, Period: PeriodCustom, Amount: 20000_00, StartDate: "2024-12-20", EndDate: date("2025-01-05")},
+	}, budgets)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Get(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows(budgetColumns).AddRow(1, 3, nil, "weekly", "1000.00", "2024-05-06", nil, false)
+		mock.ExpectQuery(getStmt).WithArgs(1, 3).WillReturnRows(rows)
+
+		b, err := NewRepository(db).Get(3, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Budget{ID: 1, SpenderID: 3, Period: PeriodWeekly, Amount: 1000_00, StartDate: "2024-05-06"}, b)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(getStmt).WithArgs(1, 4).WillReturnError(sql.ErrNoRows)
+
+		_, err := NewRepository(db).Get(4, 1)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectQuery(createStmt).WithArgs(3, nil, "monthly", "5000.00", "2024-05-01", nil, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	b, err := NewRepository(db).Create(Budget{SpenderID: 3, Period: PeriodMonthly, Amount: 5000_00, StartDate: "2024-05-01", Rollover: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, b.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateAndDelete(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{"own budget", 1, nil},
+		{"not found when another spender's", 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			defer db.Close()
+			repo := NewRepository(db)
+
+			mock.ExpectExec(updateStmt).WithArgs(nil, "weekly", "800.00", "2024-05-06", nil, false, 7, 3).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			mock.ExpectExec(deleteStmt).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			_, updateErr := repo.Update(Budget{ID: 7, SpenderID: 3, Period: PeriodWeekly, Amount: 800_00, StartDate: "2024-05-06"})
+			deleteErr := repo.Delete(3, 7)
+
+			assert.Equal(t, tt.expectedErr, updateErr)
+			assert.Equal(t, tt.expectedErr, deleteErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package budget
+
+import (
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+// Spending adds up a spender's confirmed transactions;
+// transaction.Repository implements it.
+type Spending interface {
+	Summarize(spenderId int, query transaction.SummaryQuery) (transaction.Aggregate, error)
+}
+
+type service struct {
+	repository Repository
+	categories category.Repository
+	spending   Spending
+	timeZones  transaction.TimeZones
+	now        func() time.Time
+}
+
+type Service interface {
+	List(spenderID int) ([]Budget, error)
+	Get(spenderID int, id int) (Budget, error)
+	Create(spenderID int, request Request) (Budget, error)
+	Update(spenderID int, id int, request Request) (Budget, error)
+	Delete(spenderID int, id int) error
+	Status(spenderID int, day *time.Time) ([]Status, error)
+}
+
+// NewService checks budget categories against categories and measures
+// spending with spending, on the calendar of the spender's time zone.
+func NewService(repository Repository, categories category.Repository, spending Spending, timeZones transaction.TimeZones) Service {
+	return service{
+		repository: repository,
+		categories: categories,
+		spending:   spending,
+		timeZones:  timeZones,
+		now:        time.Now,
+	}
+}
+
+func (s service) List(spenderID int) ([]Budget, error) {
+	return s.repository.List(spenderID)
+}
+
+func (s service) Get(spenderID int, id int) (Budget, error) {
+	return s.repository.Get(spenderID, id)
+}
+
+func (s service) Create(spenderID int, request Request) (Budget, error) {
+	b, err := s.budget(spenderID, request)
+	if err != nil {
+		return Budget{}, err
+	}
+
+	return s.repository.Create(b)
+}
+
+// Update replaces the budget; a request without start_date starts it anew
+// in the current period.
+func (s service) Update(spenderID int, id int, request Request) (Budget, error) {
+	b, err := s.budget(spenderID, request)
+	if err != nil {
+		return Budget{}, err
+	}
+	b.ID = id
+
+	return s.repository.Update(b)
+}
+
+func (s service) Delete(spenderID int, id int) error {
+	return s.repository.Delete(spenderID, id)
+}
+
+// budget validates request and turns it into a budget of the spender.
+func (s service) budget(spenderID int, request Request) (Budget, error) {
+	switch request.Period {
+	case PeriodMonthly, PeriodWeekly:
+	case PeriodCustom:
+		if request.StartDate == "" || request.EndDate == nil {
+			return Budget{}, ErrInvalidRange
+		}
+		if request.Rollover {
+			return Budget{}, ErrInvalidRollover
+		}
+	default:
+		return Budget{}, ErrInvalidPeriod
+	}
+	if request.Amount <= 0 {
+		return Budget{}, ErrInvalidAmount
+	}
+
+	start := request.StartDate
+	if start == "" {
+		first, _ := calendarPeriod(request.Period, s.today(spenderID))
+		start = first.Format(dateLayout)
+	}
+	first, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return Budget{}, ErrInvalidDate
+	}
+	if request.EndDate != nil {
+		last, err := time.Parse(dateLayout, *request.EndDate)
+		if err != nil {
+			return Budget{}, ErrInvalidDate
+		}
+		if last.Before(first) {
+			return Budget{}, ErrInvalidRange
+		}
+	}
+
+	if request.CategoryID != nil {
+		visible, err := s.categories.List(spenderID)
+		if err != nil {
+			return Budget{}, err
+		}
+		found := false
+		for _, c := range visible {
+			found = found || c.ID == *request.CategoryID
+		}
+		if !found {
+			return Budget{}, ErrInvalidCategory
+		}
+	}
+
+	return Budget{
+		SpenderID:  spenderID,
+		CategoryID: request.CategoryID,
+		Period:     request.Period,
+		Amount:     request.Amount,
+		StartDate:  start,
+		EndDate:    request.EndDate,
+		Rollover:   request.Rollover,
+	}, nil
+}
+
+// Status reports the spender's budgets that apply on day, today when nil,
+// in their current period.
+func (s service) Status(spenderID int, day *time.Time) ([]Status, error) {
+	loc := s.location(spenderID)
+	today := s.today(spenderID)
+	if day != nil {
+		today = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	}
+
+	budgets, err := s.repository.List(spenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []Status{}
+	for _, b := range budgets {
+		start, end, ok := b.period(today, loc)
+		if !ok {
+			continue
+		}
+		spent, err := s.spent(b, start, today)
+		if err != nil {
+			return nil, err
+		}
+
+		status := Status{
+			Budget:      b,
+			PeriodStart: start.Format(dateLayout),
+			PeriodEnd:   end.Format(dateLayout),
+			Spent:       spent,
+		}
+		if b.Rollover {
+			// The first period has no previous one: the day before it is
+			// before StartDate.
+			previousStart, previousEnd, ok := b.period(start.AddDate(0, 0, -1), loc)
+			if ok {
+				previous, err := s.spent(b, previousStart, previousEnd)
+				if err != nil {
+					return nil, err
+				}
+				status.RolledOver = max(b.Amount-previous, 0)
+			}
+		}
+		status.Limit = b.Amount + status.RolledOver
+		status.Remaining = status.Limit - spent
+		status.Projected = (spent * money.Amount(days(start, end))).Div(days(start, today))
+		status.Flag = flag(status)
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func flag(s Status) string {
+	switch {
+	case s.Spent > s.Limit:
+		return FlagOverLimit
+	case s.Spent*100 >= s.Limit*NearLimitPercent, s.Projected > s.Limit:
+		return FlagNearLimit
+	default:
+		return FlagOK
+	}
+}
+
+// spent adds up the budget's expenses from the start of from to the end of
+// to.
+func (s service) spent(b Budget, from, to time.Time) (money.Amount, error) {
+	query := transaction.SummaryQuery{TxnType: "expense", From: &from, To: &to}
+	if b.CategoryID != nil {
+		query.CategoryIDs = []int{*b.CategoryID}
+	}
+
+	aggregate, err := s.spending.Summarize(b.SpenderID, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return aggregate.TotalAmount, nil
+}
+
+func (s service) today(spenderID int) time.Time {
+	now := s.now().In(s.location(spenderID))
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+func (s service) location(spenderID int) *time.Location {
+	loc, err := time.LoadLocation(s.timeZones.TimeZone(spenderID))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
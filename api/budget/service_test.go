@@ -0,0 +1,186 @@
+package budget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedTimeZone string
+
+func (z fixedTimeZone) TimeZone(int) string { return string(z) }
+
+// newTestService runs on Bangkok time with the clock at noon on
+// 10 May 2024 there.
+func newTestService(transactions transaction.Repository) service {
+	return service{
+		repository: NewMemoryRepository(),
+		categories: category.NewMemoryRepository(),
+		spending:   transactions,
+		timeZones:  fixedTimeZone("Asia/Bangkok"),
+		now:        func() time.Time { return time.Date(2024, time.May, 10, 5, 0, 0, 0, time.UTC) },
+	}
+}
+
+func date(s string) *string { return &s }
+
+func TestService_Create(t *testing.T) {
+	food, unknown := 1, 99
+
+	tests := []struct {
+		name     string
+		request  Request
+		expected Budget
+		err      error
+	}{
+		{"monthly starts with the current month", Request{CategoryID: &food, Period: PeriodMonthly, Amount: 5000_00, Rollover: true},
+			Budget{ID: 1, SpenderID: 3, CategoryID: &food, Period: PeriodMonthly, Amount: 5000_00, StartDate: "2024-05-01", Rollover: true}, nil},
+		{"weekly starts on Monday", Request{Period: PeriodWeekly, Amount: 1000_00},
+			Budget{ID: 1, SpenderID: 3, Period: PeriodWeekly, Amount: 1000_00, StartDate: "2024-05-06"}, nil},
+		{"custom", Request{Period: PeriodCustom, Amount: 20000_00, StartDate: "2024-12-20", EndDate: date("2025-01-05")},
+			Budget{ID: 1, SpenderID: 3, Period: PeriodCustom, Amount: 20000_00, StartDate: "2024-12-20", EndDate: date("2025-01-05")}, nil},
+		{"unknown period", Request{Period: "daily", Amount: 100_00}, Budget{}, ErrInvalidPeriod},
+		{"zero amount", Request{Period: PeriodMonthly}, Budget{}, ErrInvalidAmount},
+		{"custom without end", Request{Period: PeriodCustom, Amount: 100_00, StartDate: "2024-05-01"}, Budget{}, ErrInvalidRange},
+		{"end before start", Request{Period: PeriodMonthly, Amount: 100_00, StartDate: "2024-05-01", EndDate: date("2024-04-30")}, Budget{}, ErrInvalidRange},
+		{"bad date", Request{Period: PeriodMonthly, Amount: 100_00, StartDate: "01/05/2024"}, Budget{}, ErrInvalidDate},
+		{"custom rollover", Request{Period: PeriodCustom, Amount: 100_00, StartDate: "2024-05-01", EndDate: date("2024-05-02"), Rollover: true}, Budget{}, ErrInvalidRollover},
+		{"unknown category", Request{CategoryID: &unknown, Period: PeriodMonthly, Amount: 100_00}, Budget{}, ErrInvalidCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(transaction.NewMemoryRepository())
+
+			b, err := s.Create(3, tt.request)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, b)
+		})
+	}
+}
+
+func TestService_UpdateAndDelete(t *testing.T) {
+	s := newTestService(transaction.NewMemoryRepository())
+	b, _ := s.Create(3, Request{Period: PeriodMonthly, Amount: 5000_00})
+
+	updated, err := s.Update(3, b.ID, Request{Period: PeriodMonthly, Amount: 6000_00, StartDate: "2024-01-01"})
+	_, otherErr := s.Update(4, b.ID, Request{Period: PeriodMonthly, Amount: 1_00})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Budget{ID: b.ID, SpenderID: 3, Period: PeriodMonthly, Amount: 6000_00, StartDate: "2024-01-01"}, updated)
+	assert.Equal(t, ErrNotFound, otherErr)
+	assert.Equal(t, ErrNotFound, s.Delete(4, b.ID))
+	assert.NoError(t, s.Delete(3, b.ID))
+	_, err = s.Get(3, b.ID)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestService_Status(t *testing.T) {
+	// Arrange
+	transactions := transaction.NewMemoryRepository()
+	transactions.UseCategories(category.NewMemoryRepository())
+	expense := func(month time.Month, day int, amount money.Amount, categoryID int) {
+		d := time.Date(2024, month, day, 12, 0, 0, 0, time.UTC)
+		_, _ = transactions.Create(transaction.CreateTransactionRequest{
+			Date: &d, Amount: amount, CategoryID: &categoryID, SpenderId: 3, TxnType: "expense",
+		})
+	}
+	expense(time.April, 20, 3000_00, 2) // Groceries, under Food
+	expense(time.May, 2, 2000_00, 3)    // Dining out, under Food
+	expense(time.May, 9, 1500_00, 6)    // Fuel
+	expense(time.May, 20, 9999_00, 2)   // after the status day
+	s := newTestService(transactions)
+	food, transport := 1, 5
+	_, _ = s.Create(3, Request{CategoryID: &food, Period: PeriodMonthly, Amount: 5000_00, StartDate: "2024-04-01", Rollover: true})
+	_, _ = s.Create(3, Request{CategoryID: &transport, Period: PeriodWeekly, Amount: 1500_00})
+	_, _ = s.Create(3, Request{Period: PeriodMonthly, Amount: 20000_00})
+	_, _ = s.Create(3, Request{Period: PeriodCustom, Amount: 100_00, StartDate: "2024-06-01", EndDate: date("2024-06-30")})
+
+	// Act
+	statuses, err := s.Status(3, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 3, "the June budget does not apply yet")
+
+	foodStatus := statuses[0]
+	assert.Equal(t, "2024-05-01", foodStatus.PeriodStart)
+	assert.Equal(t, "2024-05-31", foodStatus.PeriodEnd)
+	assert.Equal(t, money.Amount(2000_00), foodStatus.RolledOver)
+	assert.Equal(t, money.Amount(7000_00), foodStatus.Limit)
+	assert.Equal(t, money.Amount(2000_00), foodStatus.Spent)
+	assert.Equal(t, money.Amount(5000_00), foodStatus.Remaining)
+	assert.Equal(t, money.Amount(6200_00), foodStatus.Projected)
+	assert.Equal(t, FlagOK, foodStatus.Flag)
+
+	transportStatus := statuses[1]
+	assert.Equal(t, "2024-05-06", transportStatus.PeriodStart)
+	assert.Equal(t, "2024-05-12", transportStatus.PeriodEnd)
+	assert.Equal(t, money.Amount(0), transportStatus.RolledOver)
+	assert.Equal(t, money.Amount(0), transportStatus.Remaining)
+	assert.Equal(t, FlagNearLimit, transportStatus.Flag)
+
+	overall := statuses[2]
+	assert.Equal(t, money.Amount(3500_00), overall.Spent)
+	assert.Equal(t, FlagOK, overall.Flag)
+}
+
+func TestService_Status_OnDay(t *testing.T) {
+	transactions := transaction.NewMemoryRepository()
+	d := time.Date(2024, time.June, 3, 12, 0, 0, 0, time.UTC)
+	_, _ = transactions.Create(transaction.CreateTransactionRequest{Date: &d, Amount: 150_00, SpenderId: 3, TxnType: "expense"})
+	s := newTestService(transactions)
+	_, _ = s.Create(3, Request{Period: PeriodCustom, Amount: 100_00, StartDate: "2024-06-01", EndDate: date("2024-06-30")})
+	day := time.Date(2024, time.June, 5, 0, 0, 0, 0, time.UTC)
+
+	statuses, err := s.Status(3, &day)
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "2024-06-30", statuses[0].PeriodEnd)
+	assert.Equal(t, money.Amount(-50_00), statuses[0].Remaining)
+	assert.Equal(t, money.Amount(900_00), statuses[0].Projected)
+	assert.Equal(t, FlagOverLimit, statuses[0].Flag)
+}
+
+func TestService_Status_StartsMidPeriod(t *testing.T) {
+	transactions := transaction.NewMemoryRepository()
+	expense := func(month time.Month, day int, amount money.Amount) {
+		d := time.Date(2024, month, day, 12, 0, 0, 0, time.UTC)
+		_, _ = transactions.Create(transaction.CreateTransactionRequest{Date: &d, Amount: amount, SpenderId: 3, TxnType: "expense"})
+	}
+	expense(time.May, 2, 4000_00) // before the budget started
+	expense(time.May, 6, 1000_00)
+	expense(time.June, 3, 500_00)
+	s := newTestService(transactions)
+	_, _ = s.Create(3, Request{Period: PeriodMonthly, Amount: 3000_00, StartDate: "2024-05-05", Rollover: true})
+
+	t.Run("first period starts on the start date without rollover", func(t *testing.T) {
+		statuses, err := s.Status(3, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, "2024-05-05", statuses[0].PeriodStart)
+		assert.Equal(t, money.Amount(0), statuses[0].RolledOver)
+		assert.Equal(t, money.Amount(1000_00), statuses[0].Spent)
+		assert.Equal(t, money.Amount(2000_00), statuses[0].Remaining)
+		assert.Equal(t, money.Amount(4500_00), statuses[0].Projected)
+	})
+
+	t.Run("next period rolls over what was left from the start date on", func(t *testing.T) {
+		day := time.Date(2024, time.June, 10, 0, 0, 0, 0, time.UTC)
+
+		statuses, err := s.Status(3, &day)
+
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 1)
+		assert.Equal(t, "2024-06-01", statuses[0].PeriodStart)
+		assert.Equal(t, money.Amount(2000_00), statuses[0].RolledOver)
+		assert.Equal(t, money.Amount(5000_00), statuses[0].Limit)
+	})
+}
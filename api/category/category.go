@@ -0,0 +1,94 @@
+package category
+
+import (
+	"errors"
+	"strings"
+)
+
+// Category groups transactions. System categories have no SpenderID and are
+// shared by every spender; a spender's custom categories are visible only to
+// that spender. A category with a ParentID is a subcategory of it.
+type Category struct {
+	ID        int    `json:"id"`
+	ParentID  *int   `json:"parent_id"`
+	SpenderID *int   `json:"spender_id"`
+	NameEN    string `json:"name_en"`
+	NameTH    string `json:"name_th"`
+}
+
+type CreateRequest struct {
+	ParentID *int   `json:"parent_id"`
+	NameEN   string `json:"name_en"`
+	NameTH   string `json:"name_th"`
+}
+
+const maxNameLength = 50
+
+var (
+	ErrNotFound      = errors.New("category not found")
+	ErrInvalidName   = errors.New("name_en or name_th is required and must be at most 50 characters")
+	ErrNameTaken     = errors.New("a category with this name already exists")
+	ErrInvalidParent = errors.New("parent category not found")
+)
+
+// Named reports whether name is the category's English name, in any case, or
+// its Thai name.
+func (c Category) Named(name string) bool {
+	name = strings.TrimSpace(name)
+	return name != "" && (strings.EqualFold(c.NameEN, name) || c.NameTH == name)
+}
+
+// Subtree returns ids together with the ids of all their subcategories
+// among categories.
+func Subtree(categories []Category, ids []int) []int {
+	children := map[int][]int{}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	seen := map[int]bool{}
+	var tree []int
+	queue := append([]int{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		tree = append(tree, id)
+		queue = append(queue, children[id]...)
+	}
+	return tree
+}
+
+func parent(id int) *int { return &id }
+
+// Defaults are the system categories seeded by migration
+// 15_category.sql; the in-memory repository starts with the same set.
+var Defaults = []Category{
+	{ID: 1, NameEN: "Food", NameTH: "อาหาร"},
+	{ID: 2, ParentID: parent(1), NameEN: "Groceries", NameTH: "ของชำ"},
+	{ID: 3, ParentID: parent(1), NameEN: "Dining out", NameTH: "ทานอาหารนอกบ้าน"},
+	{ID: 4, ParentID: parent(1), NameEN: "Drinks", NameTH: "เครื่องดื่ม"},
+	{ID: 5, NameEN: "Transport", NameTH: "การเดินทาง"},
+	{ID: 6, ParentID: parent(5), NameEN: "Fuel", NameTH: "น้ำมัน"},
+	{ID: 7, ParentID: parent(5), NameEN: "Public transport", NameTH: "ขนส่งสาธารณะ"},
+	{ID: 8, ParentID: parent(5), NameEN: "Taxi", NameTH: "แท็กซี่"},
+	{ID: 9, NameEN: "Housing", NameTH: "ที่อยู่อาศัย"},
+	{ID: 10, ParentID: parent(9), NameEN: "Rent", NameTH: "ค่าเช่า"},
+	{ID: 11, ParentID: parent(9), NameEN: "Utilities", NameTH: "ค่าน้ำค่าไฟ"},
+	{ID: 12, NameEN: "Bills", NameTH: "ค่าบริการ"},
+	{ID: 13, ParentID: parent(12), NameEN: "Phone and internet", NameTH: "ค่าโทรศัพท์และอินเทอร์เน็ต"},
+	{ID: 14, NameEN: "Shopping", NameTH: "ช้อปปิ้ง"},
+	{ID: 15, NameEN: "Health", NameTH: "สุขภาพ"},
+	{ID: 16, NameEN: "Entertainment", NameTH: "บันเทิง"},
+	{ID: 17, NameEN: "Education", NameTH: "การศึกษา"},
+	{ID: 18, NameEN: "Salary", NameTH: "เงินเดือน"},
+	{ID: 19, NameEN: "Bonus", NameTH: "โบนัส"},
+	{ID: 20, NameEN: "Investment", NameTH: "การลงทุน"},
+	{ID: 21, NameEN: "Gifts", NameTH: "ของขวัญ"},
+	{ID: 22, NameEN: "Other", NameTH: "อื่นๆ"},
+}
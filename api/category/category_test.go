@@ -0,0 +1,22 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtree(t *testing.T) {
+	tree := Subtree(Defaults, []int{1, 9, 3})
+
+	assert.Equal(t, []int{1, 9, 3, 2, 4, 10, 11}, tree)
+}
+
+func TestCategory_Named(t *testing.T) {
+	food := Defaults[0]
+
+	assert.True(t, food.Named(" food "))
+	assert.True(t, food.Named("อาหาร"))
+	assert.False(t, food.Named("foods"))
+	assert.False(t, food.Named(""))
+}
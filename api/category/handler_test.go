@@ -0,0 +1,124 @@
+package category
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(spenderID int) ([]Category, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]Category), args.Error(1)
+}
+
+func (m *MockService) Create(spenderID int, request CreateRequest) (Category, error) {
+	args := m.Called(spenderID, request)
+	return args.Get(0).(Category), args.Error(1)
+}
+
+func (m *MockService) Delete(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func newAuthenticatedContext(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/categories", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3})
+	return c, rec
+}
+
+func TestHandler_List(t *testing.T) {
+	c, rec := newAuthenticatedContext(http.MethodGet, "")
+	mockService := new(MockService)
+	mockService.On("List", 3).Return([]Category{{ID: 1, NameEN: "Food", NameTH: "อาหาร"}}, nil)
+
+	err := NewHandler(mockService).List(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"id": 1, "parent_id": null, "spender_id": null, "name_en": "Food", "name_th": "อาหาร"}]`, rec.Body.String())
+}
+
+func TestHandler_Create(t *testing.T) {
+	request := CreateRequest{NameEN: "Pets", NameTH: "สัตว์เลี้ยง"}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid name", ErrInvalidName, http.StatusBadRequest},
+		{"unknown parent", ErrInvalidParent, http.StatusBadRequest},
+		{"name taken", ErrNameTaken, http.StatusConflict},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, `{"name_en": "Pets", "name_th": "สัตว์เลี้ยง"}`)
+			mockService := new(MockService)
+			mockService.On("Create", 3, request).Return(Category{ID: 23}, tt.mockError)
+
+			err := NewHandler(mockService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockError      error
+		expectedStatus int
+	}{
+		{"deleted", "23", nil, http.StatusNoContent},
+		{"not found", "1", ErrNotFound, http.StatusNotFound},
+		{"invalid id", "abc", nil, http.StatusBadRequest},
+		{"internal error", "23", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodDelete, "")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			mockService := new(MockService)
+			mockService.On("Delete", 3, mock.Anything).Return(tt.mockError)
+
+			err := NewHandler(mockService).Delete(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ShouldReturnUnauthorized_WhenNoIdentity(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/categories", nil), rec)
+
+	err := NewHandler(new(MockService)).List(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,69 @@
+package category
+
+import (
+	"sort"
+	"sync"
+)
+
+type memoryRepository struct {
+	mu         sync.Mutex
+	categories map[int]Category
+	nextID     int
+}
+
+// NewMemoryRepository keeps the catalog in process memory for the
+// --storage=memory server mode, starting with the Defaults.
+func NewMemoryRepository() Repository {
+	r := &memoryRepository{categories: map[int]Category{}}
+	for _, c := range Defaults {
+		r.categories[c.ID] = c
+		r.nextID = max(r.nextID, c.ID+1)
+	}
+	return r
+}
+
+func (r *memoryRepository) List(spenderID int) ([]Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	categories := []Category{}
+	for _, c := range r.categories {
+		if c.SpenderID == nil || *c.SpenderID == spenderID {
+			categories = append(categories, c)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].ID < categories[j].ID })
+
+	return categories, nil
+}
+
+func (r *memoryRepository) Create(c Category) (Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c.ID = r.nextID
+	r.nextID++
+	r.categories[c.ID] = c
+
+	return c, nil
+}
+
+func (r *memoryRepository) Delete(spenderID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.categories[id]
+	if !ok || c.SpenderID == nil || *c.SpenderID != spenderID {
+		return ErrNotFound
+	}
+
+	all := make([]Category, 0, len(r.categories))
+	for _, c := range r.categories {
+		all = append(all, c)
+	}
+	for _, id := range Subtree(all, []int{id}) {
+		delete(r.categories, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package category
+
+import (
+	"database/sql"
+)
+
+// Repository stores the category catalog. List and Delete are scoped to a
+// spender: the system categories and the spender's own.
+type Repository interface {
+	List(spenderID int) ([]Category, error)
+	Create(c Category) (Category, error)
+	Delete(spenderID int, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	listStmt   = `SELECT id, parent_id, spender_id, name_en, name_th FROM category WHERE spender_id IS NULL OR spender_id = $1 ORDER BY id`
+	createStmt = `INSERT INTO category (parent_id, spender_id, name_en, name_th) VALUES ($1, $2, $3, $4) RETURNING id`
+	deleteStmt = `DELETE FROM category WHERE id = $1 AND spender_id = $2`
+)
+
+func (r repository) List(spenderID int) ([]Category, error) {
+	rows, err := r.db.Query(listStmt, spenderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []Category{}
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.ParentID, &c.SpenderID, &c.NameEN, &c.NameTH); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r repository) Create(c Category) (Category, error) {
+	err := r.db.QueryRow(createStmt, c.ParentID, c.SpenderID, c.NameEN, c.NameTH).Scan(&c.ID)
+	if err != nil {
+		return Category{}, err
+	}
+
+	return c, nil
+}
+
+// Delete removes one of the spender's own categories with its
+// subcategories; their transactions become uncategorised.
+func (r repository) Delete(spenderID int, id int) error {
+	result, err := r.db.Exec(deleteStmt, id, spenderID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "parent_id", "spender_id", "name_en", "name_th"}).
+		AddRow(1, nil, nil, "Food", "อาหาร").
+		AddRow(23, 1, 7, "Street food", "สตรีทฟู้ด")
+	mock.ExpectQuery(listStmt).WithArgs(7).WillReturnRows(rows)
+
+	categories, err := NewRepository(db).List(7)
+
+	food, spender := 1, 7
+	assert.NoError(t, err)
+	assert.Equal(t, []Category{
+		{ID: 1, NameEN: "Food", NameTH: "อาหาร"},
+		{ID: 23, ParentID: &food, SpenderID: &spender, NameEN: "Street food", NameTH: "สตรีทฟู้ด"},
+	}, categories)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	spender := 7
+	mock.ExpectQuery(createStmt).WithArgs(nil, 7, "Pets", "สัตว์เลี้ยง").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(23))
+
+	c, err := NewRepository(db).Create(Category{SpenderID: &spender, NameEN: "Pets", NameTH: "สัตว์เลี้ยง"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 23, c.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Delete(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{"deleted own category", 1, nil},
+		{"not found when system or another spender's", 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			defer db.Close()
+
+			mock.ExpectExec(deleteStmt).WithArgs(23, 7).WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			err := NewRepository(db).Delete(7, 23)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
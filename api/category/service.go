@@ -0,0 +1,73 @@
+package category
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type service struct {
+	repository Repository
+}
+
+type Service interface {
+	List(spenderID int) ([]Category, error)
+	Create(spenderID int, request CreateRequest) (Category, error)
+	Delete(spenderID int, id int) error
+}
+
+func NewService(repository Repository) Service {
+	return service{repository: repository}
+}
+
+// List returns the system categories and the spender's own, by id.
+func (s service) List(spenderID int) ([]Category, error) {
+	return s.repository.List(spenderID)
+}
+
+// Create adds a custom category for the spender, optionally under a
+// category it can see. A missing English or Thai name takes the other one.
+// Names must not repeat one the spender can already see, in either
+// language.
+func (s service) Create(spenderID int, request CreateRequest) (Category, error) {
+	nameEN := strings.TrimSpace(request.NameEN)
+	nameTH := strings.TrimSpace(request.NameTH)
+	if nameEN == "" {
+		nameEN = nameTH
+	}
+	if nameTH == "" {
+		nameTH = nameEN
+	}
+	if nameEN == "" || utf8.RuneCountInString(nameEN) > maxNameLength || utf8.RuneCountInString(nameTH) > maxNameLength {
+		return Category{}, ErrInvalidName
+	}
+
+	visible, err := s.repository.List(spenderID)
+	if err != nil {
+		return Category{}, err
+	}
+	parentFound := request.ParentID == nil
+	for _, c := range visible {
+		if c.Named(nameEN) || c.Named(nameTH) {
+			return Category{}, ErrNameTaken
+		}
+		if request.ParentID != nil && c.ID == *request.ParentID {
+			parentFound = true
+		}
+	}
+	if !parentFound {
+		return Category{}, ErrInvalidParent
+	}
+
+	return s.repository.Create(Category{
+		ParentID:  request.ParentID,
+		SpenderID: &spenderID,
+		NameEN:    nameEN,
+		NameTH:    nameTH,
+	})
+}
+
+// Delete removes one of the spender's own categories. System categories
+// cannot be deleted and report ErrNotFound.
+func (s service) Delete(spenderID int, id int) error {
+	return s.repository.Delete(spenderID, id)
+}
@@ -0,0 +1,81 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_List(t *testing.T) {
+	repo := NewMemoryRepository()
+	s := NewService(repo)
+	_, _ = s.Create(1, CreateRequest{NameEN: "Pets", NameTH: "สัตว์เลี้ยง"})
+	_, _ = s.Create(2, CreateRequest{NameEN: "Golf"})
+
+	categories, err := s.List(1)
+
+	assert.NoError(t, err)
+	assert.Len(t, categories, len(Defaults)+1)
+	assert.Equal(t, "Pets", categories[len(categories)-1].NameEN)
+}
+
+func TestService_Create(t *testing.T) {
+	food, other := 1, 99
+
+	tests := []struct {
+		name     string
+		request  CreateRequest
+		expected Category
+		err      error
+	}{
+		{"subcategory of a system category", CreateRequest{ParentID: &food, NameEN: " Street food ", NameTH: "สตรีทฟู้ด"},
+			Category{ParentID: &food, NameEN: "Street food", NameTH: "สตรีทฟู้ด"}, nil},
+		{"Thai name only", CreateRequest{NameTH: "ทำบุญ"}, Category{NameEN: "ทำบุญ", NameTH: "ทำบุญ"}, nil},
+		{"missing name", CreateRequest{NameEN: " "}, Category{}, ErrInvalidName},
+		{"name too long", CreateRequest{NameEN: "a category name that is much longer than fifty chars"}, Category{}, ErrInvalidName},
+		{"name of a system category", CreateRequest{NameEN: "FOOD"}, Category{}, ErrNameTaken},
+		{"Thai name of a system category", CreateRequest{NameEN: "Meals", NameTH: "อาหาร"}, Category{}, ErrNameTaken},
+		{"unknown parent", CreateRequest{ParentID: &other, NameEN: "Pets"}, Category{}, ErrInvalidParent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(NewMemoryRepository())
+
+			c, err := s.Create(7, tt.request)
+
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				spenderID := 7
+				tt.expected.ID = len(Defaults) + 1
+				tt.expected.SpenderID = &spenderID
+				assert.Equal(t, tt.expected, c)
+			}
+		})
+	}
+
+	t.Run("parent must be visible to the spender", func(t *testing.T) {
+		s := NewService(NewMemoryRepository())
+		pets, _ := s.Create(1, CreateRequest{NameEN: "Pets"})
+
+		_, err := s.Create(2, CreateRequest{ParentID: &pets.ID, NameEN: "Dog food"})
+
+		assert.Equal(t, ErrInvalidParent, err)
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	s := NewService(NewMemoryRepository())
+	pets, _ := s.Create(1, CreateRequest{NameEN: "Pets"})
+	dogs, _ := s.Create(1, CreateRequest{ParentID: &pets.ID, NameEN: "Dogs"})
+
+	assert.Equal(t, ErrNotFound, s.Delete(1, 1), "system categories cannot be deleted")
+	assert.Equal(t, ErrNotFound, s.Delete(2, pets.ID), "only the owner may delete")
+	assert.NoError(t, s.Delete(1, pets.ID))
+
+	categories, _ := s.List(1)
+	for _, c := range categories {
+		assert.NotEqual(t, dogs.ID, c.ID, "subcategories are deleted with their parent")
+	}
+	assert.Len(t, categories, len(Defaults))
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 )
@@ -14,6 +15,13 @@ type Config struct {
 	Database    Database
 	Server      Server
 	FeatureFlag FeatureFlag
+	Auth        Auth
+	Blob        Blob
+	Upload      Upload
+	Webhook     Webhook
+	Jobs        Jobs
+	OCR         OCR
+	Recurring   Recurring
 }
 
 func (c Config) PostgresURI() string {
@@ -21,15 +29,91 @@ func (c Config) PostgresURI() string {
 }
 
 type Server struct {
-	Port string `env:"SERVER_PORT"`
+	Port    string `env:"SERVER_PORT"`
+	Storage string
 }
 
+// Storage backends selectable with the --storage flag.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
 type Database struct {
 	PostgresURI string `env:"DATABASE_POSTGRES_URI,required"`
 }
 
 type FeatureFlag struct {
 	EnableCreateSpender bool `env:"ENABLE_CREATE_SPENDER"`
+	EnableLegacyAuth    bool `env:"ENABLE_LEGACY_AUTH"`
+}
+
+type Auth struct {
+	MaxFailedAttempts int           `env:"AUTH_MAX_FAILED_ATTEMPTS" envDefault:"5"`
+	LockoutDuration   time.Duration `env:"AUTH_LOCKOUT_DURATION" envDefault:"15m"`
+
+	// JWTKeys maps key IDs to HMAC secrets, e.g. "2024-05:new-secret,2024-04:old-secret".
+	// Tokens are signed with JWTActiveKeyID and verified with any listed key,
+	// so a key can be rotated out by removing it once its tokens have expired.
+	JWTKeys         map[string]string `env:"AUTH_JWT_KEYS" envKeyValSeparator:":"`
+	JWTActiveKeyID  string            `env:"AUTH_JWT_ACTIVE_KID"`
+	AccessTokenTTL  time.Duration     `env:"AUTH_ACCESS_TOKEN_TTL" envDefault:"15m"`
+	RefreshTokenTTL time.Duration     `env:"AUTH_REFRESH_TOKEN_TTL" envDefault:"720h"`
+}
+
+// Blob selects where uploaded e-slips are stored: "local" keeps them under
+// LocalDir, "s3" sends them to an S3-compatible service such as MinIO.
+type Blob struct {
+	Backend           string `env:"BLOB_BACKEND" envDefault:"local"`
+	LocalDir          string `env:"BLOB_LOCAL_DIR" envDefault:"./data/slips"`
+	S3Endpoint        string `env:"BLOB_S3_ENDPOINT"`
+	S3Region          string `env:"BLOB_S3_REGION" envDefault:"us-east-1"`
+	S3Bucket          string `env:"BLOB_S3_BUCKET"`
+	S3AccessKeyID     string `env:"BLOB_S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `env:"BLOB_S3_SECRET_ACCESS_KEY"`
+}
+
+// Upload limits what a single e-slip upload request may carry. MaxFileSize
+// is in bytes.
+type Upload struct {
+	MaxFileSize int64 `env:"UPLOAD_MAX_FILE_SIZE" envDefault:"10485760"`
+	MaxFiles    int   `env:"UPLOAD_MAX_FILES" envDefault:"10"`
+}
+
+// Webhook authenticates calls from the slip extraction service. Requests
+// are signed with Secret and accepted within Tolerance of their timestamp.
+type Webhook struct {
+	Secret    string        `env:"WEBHOOK_SECRET"`
+	Tolerance time.Duration `env:"WEBHOOK_TOLERANCE" envDefault:"5m"`
+}
+
+// Jobs tunes the background worker pool. A failed job is retried after
+// BackoffBase, doubling up to BackoffMax, and is dead-lettered after
+// MaxAttempts. A job running longer than Lease is assumed lost and run again,
+// so its handler is cancelled after Timeout, which must be shorter.
+type Jobs struct {
+	Workers      int           `env:"JOB_WORKERS" envDefault:"4"`
+	PollInterval time.Duration `env:"JOB_POLL_INTERVAL" envDefault:"1s"`
+	MaxAttempts  int           `env:"JOB_MAX_ATTEMPTS" envDefault:"5"`
+	BackoffBase  time.Duration `env:"JOB_BACKOFF_BASE" envDefault:"2s"`
+	BackoffMax   time.Duration `env:"JOB_BACKOFF_MAX" envDefault:"5m"`
+	Lease        time.Duration `env:"JOB_LEASE" envDefault:"5m"`
+	Timeout      time.Duration `env:"JOB_TIMEOUT" envDefault:"4m"`
+}
+
+// OCR configures reading slips without a QR code. Command is the local
+// tesseract binary; when it is empty such slips are left for the spender to
+// enter. Results less certain than MinConfidence are kept as drafts.
+type OCR struct {
+	Command       string  `env:"OCR_COMMAND"`
+	Languages     string  `env:"OCR_LANGUAGES" envDefault:"tha+eng"`
+	MinConfidence float64 `env:"OCR_MIN_CONFIDENCE" envDefault:"0.8"`
+}
+
+// Recurring sets how often the scheduler generates the recurring
+// transactions that have come due.
+type Recurring struct {
+	Interval time.Duration `env:"RECURRING_INTERVAL" envDefault:"1m"`
 }
 
 func Env(key string) string {
@@ -56,14 +140,21 @@ func prefix(env string) string {
 	return ""
 }
 
-func parse(envPrefix string) (Config, error) {
+func parse(envPrefix string, storage string) (Config, error) {
 	opts := env.Options{
 		Prefix: prefix(envPrefix),
 	}
 
+	// The in-memory storage has no database, so its settings are not required.
 	dbconf := &Database{}
-	if err := env.ParseWithOptions(dbconf, opts); err != nil {
-		return Config{}, errors.New("failed to parse database config:" + err.Error())
+	switch storage {
+	case StoragePostgres:
+		if err := env.ParseWithOptions(dbconf, opts); err != nil {
+			return Config{}, errors.New("failed to parse database config:" + err.Error())
+		}
+	case StorageMemory:
+	default:
+		return Config{}, fmt.Errorf("unknown storage %q, want %s or %s", storage, StoragePostgres, StorageMemory)
 	}
 
 	feats := &FeatureFlag{}
@@ -71,6 +162,47 @@ func parse(envPrefix string) (Config, error) {
 		return Config{}, errors.New("failed to parse feature flag config:" + err.Error())
 	}
 
+	authconf := &Auth{}
+	if err := env.ParseWithOptions(authconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse auth config:" + err.Error())
+	}
+
+	blobconf := &Blob{}
+	if err := env.ParseWithOptions(blobconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse blob config:" + err.Error())
+	}
+
+	uploadconf := &Upload{}
+	if err := env.ParseWithOptions(uploadconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse upload config:" + err.Error())
+	}
+
+	webhookconf := &Webhook{}
+	if err := env.ParseWithOptions(webhookconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse webhook config:" + err.Error())
+	}
+
+	jobsconf := &Jobs{}
+	if err := env.ParseWithOptions(jobsconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse jobs config:" + err.Error())
+	}
+	if jobsconf.Timeout <= 0 || jobsconf.Timeout >= jobsconf.Lease {
+		return Config{}, fmt.Errorf("job timeout %s must be positive and shorter than the lease %s", jobsconf.Timeout, jobsconf.Lease)
+	}
+
+	ocrconf := &OCR{}
+	if err := env.ParseWithOptions(ocrconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse ocr config:" + err.Error())
+	}
+
+	recurringconf := &Recurring{}
+	if err := env.ParseWithOptions(recurringconf, opts); err != nil {
+		return Config{}, errors.New("failed to parse recurring config:" + err.Error())
+	}
+	if recurringconf.Interval <= 0 {
+		return Config{}, fmt.Errorf("recurring interval %s must be positive", recurringconf.Interval)
+	}
+
 	port := Env("SERVER_PORT")
 	if port == "" {
 		port = "8080"
@@ -81,18 +213,27 @@ func parse(envPrefix string) (Config, error) {
 			PostgresURI: dbconf.PostgresURI,
 		},
 		Server: Server{
-			Port: port,
+			Port:    port,
+			Storage: storage,
 		},
 		FeatureFlag: FeatureFlag{
 			EnableCreateSpender: feats.EnableCreateSpender,
+			EnableLegacyAuth:    feats.EnableLegacyAuth,
 		},
+		Auth:      *authconf,
+		Blob:      *blobconf,
+		Upload:    *uploadconf,
+		Webhook:   *webhookconf,
+		Jobs:      *jobsconf,
+		OCR:       *ocrconf,
+		Recurring: *recurringconf,
 	}, nil
 }
 
-func Parse(envPrefix string) Config {
+func Parse(envPrefix string, storage string) Config {
 	once.Do(func() {
 		var err error
-		config, err = parse(envPrefix)
+		config, err = parse(envPrefix, storage)
 		if err != nil {
 			log.Panic(err)
 		}
@@ -0,0 +1,57 @@
+package currency
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// Reference is the currency exchange rates are quoted in. Its own rate is
+// always 1 and is never stored.
+const Reference = "THB"
+
+// Default is the base currency of a spender that did not choose one and the
+// currency of a transaction or account created without one.
+const Default = "THB"
+
+// Rate is what one unit of Currency is worth in Reference from Date, a
+// YYYY-MM-DD day, until the next rate of the currency.
+type Rate struct {
+	Currency string  `json:"currency"`
+	Date     string  `json:"date"`
+	Rate     float64 `json:"rate"`
+}
+
+var (
+	ErrNotFound        = errors.New("exchange rate not found")
+	ErrInvalidCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrReferenceRate   = errors.New("THB is the reference currency and always has a rate of 1")
+	ErrInvalidDate     = errors.New("date must be formatted as YYYY-MM-DD")
+	ErrInvalidRate     = errors.New("rate must be above zero")
+	ErrInvalidCSV      = errors.New("CSV must have a header with the columns currency, date and rate")
+	ErrNoRates         = errors.New("at least one exchange rate is required")
+	ErrMissingRate     = errors.New("no exchange rate is recorded for a currency on the date of one of the transactions")
+)
+
+// code matches an ISO 4217 alphabetic code.
+var code = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Normalize trims and upper-cases a currency code.
+func Normalize(c string) string {
+	return strings.ToUpper(strings.TrimSpace(c))
+}
+
+// Valid reports whether c is a normalized ISO 4217 alphabetic code.
+func Valid(c string) bool {
+	return code.MatchString(c)
+}
+
+// Converter converts amounts between currencies at the rates effective at a
+// time, rounding to the satang. It reports ErrMissingRate when either
+// currency has no rate yet.
+type Converter interface {
+	Convert(amount money.Amount, from, to string, at time.Time) (money.Amount, error)
+}
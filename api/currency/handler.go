@@ -0,0 +1,90 @@
+package currency
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	List(c echo.Context) error
+	Save(c echo.Context) error
+	Import(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+type ImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// List takes an optional currency query parameter.
+func (h handler) List(c echo.Context) error {
+	result, err := h.service.List(c.QueryParam("currency"))
+	if err != nil {
+		return h.fail(c, "list exchange rates error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Save takes a JSON array of rates.
+func (h handler) Save(c echo.Context) error {
+	var rates []Rate
+	if err := c.Bind(&rates); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Save(rates)
+	if err != nil {
+		return h.fail(c, "save exchange rates error", err)
+	}
+
+	mlog.L(c).Info("save exchange rates successfully", zap.Int("count", len(result)))
+	return c.JSON(http.StatusOK, result)
+}
+
+// Import takes the CSV file as the request body.
+func (h handler) Import(c echo.Context) error {
+	result, err := h.service.Import(c.Request().Body)
+	if err != nil {
+		return h.fail(c, "import exchange rates error", err)
+	}
+
+	mlog.L(c).Info("import exchange rates successfully", zap.Int("count", len(result)))
+	return c.JSON(http.StatusOK, ImportResponse{Imported: len(result)})
+}
+
+func (h handler) Delete(c echo.Context) error {
+	if err := h.service.Delete(c.Param("currency"), c.Param("date")); err != nil {
+		return h.fail(c, "delete exchange rate error", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	case errors.Is(err, ErrInvalidCurrency), errors.Is(err, ErrReferenceRate), errors.Is(err, ErrInvalidDate),
+		errors.Is(err, ErrInvalidRate), errors.Is(err, ErrInvalidCSV), errors.Is(err, ErrNoRates):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	default:
+		mlog.L(c).Error(msg, zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+}
@@ -0,0 +1,128 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(currency string) ([]Rate, error) {
+	args := m.Called(currency)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockService) Save(rates []Rate) ([]Rate, error) {
+	args := m.Called(rates)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockService) Import(r io.Reader) ([]Rate, error) {
+	args := m.Called(r)
+	return args.Get(0).([]Rate), args.Error(1)
+}
+
+func (m *MockService) Delete(currency string, date string) error {
+	args := m.Called(currency, date)
+	return args.Error(0)
+}
+
+func newContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestHandler_Save(t *testing.T) {
+	rates := []Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"saved", nil, http.StatusOK},
+		{"invalid rate", ErrInvalidRate, http.StatusBadRequest},
+		{"reference currency", ErrReferenceRate, http.StatusBadRequest},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodPut, "/exchange-rates", `[{"currency": "USD", "date": "2024-05-01", "rate": 36.5}]`)
+			mockService := new(MockService)
+			mockService.On("Save", rates).Return(rates, tt.mockError)
+
+			err := NewHandler(mockService).Save(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Import(t *testing.T) {
+	t.Run("reports the number imported", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, "/exchange-rates/import", "currency,date,rate\nUSD,2024-05-01,36.5\n")
+		mockService := new(MockService)
+		mockService.On("Import", mock.Anything).Return([]Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}}, nil)
+
+		err := NewHandler(mockService).Import(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"imported": 1}`, rec.Body.String())
+	})
+
+	t.Run("bad line", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, "/exchange-rates/import", "currency,date,rate\nUSD,2024-05-01,abc\n")
+		mockService := new(MockService)
+		mockService.On("Import", mock.Anything).Return([]Rate(nil), fmt.Errorf("line 2: %w", ErrInvalidRate))
+
+		err := NewHandler(mockService).Import(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"deleted", nil, http.StatusNoContent},
+		{"not found", ErrNotFound, http.StatusNotFound},
+		{"invalid date", ErrInvalidDate, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodDelete, "/", "")
+			c.SetParamNames("currency", "date")
+			c.SetParamValues("USD", "2024-05-01")
+			mockService := new(MockService)
+			mockService.On("Delete", "USD", "2024-05-01").Return(tt.mockError)
+
+			err := NewHandler(mockService).Delete(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
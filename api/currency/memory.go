@@ -0,0 +1,120 @@
+package currency
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+type memoryRepository struct {
+	mu    sync.Mutex
+	rates map[string]map[string]float64
+}
+
+// MemoryRepository is the in-memory Repository. It also converts amounts
+// for the in-memory transaction repository, as the SQL functions rate_at and
+// convert_currency do in Postgres.
+type MemoryRepository interface {
+	Repository
+	Converter
+}
+
+// NewMemoryRepository keeps exchange rates in process memory for the
+// --storage=memory server mode.
+func NewMemoryRepository() MemoryRepository {
+	return &memoryRepository{rates: map[string]map[string]float64{}}
+}
+
+func (r *memoryRepository) List(currency string) ([]Rate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rates := []Rate{}
+	for c, byDate := range r.rates {
+		if currency != "" && c != currency {
+			continue
+		}
+		for date, rate := range byDate {
+			rates = append(rates, Rate{Currency: c, Date: date, Rate: rate})
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Currency != rates[j].Currency {
+			return rates[i].Currency < rates[j].Currency
+		}
+		return rates[i].Date < rates[j].Date
+	})
+
+	return rates, nil
+}
+
+func (r *memoryRepository) Save(rates []Rate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rate := range rates {
+		if r.rates[rate.Currency] == nil {
+			r.rates[rate.Currency] = map[string]float64{}
+		}
+		r.rates[rate.Currency][rate.Date] = rate.Rate
+	}
+
+	return nil
+}
+
+func (r *memoryRepository) Delete(currency string, date string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.rates[currency][date]; !ok {
+		return ErrNotFound
+	}
+	delete(r.rates[currency], date)
+
+	return nil
+}
+
+func (r *memoryRepository) Convert(amount money.Amount, from, to string, at time.Time) (money.Amount, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fromRate, ok := r.rateAt(from, at)
+	if !ok {
+		return 0, ErrMissingRate
+	}
+	toRate, ok := r.rateAt(to, at)
+	if !ok {
+		return 0, ErrMissingRate
+	}
+	return amount.Scale(new(big.Rat).Quo(exact(fromRate), exact(toRate)))
+}
+
+// exact reads a rate back as the decimal it was saved as, which is the
+// shortest one that parses to the same float.
+func exact(rate float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	return r
+}
+
+// rateAt is the latest rate of currency effective on the UTC day of at.
+func (r *memoryRepository) rateAt(currency string, at time.Time) (float64, bool) {
+	if currency == Reference {
+		return 1, true
+	}
+	day := at.UTC().Format("2006-01-02")
+	found, rate := "", 0.0
+	for date, v := range r.rates[currency] {
+		if date <= day && date > found {
+			found, rate = date, v
+		}
+	}
+	return rate, found != ""
+}
@@ -0,0 +1,96 @@
+package currency
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Repository stores exchange rates. Saving a rate for a currency and date
+// that already has one replaces it.
+type Repository interface {
+	List(currency string) ([]Rate, error)
+	Save(rates []Rate) error
+	Delete(currency string, date string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+// missingRateCode is the SQLSTATE rate_at raises when a currency has no rate
+// on a date.
+const missingRateCode = "HJ001"
+
+const (
+	listStmt   = `SELECT currency, to_char(effective_date, 'YYYY-MM-DD'), rate FROM exchange_rate WHERE $1 = '' OR currency = $1 ORDER BY currency, effective_date`
+	saveStmt   = `INSERT INTO exchange_rate (currency, effective_date, rate) VALUES ($1, $2, $3) ON CONFLICT (currency, effective_date) DO UPDATE SET rate = EXCLUDED.rate`
+	deleteStmt = `DELETE FROM exchange_rate WHERE currency = $1 AND effective_date = $2`
+)
+
+// IsMissingRate reports whether err was raised by the database because a
+// conversion found no rate.
+func IsMissingRate(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == missingRateCode
+}
+
+// List returns the rates of currency, or of every currency when it is
+// empty, oldest first.
+func (r repository) List(currency string) ([]Rate, error) {
+	rows, err := r.db.Query(listStmt, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []Rate{}
+	for rows.Next() {
+		var rate Rate
+		if err := rows.Scan(&rate.Currency, &rate.Date, &rate.Rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, rows.Err()
+}
+
+// Save upserts the rates in one database transaction, so an import is
+// applied entirely or not at all.
+func (r repository) Save(rates []Rate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		if _, err := tx.Exec(saveStmt, rate.Currency, rate.Date, rate.Rate); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r repository) Delete(currency string, date string) error {
+	result, err := r.db.Exec(deleteStmt, currency, date)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
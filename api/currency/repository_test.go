@@ -0,0 +1,75 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"currency", "effective_date", "rate"}).
+		AddRow("USD", "2024-05-01", 36.5).
+		AddRow("USD", "2024-06-01", 35.75)
+	mock.ExpectQuery(listStmt).WithArgs("USD").WillReturnRows(rows)
+
+	rates, err := NewRepository(db).List("USD")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}, {Currency: "USD", Date: "2024-06-01", Rate: 35.75}}, rates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Save(t *testing.T) {
+	rates := []Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}, {Currency: "EUR", Date: "2024-05-01", Rate: 39.25}}
+
+	t.Run("upserts in one transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveStmt).WithArgs("USD", "2024-05-01", 36.5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveStmt).WithArgs("EUR", "2024-05-01", 39.25).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, NewRepository(db).Save(rates))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveStmt).WithArgs("USD", "2024-05-01", 36.5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveStmt).WithArgs("EUR", "2024-05-01", 39.25).WillReturnError(errors.New("db down"))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, NewRepository(db).Save(rates), "db down")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectExec(deleteStmt).WithArgs("USD", "2024-05-01").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteStmt).WithArgs("USD", "2024-06-01").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewRepository(db)
+	assert.NoError(t, repo.Delete("USD", "2024-05-01"))
+	assert.Equal(t, ErrNotFound, repo.Delete("USD", "2024-06-01"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsMissingRate(t *testing.T) {
+	assert.True(t, IsMissingRate(&pq.Error{Code: missingRateCode}))
+	assert.False(t, IsMissingRate(&pq.Error{Code: "23505"}))
+	assert.False(t, IsMissingRate(errors.New("db down")))
+}
@@ -0,0 +1,137 @@
+package currency
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type service struct {
+	repository Repository
+}
+
+type Service interface {
+	List(currency string) ([]Rate, error)
+	Save(rates []Rate) ([]Rate, error)
+	Import(r io.Reader) ([]Rate, error)
+	Delete(currency string, date string) error
+}
+
+func NewService(repository Repository) Service {
+	return service{repository: repository}
+}
+
+// List returns the rates of currency, or of every currency when it is
+// empty, by currency and date.
+func (s service) List(currency string) ([]Rate, error) {
+	currency = Normalize(currency)
+	if currency != "" && !Valid(currency) {
+		return nil, ErrInvalidCurrency
+	}
+
+	return s.repository.List(currency)
+}
+
+// Save validates every rate before storing any of them, replacing the rates
+// already recorded for the same currency and date.
+func (s service) Save(rates []Rate) ([]Rate, error) {
+	if len(rates) == 0 {
+		return nil, ErrNoRates
+	}
+	for i := range rates {
+		rate, err := validate(rates[i])
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		rates[i] = rate
+	}
+
+	if err := s.repository.Save(rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Import reads rates from CSV with a header naming the columns currency,
+// date and rate in any order, then saves them like Save. A bad line rejects
+// the whole file and is reported by its line number.
+func (s service) Import(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidCSV
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	currencyAt, okCurrency := columns["currency"]
+	dateAt, okDate := columns["date"]
+	rateAt, okRate := columns["rate"]
+	if !okCurrency || !okDate || !okRate {
+		return nil, ErrInvalidCSV
+	}
+
+	var rates []Rate
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[rateAt]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, ErrInvalidRate)
+		}
+		rate, err := validate(Rate{Currency: record[currencyAt], Date: record[dateAt], Rate: value})
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return nil, ErrNoRates
+	}
+
+	if err := s.repository.Save(rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (s service) Delete(currency string, date string) error {
+	rate, err := validate(Rate{Currency: currency, Date: date, Rate: 1})
+	if err != nil {
+		return err
+	}
+
+	return s.repository.Delete(rate.Currency, rate.Date)
+}
+
+func validate(rate Rate) (Rate, error) {
+	rate.Currency = Normalize(rate.Currency)
+	if !Valid(rate.Currency) {
+		return Rate{}, ErrInvalidCurrency
+	}
+	if rate.Currency == Reference {
+		return Rate{}, ErrReferenceRate
+	}
+	rate.Date = strings.TrimSpace(rate.Date)
+	if _, err := time.Parse("2006-01-02", rate.Date); err != nil {
+		return Rate{}, ErrInvalidDate
+	}
+	if !(rate.Rate > 0) || math.IsInf(rate.Rate, 1) {
+		return Rate{}, ErrInvalidRate
+	}
+	return rate, nil
+}
@@ -0,0 +1,132 @@
+package currency
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Save(t *testing.T) {
+	tests := []struct {
+		name     string
+		rates    []Rate
+		expected []Rate
+		err      string
+	}{
+		{"normalizes the currency", []Rate{{Currency: " usd ", Date: "2024-05-01", Rate: 36.5}},
+			[]Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}}, ""},
+		{"no rates", nil, nil, ErrNoRates.Error()},
+		{"invalid currency", []Rate{{Currency: "dollar", Date: "2024-05-01", Rate: 36.5}}, nil, "rate 1: " + ErrInvalidCurrency.Error()},
+		{"reference currency", []Rate{{Currency: "THB", Date: "2024-05-01", Rate: 1}}, nil, "rate 1: " + ErrReferenceRate.Error()},
+		{"invalid date", []Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}, {Currency: "EUR", Date: "01/05/2024", Rate: 39}},
+			nil, "rate 2: " + ErrInvalidDate.Error()},
+		{"zero rate", []Rate{{Currency: "USD", Date: "2024-05-01"}}, nil, "rate 1: " + ErrInvalidRate.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewMemoryRepository()
+
+			rates, err := NewService(repo).Save(tt.rates)
+
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				stored, _ := repo.List("")
+				assert.Empty(t, stored)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, rates)
+		})
+	}
+}
+
+func TestService_Import(t *testing.T) {
+	t.Run("reads columns in any order", func(t *testing.T) {
+		repo := NewMemoryRepository()
+		csv := "rate,currency,date\n36.5,usd,2024-05-01\n39.25, EUR ,2024-05-01\n"
+
+		rates, err := NewService(repo).Import(strings.NewReader(csv))
+
+		assert.NoError(t, err)
+		assert.Len(t, rates, 2)
+		stored, _ := repo.List("")
+		assert.Equal(t, []Rate{{Currency: "EUR", Date: "2024-05-01", Rate: 39.25}, {Currency: "USD", Date: "2024-05-01", Rate: 36.5}}, stored)
+	})
+
+	tests := []struct {
+		name string
+		csv  string
+		err  string
+	}{
+		{"empty file", "", ErrInvalidCSV.Error()},
+		{"missing column", "currency,rate\nUSD,36.5\n", ErrInvalidCSV.Error()},
+		{"header only", "currency,date,rate\n", ErrNoRates.Error()},
+		{"rate is not a number", "currency,date,rate\nUSD,2024-05-01,36.5\nEUR,2024-05-01,abc\n", "line 3: " + ErrInvalidRate.Error()},
+		{"bad date", "currency,date,rate\nUSD,2024-05-01,36.5\nUSD,2024-13-01,36.5\n", "line 3: " + ErrInvalidDate.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewMemoryRepository()
+
+			_, err := NewService(repo).Import(strings.NewReader(tt.csv))
+
+			assert.EqualError(t, err, tt.err)
+			stored, _ := repo.List("")
+			assert.Empty(t, stored)
+		})
+	}
+}
+
+func TestService_Delete(t *testing.T) {
+	repo := NewMemoryRepository()
+	s := NewService(repo)
+	_, _ = s.Save([]Rate{{Currency: "USD", Date: "2024-05-01", Rate: 36.5}})
+
+	assert.NoError(t, s.Delete("usd", "2024-05-01"))
+	assert.Equal(t, ErrNotFound, s.Delete("USD", "2024-05-01"))
+	assert.Equal(t, ErrInvalidDate, s.Delete("USD", "May 1"))
+}
+
+func TestMemoryRepository_Convert(t *testing.T) {
+	repo := NewMemoryRepository()
+	_ = repo.Save([]Rate{
+		{Currency: "USD", Date: "2024-05-01", Rate: 36},
+		{Currency: "USD", Date: "2024-06-01", Rate: 35},
+		{Currency: "EUR", Date: "2024-05-01", Rate: 40},
+		{Currency: "GBP", Date: "2024-05-01", Rate: 45.1},
+	})
+	may := time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		amount   money.Amount
+		from, to string
+		at       time.Time
+		expected money.Amount
+		err      error
+	}{
+		{"same currency", 10_00, "JPY", "JPY", may, 10_00, nil},
+		{"into the reference", 10_00, "USD", "THB", may, 360_00, nil},
+		{"from the reference", 720_00, "THB", "USD", may, 20_00, nil},
+		{"through the reference, rounded to the satang", 20_00, "EUR", "USD", may, 22_22, nil},
+		{"rate effective on the day", 10_00, "USD", "THB", june, 350_00, nil},
+		{"exact at a decimal rate", 3_00, "GBP", "THB", may, 135_30, nil},
+		{"before the first rate", 10_00, "USD", "THB", time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC), 0, ErrMissingRate},
+		{"currency without rates", 10_00, "JPY", "THB", may, 0, ErrMissingRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := repo.Convert(tt.amount, tt.from, tt.to, tt.at)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
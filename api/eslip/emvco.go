@@ -0,0 +1,166 @@
+package eslip
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+var (
+	ErrMalformedQR = errors.New("malformed QR payload")
+	ErrQRChecksum  = errors.New("QR payload checksum mismatch")
+	ErrNotSlipQR   = errors.New("QR code is not a Thai payment slip")
+)
+
+// banks maps Bank of Thailand bank codes to their common abbreviations.
+var banks = map[string]string{
+	"002": "BBL",
+	"004": "KBANK",
+	"006": "KTB",
+	"011": "TTB",
+	"014": "SCB",
+	"022": "CIMBT",
+	"024": "UOBT",
+	"025": "BAY",
+	"030": "GSB",
+	"033": "GHB",
+	"034": "BAAC",
+	"067": "TISCO",
+	"069": "KKP",
+	"073": "LHFG",
+}
+
+// tlv is one EMVCo data object: a two digit tag, a two digit length and
+// the value.
+type tlv struct {
+	Tag   string
+	Value string
+}
+
+func parseTLV(s string) ([]tlv, error) {
+	var fields []tlv
+	for len(s) > 0 {
+		if len(s) < 4 || !isDigits(s[:4]) {
+			return nil, ErrMalformedQR
+		}
+		n, _ := strconv.Atoi(s[2:4])
+		if n > len(s)-4 {
+			return nil, ErrMalformedQR
+		}
+		fields = append(fields, tlv{Tag: s[:2], Value: s[4 : 4+n]})
+		s = s[4+n:]
+	}
+	return fields, nil
+}
+
+func lookup(fields []tlv, tag string) (string, bool) {
+	for _, f := range fields {
+		if f.Tag == tag {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
+
+// SlipQR is what a slip's QR code tells about the transfer. Amount is zero
+// when the code does not carry it, which is the case for the verification
+// mini-QR most banks print.
+type SlipQR struct {
+	BankCode  string       `json:"bank_code,omitempty"`
+	Bank      string       `json:"bank,omitempty"`
+	Reference string       `json:"reference"`
+	Amount    money.Amount `json:"amount,omitempty"`
+	Currency  string       `json:"currency,omitempty"`
+	Country   string       `json:"country,omitempty"`
+}
+
+// ParseSlipQR reads the two payloads found on Thai slips: the bank slip
+// verification mini-QR (tag 00 holding API id, sending bank and transaction
+// reference, CRC in tag 91) and an EMVCo PromptPay payload (amount in tag
+// 54, reference in tag 62, CRC in tag 63).
+func ParseSlipQR(payload string) (SlipQR, error) {
+	payload = strings.TrimSpace(payload)
+	fields, err := parseTLV(payload)
+	if err != nil {
+		return SlipQR{}, err
+	}
+	if err := verifyCRC(payload, fields); err != nil {
+		return SlipQR{}, err
+	}
+
+	var qr SlipQR
+	qr.Country, _ = lookup(fields, "58")
+	if qr.Country == "" {
+		qr.Country, _ = lookup(fields, "51")
+	}
+	qr.Currency, _ = lookup(fields, "53")
+	if amount, ok := lookup(fields, "54"); ok {
+		qr.Amount, err = money.Parse(amount)
+		if err != nil || qr.Amount < 0 {
+			return SlipQR{}, fmt.Errorf("%w: invalid amount %q", ErrMalformedQR, amount)
+		}
+	}
+
+	head, _ := lookup(fields, "00")
+	if inner, err := parseTLV(head); err == nil && len(inner) > 1 {
+		qr.BankCode, _ = lookup(inner, "01")
+		qr.Reference, _ = lookup(inner, "02")
+	} else if extra, ok := lookup(fields, "62"); ok {
+		inner, err := parseTLV(extra)
+		if err != nil {
+			return SlipQR{}, err
+		}
+		qr.Reference, _ = lookup(inner, "05")
+		if qr.Reference == "" {
+			qr.Reference, _ = lookup(inner, "01")
+		}
+	}
+	qr.Bank = banks[qr.BankCode]
+
+	if qr.Reference == "" || (qr.Country != "" && qr.Country != "TH") {
+		return SlipQR{}, ErrNotSlipQR
+	}
+	return qr, nil
+}
+
+// verifyCRC checks the CRC-16/CCITT-FALSE of the payload when it ends with
+// a checksum object. The checksum covers everything before its value,
+// including its own tag and length.
+func verifyCRC(payload string, fields []tlv) error {
+	last := fields[len(fields)-1]
+	if (last.Tag != "63" && last.Tag != "91") || len(last.Value) != 4 {
+		return nil
+	}
+	want := fmt.Sprintf("%04X", crc16(payload[:len(payload)-4]))
+	if !strings.EqualFold(want, last.Value) {
+		return ErrQRChecksum
+	}
+	return nil
+}
+
+func crc16(s string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
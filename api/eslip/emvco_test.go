@@ -0,0 +1,86 @@
+package eslip
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withCRC appends a checksum object with the given tag to payload.
+func withCRC(payload, tag string) string {
+	payload += tag + "04"
+	return payload + fmt.Sprintf("%04X", crc16(payload))
+}
+
+func obj(tag, value string) string {
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+}
+
+// slipPayload builds a bank slip verification mini-QR payload.
+func slipPayload(bank, reference string) string {
+	return withCRC(obj("00", obj("00", "000001")+obj("01", bank)+obj("02", reference))+obj("51", "TH"), "91")
+}
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), crc16("123456789"))
+}
+
+func TestParseSlipQR(t *testing.T) {
+	promptPay := withCRC(obj("00", "01")+obj("01", "12")+
+		obj("29", obj("00", "A000000677010111")+obj("01", "0066812345678"))+
+		obj("53", "764")+obj("54", "888.88")+obj("58", "TH")+
+		obj("62", obj("05", "014242082547BPM0498")), "63")
+
+	tests := []struct {
+		name    string
+		payload string
+		want    SlipQR
+		err     error
+	}{
+		{
+			name:    "bank slip verification",
+			payload: slipPayload("004", "014242082547BPM04988"),
+			want:    SlipQR{BankCode: "004", Bank: "KBANK", Reference: "014242082547BPM04988", Country: "TH"},
+		},
+		{
+			name:    "promptpay with amount",
+			payload: promptPay,
+			want:    SlipQR{Reference: "014242082547BPM0498", Amount: 888_88, Currency: "764", Country: "TH"},
+		},
+		{
+			name:    "unknown bank keeps its code",
+			payload: slipPayload("999", "REF1"),
+			want:    SlipQR{BankCode: "999", Reference: "REF1", Country: "TH"},
+		},
+		{
+			name:    "checksum mismatch",
+			payload: slipPayload("004", "014242082547BPM04988")[:len(slipPayload("004", "014242082547BPM04988"))-4] + "0000",
+			err:     ErrQRChecksum,
+		},
+		{
+			name:    "truncated",
+			payload: "0041000600",
+			err:     ErrMalformedQR,
+		},
+		{
+			name:    "not tlv",
+			payload: "https://example.com",
+			err:     ErrMalformedQR,
+		},
+		{
+			name:    "without reference",
+			payload: withCRC(obj("00", "01")+obj("01", "11")+obj("58", "TH"), "63"),
+			err:     ErrNotSlipQR,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSlipQR(tt.payload)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,72 +1,387 @@
 package eslip
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
-	"strings"
+	"strconv"
+	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
-func Upload(c echo.Context) error {
+// slipPath is where an uploaded slip can be fetched again.
+const slipPath = "/api/v1/slips/"
+
+// formOverhead is allowed on top of the files themselves for multipart
+// boundaries and headers.
+const formOverhead = 1 << 20
+
+var (
+	ErrNoFiles         = errors.New("no images to upload")
+	ErrTooManyFiles    = errors.New("too many images")
+	ErrRequestTooLarge = errors.New("request body too large")
+	ErrFileTooLarge    = errors.New("file too large")
+	ErrEmptyFile       = errors.New("file is empty")
+	ErrUnsupportedType = errors.New("unsupported file type, want PNG, JPEG, HEIC or PDF")
+	ErrInvalidForce    = errors.New("force must be true or false")
+	errStoreFailed     = errors.New("failed to store file")
+	errQueueFailed     = errors.New("file was stored but could not be queued for reading, upload it again")
+)
+
+// FileResult is the outcome of one uploaded file. Error is set instead of
+// Key and Location when the file was rejected. A Duplicate result points at
+// the slip the spender already uploaded with the same content. Status is
+// the state of the slip's processing job, see Status.
+type FileResult struct {
+	Filename      string `json:"filename"`
+	SlipID        int    `json:"slip_id,omitempty"`
+	Key           string `json:"key,omitempty"`
+	Location      string `json:"location,omitempty"`
+	Size          int64  `json:"size"`
+	SHA256        string `json:"sha256,omitempty"`
+	ContentType   string `json:"content_type,omitempty"`
+	Duplicate     bool   `json:"duplicate,omitempty"`
+	TransactionID *int   `json:"transaction_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type UploadResponse struct {
+	Message string       `json:"message"`
+	Files   []FileResult `json:"files"`
+}
+
+// StatusNone is reported for slips that were never queued for processing.
+const StatusNone = "none"
+
+type StatusResponse struct {
+	SlipID        int         `json:"slip_id"`
+	Key           string      `json:"key"`
+	Status        string      `json:"status"`
+	Attempts      int         `json:"attempts"`
+	LastError     string      `json:"last_error,omitempty"`
+	NextRunAt     *time.Time  `json:"next_run_at,omitempty"`
+	TransactionID *int        `json:"transaction_id"`
+	Extraction    *Extraction `json:"extraction,omitempty"`
+}
+
+type handler struct {
+	cfg   config.Upload
+	store blob.BlobStore
+	repo  Repository
+	jobs  job.Queue
+}
+
+type Handler interface {
+	Upload(c echo.Context) error
+	Get(c echo.Context) error
+	Status(c echo.Context) error
+}
+
+func NewHandler(cfg config.Upload, store blob.BlobStore, repo Repository, jobs job.Queue) Handler {
+	return handler{cfg: cfg, store: store, repo: repo, jobs: jobs}
+}
+
+// Upload stores every file of the "images" field under a fresh random key
+// and reports each file separately, so one bad file does not hide the ones
+// that were stored. Every stored slip is queued for processing, which
+// drafts a transaction from its QR code. Content the spender uploaded
+// before is not stored again unless ?force=true. It answers 200 when at
+// least one file was accepted and 422 when none were.
+func (h handler) Upload(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	force := false
+	if v := c.QueryParam("force"); v != "" {
+		var err error
+		force, err = strconv.ParseBool(v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidForce))
+		}
+	}
+
+	req := c.Request()
+	limit := int64(h.cfg.MaxFiles)*h.cfg.MaxFileSize + formOverhead
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, limit)
+
 	form, err := c.MultipartForm()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return c.JSON(http.StatusRequestEntityTooLarge, errs.Build(ErrRequestTooLarge))
+	}
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"message": "Failed to parse form",
 			"error":   err.Error(),
 		})
 	}
+	defer form.RemoveAll()
+
 	images := form.File["images"]
-	var locations []string
+	if len(images) == 0 {
+		return c.JSON(http.StatusBadRequest, errs.Build(ErrNoFiles))
+	}
+	if len(images) > h.cfg.MaxFiles {
+		return c.JSON(http.StatusBadRequest, errs.Build(fmt.Errorf("%w: %d, at most %d", ErrTooManyFiles, len(images), h.cfg.MaxFiles)))
+	}
+
+	accepted := 0
+	results := make([]FileResult, 0, len(images))
 	for _, image := range images {
-		fmt.Printf("Uploading file: %+v\n", image.Filename)
-		src, err := image.Open()
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"message": "Failed to parse form",
-				"error":   err.Error(),
-			})
+		result := h.storeFile(c, caller.SpenderID, image, force)
+		if result.Error == "" {
+			accepted++
 		}
-		defer src.Close()
+		results = append(results, result)
+	}
 
-		// upload to AWS S3 bucket
-		loc, err := UploadToS3(c, image.Filename, src)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"message": "Failed to upload image",
-				"error":   err.Error(),
-			})
+	res := UploadResponse{
+		Message: fmt.Sprintf("%d of %d images uploaded", accepted, len(images)),
+		Files:   results,
+	}
+	if accepted == 0 {
+		return c.JSON(http.StatusUnprocessableEntity, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// storeFile validates and stores a single file. Its type comes from the
+// file's magic bytes, never from the client's name or header, so a slip
+// cannot be served back as HTML.
+func (h handler) storeFile(c echo.Context, spenderID int, image *multipart.FileHeader, force bool) FileResult {
+	logger := mlog.L(c)
+	result := FileResult{Filename: image.Filename, Size: image.Size}
+	reject := func(err error) FileResult {
+		logger.Info("slip rejected", zap.String("filename", image.Filename), zap.Error(err))
+		result.Error = err.Error()
+		return result
+	}
+
+	if image.Size == 0 {
+		return reject(ErrEmptyFile)
+	}
+	if image.Size > h.cfg.MaxFileSize {
+		return reject(fmt.Errorf("%w: %d bytes, at most %d", ErrFileTooLarge, image.Size, h.cfg.MaxFileSize))
+	}
+
+	src, err := image.Open()
+	if err != nil {
+		return reject(err)
+	}
+	defer src.Close()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return reject(err)
+	}
+	typ, ok := sniff(head[:n])
+	if !ok {
+		return reject(ErrUnsupportedType)
+	}
+	result.ContentType = typ.ContentType
+
+	hash := sha256.New()
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return reject(err)
+	}
+	if _, err := io.Copy(hash, src); err != nil {
+		return reject(err)
+	}
+	result.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	if !force {
+		existing, err := h.repo.FindBySHA256(spenderID, result.SHA256)
+		if err == nil {
+			logger.Info("duplicate slip", zap.String("key", existing.Key), zap.String("filename", image.Filename))
+			if err := h.requeue(existing); err != nil {
+				logger.Error("requeue slip error", zap.String("key", existing.Key), zap.Error(err))
+				result.Error = errQueueFailed.Error()
+				return result
+			}
+			return duplicateResult(image.Filename, existing)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			logger.Error("find slip error", zap.Error(err))
+			result.Error = errStoreFailed.Error()
+			return result
 		}
-		locations = append(locations, loc)
 	}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"message":   "Image uploaded successfully",
-		"locations": strings.Join(locations, ","),
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return reject(err)
+	}
+	ctx := c.Request().Context()
+	key := blob.NewKey(typ.Ext)
+	if _, err := h.store.Put(ctx, key, src, image.Size, typ.ContentType); err != nil {
+		logger.Error("store slip error", zap.String("key", key), zap.Error(err))
+		result.Error = errStoreFailed.Error()
+		return result
+	}
+
+	slip, err := h.repo.Create(Slip{
+		SpenderID:   spenderID,
+		Key:         key,
+		SHA256:      result.SHA256,
+		ContentType: typ.ContentType,
+		Size:        image.Size,
+		Filename:    image.Filename,
 	})
+	if err != nil {
+		logger.Error("create slip error", zap.String("key", key), zap.Error(err))
+		// Without its record nobody can fetch the object, so drop it.
+		if err := h.store.Delete(ctx, key); err != nil {
+			logger.Error("delete orphaned slip error", zap.String("key", key), zap.Error(err))
+		}
+		result.Error = errStoreFailed.Error()
+		return result
+	}
+
+	logger.Info("slip stored", zap.String("key", key), zap.String("filename", image.Filename))
+	// A slip that is stored but not queued would never be read. Report the
+	// failure; uploading the file again queues the slip that was kept.
+	j, err := h.jobs.Enqueue(JobExtract, key)
+	if err != nil {
+		logger.Error("enqueue slip error", zap.String("key", key), zap.Error(err))
+		result.Error = errQueueFailed.Error()
+		return result
+	}
+	result.Status = j.Status
+	result.SlipID = slip.ID
+	result.Key = key
+	result.Location = slipPath + key
+	return result
+}
+
+// requeue queues a slip that has no transaction and was never queued, which
+// happens when queueing failed on its first upload.
+func (h handler) requeue(slip Slip) error {
+	if slip.TransactionID != nil {
+		return nil
+	}
+	_, err := h.jobs.Latest(JobExtract, slip.Key)
+	if !errors.Is(err, job.ErrNotFound) {
+		return err
+	}
+	_, err = h.jobs.Enqueue(JobExtract, slip.Key)
+	return err
+}
+
+func duplicateResult(filename string, slip Slip) FileResult {
+	return FileResult{
+		Filename:      filename,
+		SlipID:        slip.ID,
+		Key:           slip.Key,
+		Location:      slipPath + slip.Key,
+		Size:          slip.Size,
+		SHA256:        slip.SHA256,
+		ContentType:   slip.ContentType,
+		Duplicate:     true,
+		TransactionID: slip.TransactionID,
+	}
+}
+
+// Get streams a slip to its owner.
+func (h handler) Get(c echo.Context) error {
+	slip, err := h.ownSlip(c)
+	if err != nil {
+		return h.fail(c, "get slip error", err)
+	}
+
+	body, obj, err := h.store.Get(c.Request().Context(), slip.Key)
+	if err != nil {
+		return h.fail(c, "read slip error", err)
+	}
+	defer body.Close()
+
+	contentType := obj.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(obj.Size, 10))
+	res.Header().Set("X-Content-Type-Options", "nosniff")
+	res.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.WriteHeader(http.StatusOK)
+	_, err = io.Copy(res, body)
+	return err
+}
+
+// Status reports how far processing of the slip has got.
+func (h handler) Status(c echo.Context) error {
+	slip, err := h.ownSlip(c)
+	if err != nil {
+		return h.fail(c, "get slip error", err)
+	}
+
+	res := StatusResponse{SlipID: slip.ID, Key: slip.Key, Status: StatusNone, TransactionID: slip.TransactionID, Extraction: slip.Extraction}
+	j, err := h.jobs.Latest(JobExtract, slip.Key)
+	if err != nil && !errors.Is(err, job.ErrNotFound) {
+		return h.fail(c, "get slip job error", err)
+	}
+	if err == nil {
+		res.Status = j.Status
+		res.Attempts = j.Attempts
+		res.LastError = j.LastError
+		if j.Status == job.StatusPending {
+			res.NextRunAt = &j.RunAt
+		}
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// ownSlip returns the slip named in the path. Slips of other spenders are
+// reported as not found unless the caller may read any spender's
+// transactions.
+func (h handler) ownSlip(c echo.Context) (Slip, error) {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return Slip{}, auth.ErrUnauthenticated
+	}
+
+	key := c.Param("key")
+	if !blob.ValidKey(key) {
+		return Slip{}, blob.ErrInvalidKey
+	}
+
+	slip, err := h.repo.GetByKey(key)
+	if err != nil {
+		return Slip{}, err
+	}
+	if slip.SpenderID != caller.SpenderID && !caller.Can(auth.PermTransactionsReadAny) {
+		return Slip{}, ErrNotFound
+	}
+	return slip, nil
 }
 
-func UploadToS3(c echo.Context, filename string, src multipart.File) (string, error) {
-	// Assume that the file is uploaded to S3 bucket successfully
-	return "location/on/s3/bucket/" + filename, nil
-	// Create an AWS session
-	// sess, err := session.NewSession(&aws.Config{
-	// 	Region: aws.String(os.Getenv("AWS_REGION")),
-	// })
-	// if err != nil {
-	// 	return "", err
-	// }
-
-	// Upload the file to S3
-	// result, err = s3.New(sess).PutObject(&s3.PutObjectInput{
-	// 	Bucket: aws.String(os.Getenv("AWS_BUCKET")),
-	// 	Key:    aws.String(filename),
-	// 	Body:   src,
-	// })
-	// if err != nil {
-	// 	return "", err
-	// }
-
-	// return result.Location, nil
+// fail maps errors to their status code.
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return c.JSON(http.StatusUnauthorized, errs.Build(err))
+	case errors.Is(err, blob.ErrInvalidKey):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	case errors.Is(err, ErrNotFound), errors.Is(err, blob.ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	}
+
+	mlog.L(c).Error(msg, zap.String("key", c.Param("key")), zap.Error(err))
+	return c.JSON(http.StatusInternalServerError, errs.Build(err))
 }
@@ -1,32 +1,442 @@
 package eslip
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestUploadToS3(t *testing.T) {
-	t.Run("should able to upload image to S3 bucket", func(t *testing.T) {
-		f, _ := os.CreateTemp("", "eslip1.jpg")
-		defer f.Close()
-		defer os.Remove(f.Name())
+type upload struct {
+	name    string
+	content string
+}
 
-		e := echo.New()
-		defer e.Close()
+var owner = auth.Identity{UserID: 1, SpenderID: 1, Role: auth.RoleSpender}
+
+func newUploadContext(t *testing.T, target string, files ...upload) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for _, f := range files {
+		part, err := w.CreateFormFile("images", f.name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, owner)
+	return c, rec
+}
+
+func newGetContext(key string, caller auth.Identity) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/slips/"+key, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v1/slips/:key")
+	c.SetParamNames("key")
+	c.SetParamValues(key)
+	auth.SetIdentity(c, caller)
+	return c, rec
+}
+
+const (
+	pngData = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	pdfData = "%PDF-1.7\n"
+)
+
+var uploadConfig = config.Upload{MaxFileSize: 64, MaxFiles: 3}
+
+func newTestStore(t *testing.T) blob.BlobStore {
+	store, err := blob.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func decodeUpload(t *testing.T, rec *httptest.ResponseRecorder) UploadResponse {
+	var res UploadResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
+
+func TestUpload(t *testing.T) {
+	t.Run("should store images under random keys with their sniffed type", func(t *testing.T) {
+		store := newTestStore(t)
+		h := NewHandler(uploadConfig, store, NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"../../eslip1.html", pngData})
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		res := decodeUpload(t, rec)
+		require.Len(t, res.Files, 1)
+		file := res.Files[0]
+		assert.Empty(t, file.Error)
+		assert.True(t, blob.ValidKey(file.Key))
+		assert.True(t, strings.HasSuffix(file.Key, ".png"))
+		assert.Equal(t, "/api/v1/slips/"+file.Key, file.Location)
+		assert.Equal(t, "image/png", file.ContentType)
+		assert.Equal(t, int64(len(pngData)), file.Size)
+		sum := sha256.Sum256([]byte(pngData))
+		assert.Equal(t, hex.EncodeToString(sum[:]), file.SHA256)
+		assert.Equal(t, job.StatusPending, file.Status)
+
+		obj, err := store.Stat(context.Background(), file.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", obj.ContentType)
+	})
+
+	t.Run("should report each file when only some are accepted", func(t *testing.T) {
+		store := newTestStore(t)
+		h := NewHandler(uploadConfig, store, NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload",
+			upload{"slip.pdf", pdfData},
+			upload{"slip.png", "<html><script>alert(1)</script>"},
+			upload{"big.png", pngData + strings.Repeat("x", 64)},
+		)
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		res := decodeUpload(t, rec)
+		assert.Equal(t, "1 of 3 images uploaded", res.Message)
+		require.Len(t, res.Files, 3)
+		assert.Empty(t, res.Files[0].Error)
+		assert.Equal(t, "application/pdf", res.Files[0].ContentType)
+		assert.Equal(t, ErrUnsupportedType.Error(), res.Files[1].Error)
+		assert.Empty(t, res.Files[1].Key)
+		assert.Contains(t, res.Files[2].Error, ErrFileTooLarge.Error())
+
+		objects, err := store.List(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, objects, 1)
+	})
+
+	t.Run("should return unprocessable entity when no file is accepted", func(t *testing.T) {
+		h := NewHandler(uploadConfig, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"empty.png", ""})
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		res := decodeUpload(t, rec)
+		require.Len(t, res.Files, 1)
+		assert.Equal(t, ErrEmptyFile.Error(), res.Files[0].Error)
+	})
 
-		req := httptest.NewRequest(http.MethodPost, "/", nil)
+	t.Run("should return bad request when there are too many files", func(t *testing.T) {
+		h := NewHandler(uploadConfig, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload",
+			upload{"1.png", pngData}, upload{"2.png", pngData},
+			upload{"3.png", pngData}, upload{"4.png", pngData},
+		)
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), ErrTooManyFiles.Error())
+	})
+
+	t.Run("should return bad request when there are no files", func(t *testing.T) {
+		h := NewHandler(uploadConfig, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload")
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should return request entity too large when body exceeds the limit", func(t *testing.T) {
+		h := NewHandler(config.Upload{MaxFileSize: 64, MaxFiles: 1}, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"huge.png", pngData + strings.Repeat("x", 2<<20)})
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("should return bad request when body is not multipart", func(t *testing.T) {
+		h := NewHandler(uploadConfig, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", strings.NewReader("{}"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
+
 		c := e.NewContext(req, rec)
+		auth.SetIdentity(c, owner)
 
-		loc, err := UploadToS3(c, "eslip1.jpg", f)
+		err := h.Upload(c)
 
 		assert.NoError(t, err)
-		assert.Equal(t, "location/on/s3/bucket/eslip1.jpg", loc)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+// failingQueue is a queue that cannot take new jobs.
+type failingQueue struct {
+	job.Queue
+}
+
+func (failingQueue) Enqueue(kind, ref string) (job.Job, error) {
+	return job.Job{}, errors.New("queue unavailable")
+}
+
+func TestUpload_Deduplication(t *testing.T) {
+	t.Run("should return the existing slip when content was uploaded before", func(t *testing.T) {
+		store := newTestStore(t)
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		h := NewHandler(uploadConfig, store, repo, job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"e-slip1.png", pngData})
+		require.NoError(t, h.Upload(c))
+		first := decodeUpload(t, rec).Files[0]
+
+		c, rec = newUploadContext(t, "/api/v1/upload", upload{"e-slip1-again.png", pngData})
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		again := decodeUpload(t, rec).Files[0]
+		assert.True(t, again.Duplicate)
+		assert.Equal(t, first.SlipID, again.SlipID)
+		assert.Equal(t, first.Key, again.Key)
+		assert.Equal(t, "e-slip1-again.png", again.Filename)
+		objects, _ := store.List(context.Background(), "")
+		assert.Len(t, objects, 1)
+	})
+
+	t.Run("should queue the existing slip when queueing failed on its first upload", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		jobs := job.NewMemoryQueue()
+		h := NewHandler(uploadConfig, newTestStore(t), repo, failingQueue{jobs})
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"e-slip1.png", pngData})
+		require.NoError(t, h.Upload(c))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, errQueueFailed.Error(), decodeUpload(t, rec).Files[0].Error)
+
+		h = NewHandler(uploadConfig, newTestStore(t), repo, jobs)
+		c, rec = newUploadContext(t, "/api/v1/upload", upload{"e-slip1-again.png", pngData})
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		file := decodeUpload(t, rec).Files[0]
+		assert.True(t, file.Duplicate)
+		queued, err := jobs.Latest(JobExtract, file.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, job.StatusPending, queued.Status)
 	})
 
+	t.Run("should link the transaction of the existing slip", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		transactionID := 7
+		sum := sha256.Sum256([]byte(pngData))
+		_, err := repo.Create(Slip{SpenderID: owner.SpenderID, Key: "old.png", SHA256: hex.EncodeToString(sum[:]), TransactionID: &transactionID})
+		require.NoError(t, err)
+		h := NewHandler(uploadConfig, newTestStore(t), repo, job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"e-slip1.png", pngData})
+
+		err = h.Upload(c)
+
+		assert.NoError(t, err)
+		file := decodeUpload(t, rec).Files[0]
+		assert.True(t, file.Duplicate)
+		assert.Equal(t, "old.png", file.Key)
+		assert.Equal(t, &transactionID, file.TransactionID)
+	})
+
+	t.Run("should not match slips of other spenders", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		sum := sha256.Sum256([]byte(pngData))
+		_, err := repo.Create(Slip{SpenderID: 2, Key: "other.png", SHA256: hex.EncodeToString(sum[:])})
+		require.NoError(t, err)
+		h := NewHandler(uploadConfig, newTestStore(t), repo, job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload", upload{"e-slip1.png", pngData})
+
+		err = h.Upload(c)
+
+		assert.NoError(t, err)
+		file := decodeUpload(t, rec).Files[0]
+		assert.False(t, file.Duplicate)
+		assert.NotEqual(t, "other.png", file.Key)
+	})
+
+	t.Run("should store again when forced", func(t *testing.T) {
+		store := newTestStore(t)
+		h := NewHandler(uploadConfig, store, NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, _ := newUploadContext(t, "/api/v1/upload", upload{"e-slip1.png", pngData})
+		require.NoError(t, h.Upload(c))
+
+		c, rec := newUploadContext(t, "/api/v1/upload?force=true", upload{"e-slip1.png", pngData})
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		file := decodeUpload(t, rec).Files[0]
+		assert.False(t, file.Duplicate)
+		objects, _ := store.List(context.Background(), "")
+		assert.Len(t, objects, 2)
+	})
+
+	t.Run("should return bad request when force is invalid", func(t *testing.T) {
+		h := NewHandler(uploadConfig, newTestStore(t), NewMemoryRepository(transaction.NewMemoryRepository()), job.NewMemoryQueue())
+		c, rec := newUploadContext(t, "/api/v1/upload?force=maybe", upload{"e-slip1.png", pngData})
+
+		err := h.Upload(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestGet(t *testing.T) {
+	store := newTestStore(t)
+	_, err := store.Put(context.Background(), "slip.png", strings.NewReader("fake-png"), 8, "image/png")
+	require.NoError(t, err)
+	repo := NewMemoryRepository(transaction.NewMemoryRepository())
+	_, err = repo.Create(Slip{SpenderID: owner.SpenderID, Key: "slip.png"})
+	require.NoError(t, err)
+	h := NewHandler(uploadConfig, store, repo, job.NewMemoryQueue())
+
+	t.Run("should stream stored slip", func(t *testing.T) {
+		c, rec := newGetContext("slip.png", owner)
+
+		err := h.Get(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "fake-png", rec.Body.String())
+	})
+
+	t.Run("should return not found when slip belongs to another spender", func(t *testing.T) {
+		c, rec := newGetContext("slip.png", auth.Identity{UserID: 2, SpenderID: 2, Role: auth.RoleSpender})
+
+		err := h.Get(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should let admin read any slip", func(t *testing.T) {
+		c, rec := newGetContext("slip.png", auth.Identity{UserID: 3, SpenderID: 3, Role: auth.RoleAdmin})
+
+		err := h.Get(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should return not found when slip is missing", func(t *testing.T) {
+		c, rec := newGetContext("missing.png", owner)
+
+		err := h.Get(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should return bad request when key is invalid", func(t *testing.T) {
+		c, rec := newGetContext("..", owner)
+
+		err := h.Get(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestStatus(t *testing.T) {
+	newStatusContext := func(key string, caller auth.Identity) (echo.Context, *httptest.ResponseRecorder) {
+		c, rec := newGetContext(key, caller)
+		c.SetPath("/api/v1/slips/:key/status")
+		return c, rec
+	}
+	decodeStatus := func(rec *httptest.ResponseRecorder) StatusResponse {
+		var res StatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		return res
+	}
+
+	t.Run("should report the latest job of the slip", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: owner.SpenderID, Key: "slip.png"})
+		jobs := job.NewMemoryQueue()
+		jobs.Enqueue(JobExtract, "slip.png")
+		claimed, _ := jobs.Claim(time.Minute)
+		jobs.Retry(claimed, "blob store unavailable", time.Now().Add(time.Minute))
+		h := NewHandler(uploadConfig, newTestStore(t), repo, jobs)
+		c, rec := newStatusContext("slip.png", owner)
+
+		err := h.Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		res := decodeStatus(rec)
+		assert.Equal(t, job.StatusPending, res.Status)
+		assert.Equal(t, 1, res.Attempts)
+		assert.Equal(t, "blob store unavailable", res.LastError)
+		assert.NotNil(t, res.NextRunAt)
+	})
+
+	t.Run("should report none for a slip that was never queued", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: owner.SpenderID, Key: "slip.png"})
+		h := NewHandler(uploadConfig, newTestStore(t), repo, job.NewMemoryQueue())
+		c, rec := newStatusContext("slip.png", owner)
+
+		err := h.Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusNone, decodeStatus(rec).Status)
+	})
+
+	t.Run("should return not found for another spender's slip", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: 2, Key: "slip.png"})
+		h := NewHandler(uploadConfig, newTestStore(t), repo, job.NewMemoryQueue())
+		c, rec := newStatusContext("slip.png", owner)
+
+		err := h.Status(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func listTransactions(t *testing.T, transactions transaction.Repository, spenderID int) []transaction.Transaction {
+	t.Helper()
+
+	all, err := transactions.GetAll(spenderID, transaction.Filter{}, transaction.Pagination{ItemPerPage: 100, Page: 1})
+	require.NoError(t, err)
+	return all
 }
@@ -0,0 +1,128 @@
+package eslip
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+// Field is one value read from a slip and how sure the reader is of it,
+// from 0 (not read) to 1 (read from the slip's QR code).
+type Field struct {
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Extraction is what was read from a slip. Amount is in baht with two
+// decimals and Date is RFC 3339; fields that could not be read are empty.
+type Extraction struct {
+	Amount    Field `json:"amount"`
+	Date      Field `json:"date"`
+	Sender    Field `json:"sender"`
+	Receiver  Field `json:"receiver"`
+	Bank      Field `json:"bank"`
+	Reference Field `json:"reference"`
+}
+
+// Extractor reads the fields of a slip image.
+type Extractor interface {
+	Extract(ctx context.Context, image []byte) (Extraction, error)
+}
+
+// OCR turns an image into text.
+type OCR interface {
+	Text(ctx context.Context, image []byte) (string, error)
+}
+
+type textExtractor struct {
+	ocr OCR
+}
+
+// NewExtractor reads slips with ocr and parses the text with ParseSlipText.
+func NewExtractor(ocr OCR) Extractor {
+	return textExtractor{ocr: ocr}
+}
+
+func (e textExtractor) Extract(ctx context.Context, image []byte) (Extraction, error) {
+	text, err := e.ocr.Text(ctx, image)
+	if err != nil {
+		return Extraction{}, err
+	}
+	return ParseSlipText(text), nil
+}
+
+// Confidence is that of the least certain field a transaction needs: the
+// amount, the date and the reference.
+func (e Extraction) Confidence() float64 {
+	return min(e.Amount.Confidence, e.Date.Confidence, e.Reference.Confidence)
+}
+
+// Request maps the extraction onto a transaction of the slip's spender. The
+// transaction is a draft for the spender to confirm unless Confidence is at
+// least minConfidence. A date that was not read is taken as now.
+func (e Extraction) Request(slip Slip, minConfidence float64, now time.Time) transaction.CreateTransactionRequest {
+	date := now
+	if d, err := time.Parse(time.RFC3339, e.Date.Value); err == nil {
+		date = d
+	}
+	amount, _ := money.Round(e.Amount.Value)
+
+	var note []string
+	if e.Bank.Value != "" {
+		note = append(note, e.Bank.Value)
+	}
+	if e.Reference.Value != "" {
+		note = append(note, "ref "+e.Reference.Value)
+	}
+	if e.Receiver.Value != "" {
+		note = append(note, "to "+e.Receiver.Value)
+	}
+
+	status := transaction.StatusDraft
+	if e.Confidence() >= minConfidence {
+		status = transaction.StatusConfirmed
+	}
+
+	return transaction.CreateTransactionRequest{
+		Date:      &date,
+		Amount:    amount,
+		Currency:  "THB",
+		ImageUrl:  slipPath + slip.Key,
+		Note:      strings.Join(note, " "),
+		SpenderId: slip.SpenderID,
+		TxnType:   "expense",
+		Status:    status,
+	}
+}
+
+// overlay returns e with the fields that top has a value for replaced by
+// those of top.
+func (e Extraction) overlay(top Extraction) Extraction {
+	dst, src := e.fields(), top.fields()
+	for i := range dst {
+		if src[i].Value != "" {
+			*dst[i] = *src[i]
+		}
+	}
+	return e
+}
+
+func (e *Extraction) fields() []*Field {
+	return []*Field{&e.Amount, &e.Date, &e.Sender, &e.Receiver, &e.Bank, &e.Reference}
+}
+
+// qrExtraction is what a slip's QR code says. The code is checksummed, so
+// its fields are certain.
+func qrExtraction(qr SlipQR) Extraction {
+	e := Extraction{Reference: Field{Value: qr.Reference, Confidence: 1}}
+	if qr.Bank != "" {
+		e.Bank = Field{Value: qr.Bank, Confidence: 1}
+	}
+	if qr.Amount > 0 {
+		e.Amount = Field{Value: qr.Amount.String(), Confidence: 1}
+	}
+	return e
+}
@@ -0,0 +1,119 @@
+package eslip
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOCR struct {
+	text string
+	err  error
+}
+
+func (o fakeOCR) Text(context.Context, []byte) (string, error) {
+	return o.text, o.err
+}
+
+func TestExtractionRequest(t *testing.T) {
+	now := time.Date(2024, time.May, 20, 9, 0, 0, 0, time.UTC)
+	slip := Slip{Key: "a.png", SpenderID: 3}
+	read := Extraction{
+		Amount:    Field{"350.50", 0.9},
+		Date:      Field{"2024-05-18T10:30:00+07:00", 0.9},
+		Receiver:  Field{"บริษัท ตัวอย่าง จำกัด", 0.9},
+		Bank:      Field{"SCB", 0.9},
+		Reference: Field{"202405181030ABC1234", 0.9},
+	}
+
+	t.Run("should confirm a confident extraction", func(t *testing.T) {
+		got := read.Request(slip, 0.8, now)
+
+		date := time.Date(2024, time.May, 18, 10, 30, 0, 0, slipZone)
+		assert.True(t, date.Equal(*got.Date))
+		assert.Equal(t, money.Amount(350_50), got.Amount)
+		assert.Equal(t, "SCB ref 202405181030ABC1234 to บริษัท ตัวอย่าง จำกัด", got.Note)
+		assert.Equal(t, "/api/v1/slips/a.png", got.ImageUrl)
+		assert.Equal(t, 3, got.SpenderId)
+		assert.Equal(t, "expense", got.TxnType)
+		assert.Equal(t, transaction.StatusConfirmed, got.Status)
+	})
+
+	t.Run("should keep a draft when a needed field is uncertain", func(t *testing.T) {
+		uncertain := read
+		uncertain.Amount.Confidence = 0.5
+
+		got := uncertain.Request(slip, 0.8, now)
+
+		assert.Equal(t, transaction.StatusDraft, got.Status)
+	})
+
+	t.Run("should date a slip without a readable date now", func(t *testing.T) {
+		undated := read
+		undated.Date = Field{}
+
+		got := undated.Request(slip, 0.8, now)
+
+		assert.Equal(t, now, *got.Date)
+		assert.Equal(t, transaction.StatusDraft, got.Status)
+	})
+}
+
+func TestExtractionOverlay(t *testing.T) {
+	ocr := Extraction{Amount: Field{"120.00", 0.5}, Reference: Field{"0123", 0.5}, Bank: Field{"SCB", 0.9}}
+	qr := qrExtraction(SlipQR{Bank: "KBANK", Reference: "014242082547BPM04988"})
+
+	got := ocr.overlay(qr)
+
+	assert.Equal(t, Extraction{
+		Amount:    Field{"120.00", 0.5},
+		Bank:      Field{"KBANK", 1},
+		Reference: Field{"014242082547BPM04988", 1},
+	}, got)
+}
+
+func TestExtractor(t *testing.T) {
+	t.Run("should parse the recognized text", func(t *testing.T) {
+		e := NewExtractor(fakeOCR{text: "SCB\nจำนวนเงิน 10.00"})
+
+		got, err := e.Extract(context.Background(), []byte(pngData))
+
+		assert.NoError(t, err)
+		assert.Equal(t, Field{"SCB", templateConfidence}, got.Bank)
+		assert.Equal(t, Field{"10.00", templateConfidence}, got.Amount)
+	})
+
+	t.Run("should return OCR errors", func(t *testing.T) {
+		e := NewExtractor(fakeOCR{err: errors.New("ocr down")})
+
+		_, err := e.Extract(context.Background(), []byte(pngData))
+
+		assert.EqualError(t, err, "ocr down")
+	})
+}
+
+func TestTesseract(t *testing.T) {
+	dir := t.TempDir()
+	command := filepath.Join(dir, "tesseract")
+	// Echoes the arguments and the image back, like tesseract would print the
+	// recognized text.
+	script := "#!/bin/sh\necho \"$@\"\ncat\n"
+	require.NoError(t, os.WriteFile(command, []byte(script), 0o755))
+
+	text, err := NewTesseract(command, "tha+eng").Text(context.Background(), []byte("image"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "stdin stdout -l tha+eng\nimage", text)
+
+	_, err = NewTesseract(filepath.Join(dir, "missing"), "tha").Text(context.Background(), []byte("image"))
+
+	assert.ErrorContains(t, err, "tesseract:")
+}
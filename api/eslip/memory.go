@@ -0,0 +1,154 @@
+package eslip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+type memoryRepository struct {
+	mu           sync.Mutex
+	slips        map[int]Slip
+	nextID       int
+	transactions Transactions
+}
+
+// NewMemoryRepository keeps slip records in process memory for unit tests
+// and the --storage=memory server mode. The transactions of slips are
+// created in transactions.
+func NewMemoryRepository(transactions Transactions) Repository {
+	return &memoryRepository{
+		slips:        map[int]Slip{},
+		nextID:       1,
+		transactions: transactions,
+	}
+}
+
+func (r *memoryRepository) Create(slip Slip) (Slip, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slip.ID = r.nextID
+	slip.CreatedAt = time.Now()
+	r.nextID++
+	r.slips[slip.ID] = slip
+
+	return slip, nil
+}
+
+func (r *memoryRepository) GetByKey(key string) (Slip, error) {
+	return r.first(func(s Slip) bool { return s.Key == key })
+}
+
+func (r *memoryRepository) FindBySHA256(spenderID int, sha256 string) (Slip, error) {
+	return r.first(func(s Slip) bool { return s.SpenderID == spenderID && s.SHA256 == sha256 })
+}
+
+func (r *memoryRepository) FindByReference(spenderID int, reference string) (Slip, error) {
+	return r.first(func(s Slip) bool {
+		return s.SpenderID == spenderID && s.Reference == reference && s.TransactionID != nil
+	})
+}
+
+func (r *memoryRepository) Link(id int, reference string, transactionID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.slips[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.Reference = reference
+	s.TransactionID = &transactionID
+	r.slips[id] = s
+
+	return nil
+}
+
+// CreateTransaction holds the lock while creating the transaction, so
+// concurrent runs cannot both create one for the slip.
+func (r *memoryRepository) CreateTransaction(id int, reference string, request transaction.CreateTransactionRequest) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.slips[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if s.TransactionID != nil {
+		return *s.TransactionID, nil
+	}
+	created, err := r.transactions.Create(request)
+	if err != nil {
+		return 0, err
+	}
+	s.Reference = reference
+	s.TransactionID = &created.ID
+	r.slips[id] = s
+
+	return created.ID, nil
+}
+
+func (r *memoryRepository) SaveExtraction(id int, extraction Extraction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.slips[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.Extraction = &extraction
+	r.slips[id] = s
+
+	return nil
+}
+
+func (r *memoryRepository) ClaimExtraction(id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.slips[id]
+	if !ok {
+		return false, ErrNotFound
+	}
+	if s.ExtractedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	s.ExtractedAt = &now
+	r.slips[id] = s
+
+	return true, nil
+}
+
+func (r *memoryRepository) ReleaseExtraction(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.slips[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.ExtractedAt = nil
+	r.slips[id] = s
+
+	return nil
+}
+
+// first returns the matching slip with the lowest id, like the SQL queries.
+func (r *memoryRepository) first(match func(s Slip) bool) (Slip, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := Slip{}
+	for _, s := range r.slips {
+		if match(s) && (found.ID == 0 || s.ID < found.ID) {
+			found = s
+		}
+	}
+	if found.ID == 0 {
+		return Slip{}, ErrNotFound
+	}
+	return found, nil
+}
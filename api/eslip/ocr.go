@@ -0,0 +1,34 @@
+package eslip
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type tesseract struct {
+	command   string
+	languages string
+}
+
+// NewTesseract runs the local tesseract command, a stand-in for a hosted OCR
+// service that needs no network access. languages is tesseract's -l value,
+// e.g. "tha+eng"; its trained data must be installed.
+func NewTesseract(command, languages string) OCR {
+	return tesseract{command: command, languages: languages}
+}
+
+func (t tesseract) Text(ctx context.Context, image []byte) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.command, "stdin", "stdout", "-l", t.languages)
+	cmd.Stdin = bytes.NewReader(image)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
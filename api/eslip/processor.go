@@ -0,0 +1,155 @@
+package eslip
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"go.uber.org/zap"
+)
+
+// JobExtract is the job that reads a stored slip. Its ref is the slip key.
+const JobExtract = "slip.extract"
+
+// Transactions records the transactions read from slips.
+type Transactions interface {
+	Get(spenderId int, id int) (transaction.Transaction, error)
+	Create(request transaction.CreateTransactionRequest) (transaction.CreateTransactionResponse, error)
+	UpdateExpense(spenderId int, transaction transaction.Transaction) error
+}
+
+type processor struct {
+	maxFileSize   int64
+	store         blob.BlobStore
+	repo          Repository
+	extractor     Extractor
+	minConfidence float64
+	logger        *zap.Logger
+}
+
+type Processor interface {
+	Process(ctx context.Context, j job.Job) error
+}
+
+// NewProcessor reads slips with their QR code and, when extractor is not
+// nil, with OCR. Transactions read with less than minConfidence are created
+// as drafts.
+func NewProcessor(maxFileSize int64, store blob.BlobStore, repo Repository, extractor Extractor, minConfidence float64, logger *zap.Logger) Processor {
+	return processor{
+		maxFileSize:   maxFileSize,
+		store:         store,
+		repo:          repo,
+		extractor:     extractor,
+		minConfidence: minConfidence,
+		logger:        logger,
+	}
+}
+
+// Process reads the slip and creates a transaction for it, or links the
+// slip to the transaction of an earlier slip of the same transfer. Fields
+// of the QR code take precedence over those read by OCR. Slips nothing
+// could be read from are left for manual entry and complete without error;
+// storage failures, and OCR failures on slips without a QR code, are
+// retried.
+func (p processor) Process(ctx context.Context, j job.Job) error {
+	logger := p.logger.With(zap.String("key", j.Ref))
+
+	slip, err := p.repo.GetByKey(j.Ref)
+	if errors.Is(err, ErrNotFound) {
+		logger.Info("slip is gone, nothing to process")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if slip.TransactionID != nil {
+		return nil
+	}
+	if slip.ContentType != typePNG.ContentType && slip.ContentType != typeJPEG.ContentType {
+		return nil
+	}
+
+	body, _, err := p.store.Get(ctx, slip.Key)
+	if err != nil {
+		return err
+	}
+	img, err := io.ReadAll(io.LimitReader(body, p.maxFileSize))
+	body.Close()
+	if err != nil {
+		return err
+	}
+
+	extraction, err := p.extract(ctx, img, logger)
+	if err != nil {
+		return err
+	}
+	if extraction.Amount.Value == "" && extraction.Reference.Value == "" {
+		logger.Info("nothing read from slip")
+		return nil
+	}
+	if err := p.repo.SaveExtraction(slip.ID, extraction); err != nil {
+		return err
+	}
+
+	reference := extraction.Reference.Value
+	id, err := p.link(slip, reference, extraction)
+	if err != nil {
+		return err
+	}
+	logger.Info("slip linked", zap.Int("transaction_id", id), zap.String("reference", reference),
+		zap.Float64("confidence", extraction.Confidence()))
+	return nil
+}
+
+// extract reads the slip's QR code and lays it over what OCR read. An OCR
+// failure is only returned when there is no QR code to fall back on.
+func (p processor) extract(ctx context.Context, img []byte, logger *zap.Logger) (Extraction, error) {
+	var fromQR Extraction
+	payload, err := decodeQR(bytes.NewReader(img))
+	if err == nil {
+		var qr SlipQR
+		qr, err = ParseSlipQR(payload)
+		if err == nil {
+			fromQR = qrExtraction(qr)
+		}
+	}
+	if err != nil {
+		logger.Info("no slip QR code", zap.Error(err))
+	}
+
+	if p.extractor == nil {
+		return fromQR, nil
+	}
+	read, err := p.extractor.Extract(ctx, img)
+	if err != nil {
+		if fromQR.Reference.Value == "" {
+			return Extraction{}, err
+		}
+		logger.Warn("slip OCR failed, using its QR code only", zap.Error(err))
+		return fromQR, nil
+	}
+	return read.overlay(fromQR), nil
+}
+
+// link links the slip to the transaction of an earlier slip with the same
+// reference, or to one created from the extraction. The transaction is
+// created and linked together, so a run retried after a failure finds the
+// slip linked instead of creating another draft.
+func (p processor) link(slip Slip, reference string, extraction Extraction) (int, error) {
+	if reference != "" {
+		existing, err := p.repo.FindByReference(slip.SpenderID, reference)
+		if err == nil {
+			return *existing.TransactionID, p.repo.Link(slip.ID, reference, *existing.TransactionID)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return 0, err
+		}
+	}
+
+	return p.repo.CreateTransaction(slip.ID, reference, extraction.Request(slip, p.minConfidence, time.Now()))
+}
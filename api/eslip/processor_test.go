@@ -0,0 +1,197 @@
+package eslip
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// qrPNG renders payload as a size x size QR code image.
+func qrPNG(t *testing.T, payload string, size int) string {
+	t.Helper()
+
+	matrix, err := qrcode.NewQRCodeWriter().Encode(payload, gozxing.BarcodeFormat_QR_CODE, size, size, nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, matrix))
+	return buf.String()
+}
+
+// putSlip stores content as a PNG slip of the test spender.
+func putSlip(t *testing.T, store blob.BlobStore, repo Repository, key, content string) Slip {
+	t.Helper()
+
+	_, err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), typePNG.ContentType)
+	require.NoError(t, err)
+	slip, err := repo.Create(Slip{SpenderID: owner.SpenderID, Key: key, ContentType: typePNG.ContentType, Size: int64(len(content))})
+	require.NoError(t, err)
+	return slip
+}
+
+func TestDecodeQR(t *testing.T) {
+	payload := slipPayload("014", "2024051812345")
+
+	text, err := decodeQR(bytes.NewReader([]byte(qrPNG(t, payload, 240))))
+
+	assert.NoError(t, err)
+	assert.Equal(t, payload, text)
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should create a draft transaction linked to the slip", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", qrPNG(t, slipPayload("004", "014242082547BPM04988"), 240))
+		p := NewProcessor(1<<20, store, repo, nil, 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.NoError(t, err)
+		drafts := listTransactions(t, transactions, owner.SpenderID)
+		require.Len(t, drafts, 1)
+		assert.Equal(t, transaction.StatusDraft, drafts[0].Status)
+		assert.Equal(t, "KBANK ref 014242082547BPM04988", drafts[0].Note)
+		assert.Equal(t, "/api/v1/slips/slip.png", drafts[0].ImageUrl)
+		assert.NotNil(t, drafts[0].Date)
+
+		slip, err := repo.GetByKey("slip.png")
+		require.NoError(t, err)
+		assert.Equal(t, "014242082547BPM04988", slip.Reference)
+		assert.Equal(t, &drafts[0].ID, slip.TransactionID)
+	})
+
+	t.Run("should reuse the transaction of another slip of the same transfer", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		payload := slipPayload("014", "2024051812345")
+		putSlip(t, store, repo, "screenshot.png", qrPNG(t, payload, 240))
+		putSlip(t, store, repo, "photo.png", qrPNG(t, payload, 300))
+		p := NewProcessor(1<<20, store, repo, nil, 0.8, zap.NewNop())
+
+		require.NoError(t, p.Process(ctx, job.Job{Kind: JobExtract, Ref: "screenshot.png"}))
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "photo.png"})
+
+		assert.NoError(t, err)
+		first, _ := repo.GetByKey("screenshot.png")
+		second, _ := repo.GetByKey("photo.png")
+		assert.Equal(t, first.TransactionID, second.TransactionID)
+		all := listTransactions(t, transactions, owner.SpenderID)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("should create one transaction when two runs race on a slip", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		slip := putSlip(t, store, repo, "slip.png", pngData)
+		request := transaction.CreateTransactionRequest{Amount: 75_00, SpenderId: owner.SpenderID, TxnType: "expense", Status: transaction.StatusDraft}
+
+		first, err := repo.CreateTransaction(slip.ID, "", request)
+		require.NoError(t, err)
+		second, err := repo.CreateTransaction(slip.ID, "", request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Len(t, listTransactions(t, transactions, owner.SpenderID), 1)
+	})
+
+	t.Run("should leave slips without a QR code alone", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", qrPNG(t, "https://example.com", 240))
+		p := NewProcessor(1<<20, store, repo, nil, 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.NoError(t, err)
+		slip, _ := repo.GetByKey("slip.png")
+		assert.Nil(t, slip.TransactionID)
+		all := listTransactions(t, transactions, owner.SpenderID)
+		assert.Empty(t, all)
+	})
+
+	t.Run("should confirm a transaction read confidently by OCR", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", pngData)
+		ocr := fakeOCR{text: "SCB\n18 พ.ค. 2567 - 10:30\nรหัสอ้างอิง: 202405181030ABC1234\nจำนวนเงิน\n350.50"}
+		p := NewProcessor(1<<20, store, repo, NewExtractor(ocr), 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.NoError(t, err)
+		all := listTransactions(t, transactions, owner.SpenderID)
+		require.Len(t, all, 1)
+		assert.Equal(t, transaction.StatusConfirmed, all[0].Status)
+		assert.Equal(t, money.Amount(350_50), all[0].Amount)
+		slip, _ := repo.GetByKey("slip.png")
+		assert.Equal(t, "202405181030ABC1234", slip.Reference)
+		require.NotNil(t, slip.Extraction)
+		assert.Equal(t, Field{"350.50", templateConfidence}, slip.Extraction.Amount)
+	})
+
+	t.Run("should keep the QR reference and take the amount from OCR as a draft", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", qrPNG(t, slipPayload("004", "014242082547BPM04988"), 240))
+		ocr := fakeOCR{text: "จำนวนเงิน 75.00 บาท\nRef. 99999999"}
+		p := NewProcessor(1<<20, store, repo, NewExtractor(ocr), 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.NoError(t, err)
+		all := listTransactions(t, transactions, owner.SpenderID)
+		require.Len(t, all, 1)
+		assert.Equal(t, transaction.StatusDraft, all[0].Status)
+		assert.Equal(t, money.Amount(75_00), all[0].Amount)
+		assert.Equal(t, "KBANK ref 014242082547BPM04988", all[0].Note)
+	})
+
+	t.Run("should fall back to the QR code when OCR fails", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", qrPNG(t, slipPayload("004", "014242082547BPM04988"), 240))
+		p := NewProcessor(1<<20, store, repo, NewExtractor(fakeOCR{err: errors.New("ocr down")}), 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.NoError(t, err)
+		slip, _ := repo.GetByKey("slip.png")
+		assert.NotNil(t, slip.TransactionID)
+	})
+
+	t.Run("should fail so the job is retried when OCR fails on a slip without a QR code", func(t *testing.T) {
+		store, transactions := newTestStore(t), transaction.NewMemoryRepository()
+		repo := NewMemoryRepository(transactions)
+		putSlip(t, store, repo, "slip.png", pngData)
+		p := NewProcessor(1<<20, store, repo, NewExtractor(fakeOCR{err: errors.New("ocr down")}), 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.EqualError(t, err, "ocr down")
+	})
+
+	t.Run("should fail so the job is retried when the image is missing", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: owner.SpenderID, Key: "slip.png", ContentType: typePNG.ContentType})
+		p := NewProcessor(1<<20, newTestStore(t), repo, nil, 0.8, zap.NewNop())
+
+		err := p.Process(ctx, job.Job{Kind: JobExtract, Ref: "slip.png"})
+
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	})
+}
@@ -0,0 +1,49 @@
+package eslip
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// maxQRPixels bounds the images decodeQR will decompress; a small file can
+// declare huge dimensions.
+const maxQRPixels = 40_000_000
+
+var ErrNoQRCode = errors.New("no QR code found")
+
+// decodeQR returns the text of the QR code in a PNG or JPEG image.
+func decodeQR(r io.ReadSeeker) (string, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return "", err
+	}
+	if cfg.Width*cfg.Height > maxQRPixels {
+		return "", fmt.Errorf("image too large to scan: %dx%d", cfg.Width, cfg.Height)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return "", err
+	}
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", err
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	result, err := qrcode.NewQRCodeReader().Decode(bmp, hints)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrNoQRCode, err)
+	}
+
+	return result.GetText(), nil
+}
@@ -0,0 +1,196 @@
+package eslip
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+type Repository interface {
+	Create(slip Slip) (Slip, error)
+	GetByKey(key string) (Slip, error)
+	FindBySHA256(spenderID int, sha256 string) (Slip, error)
+	FindByReference(spenderID int, reference string) (Slip, error)
+	Link(id int, reference string, transactionID int) error
+	// CreateTransaction creates the slip's transaction and links it in one
+	// step. When the slip is already linked it creates nothing and returns
+	// the linked transaction, so a retried job cannot add a second one.
+	CreateTransaction(id int, reference string, request transaction.CreateTransactionRequest) (int, error)
+	SaveExtraction(id int, extraction Extraction) error
+	ClaimExtraction(id int) (bool, error)
+	ReleaseExtraction(id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const selectSlipQuery = `SELECT id, spender_id, key, sha256, content_type, size, filename, reference, transaction_id, extracted_at, extraction, created_at FROM slip`
+
+func (r repository) Create(slip Slip) (Slip, error) {
+	query := `INSERT INTO slip (spender_id, key, sha256, content_type, size, filename) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at;`
+	err := r.db.QueryRow(query, slip.SpenderID, slip.Key, slip.SHA256, slip.ContentType, slip.Size, slip.Filename).Scan(&slip.ID, &slip.CreatedAt)
+	if err != nil {
+		return Slip{}, err
+	}
+
+	return slip, nil
+}
+
+func (r repository) GetByKey(key string) (Slip, error) {
+	return r.get(selectSlipQuery+` WHERE key = $1`, key)
+}
+
+// FindBySHA256 returns the spender's first slip with the given content.
+func (r repository) FindBySHA256(spenderID int, sha256 string) (Slip, error) {
+	return r.get(selectSlipQuery+` WHERE spender_id = $1 AND sha256 = $2 ORDER BY id LIMIT 1`, spenderID, sha256)
+}
+
+// FindByReference returns the spender's first slip of the same transfer
+// that already has a transaction.
+func (r repository) FindByReference(spenderID int, reference string) (Slip, error) {
+	return r.get(selectSlipQuery+` WHERE spender_id = $1 AND reference = $2 AND transaction_id IS NOT NULL ORDER BY id LIMIT 1`, spenderID, reference)
+}
+
+func (r repository) Link(id int, reference string, transactionID int) error {
+	result, err := r.db.Exec(`UPDATE slip SET reference = $1, transaction_id = $2 WHERE id = $3`, reference, transactionID, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// insertTransactionQuery takes the currency of the spender when the request
+// names none, as the transaction repository does.
+const insertTransactionQuery = `INSERT INTO transaction (date, amount, category, transaction_type, note, image_url, spender_id, status, currency) ` +
+	`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), (SELECT base_currency FROM spender WHERE id = $7))) RETURNING id`
+
+// CreateTransaction locks the slip row so that of two runs racing on the
+// same slip only the first creates a transaction.
+func (r repository) CreateTransaction(id int, reference string, request transaction.CreateTransactionRequest) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var linked *int
+	err = tx.QueryRow(`SELECT transaction_id FROM slip WHERE id = $1 FOR UPDATE`, id).Scan(&linked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if linked != nil {
+		return *linked, nil
+	}
+
+	var created int
+	err = tx.QueryRow(insertTransactionQuery, request.Date, request.Amount, request.Category, request.TxnType, request.Note,
+		request.ImageUrl, request.SpenderId, request.Status, request.Currency).Scan(&created)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE slip SET reference = $1, transaction_id = $2 WHERE id = $3`, reference, created, id); err != nil {
+		return 0, err
+	}
+
+	return created, tx.Commit()
+}
+
+// SaveExtraction keeps what was read from the slip, with the confidence of
+// each field, for the spender to review.
+func (r repository) SaveExtraction(id int, extraction Extraction) error {
+	data, err := json.Marshal(extraction)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`UPDATE slip SET extraction = $1 WHERE id = $2`, data, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ClaimExtraction marks the slip as extracted. It reports false when an
+// earlier call already did, so only one delivery of a result is applied.
+func (r repository) ClaimExtraction(id int) (bool, error) {
+	result, err := r.db.Exec(`UPDATE slip SET extracted_at = now() WHERE id = $1 AND extracted_at IS NULL`, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseExtraction undoes a claim whose result could not be applied, so
+// the extraction service can retry.
+func (r repository) ReleaseExtraction(id int) error {
+	_, err := r.db.Exec(`UPDATE slip SET extracted_at = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (r repository) get(query string, args ...interface{}) (Slip, error) {
+	s, err := scanSlip(r.db.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Slip{}, ErrNotFound
+	}
+	if err != nil {
+		return Slip{}, err
+	}
+
+	return s, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSlip(row scanner) (Slip, error) {
+	var s Slip
+	var transactionID sql.NullInt64
+	var extraction []byte
+	err := row.Scan(&s.ID, &s.SpenderID, &s.Key, &s.SHA256, &s.ContentType, &s.Size, &s.Filename, &s.Reference, &transactionID, &s.ExtractedAt, &extraction, &s.CreatedAt)
+	if err != nil {
+		return s, err
+	}
+	if transactionID.Valid {
+		id := int(transactionID.Int64)
+		s.TransactionID = &id
+	}
+	if extraction != nil {
+		s.Extraction = &Extraction{}
+		err = json.Unmarshal(extraction, s.Extraction)
+	}
+	return s, err
+}
@@ -0,0 +1,115 @@
+package eslip
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindBySHA256(t *testing.T) {
+	t.Run("should return the spender's first slip with the same content", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows([]string{"id", "spender_id", "key", "sha256", "content_type", "size", "filename", "reference", "transaction_id", "extracted_at", "extraction", "created_at"}).
+			AddRow(1, 3, "a.png", "abc", "image/png", 42, "e-slip1.png", "", 9, nil, []byte(`{"amount":{"value":"120.00","confidence":0.9}}`), createdAt)
+		mock.ExpectQuery(`SELECT (.+) FROM slip WHERE spender_id = \$1 AND sha256 = \$2 ORDER BY id LIMIT 1`).WithArgs(3, "abc").WillReturnRows(rows)
+
+		slip, err := repo.FindBySHA256(3, "abc")
+
+		transactionID := 9
+		assert.NoError(t, err)
+		assert.Equal(t, Slip{
+			ID: 1, SpenderID: 3, Key: "a.png", SHA256: "abc", ContentType: "image/png", Size: 42,
+			Filename: "e-slip1.png", TransactionID: &transactionID, CreatedAt: createdAt,
+			Extraction: &Extraction{Amount: Field{Value: "120.00", Confidence: 0.9}},
+		}, slip)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectQuery(`SELECT (.+) FROM slip`).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindBySHA256(3, "abc")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestCreateSlip(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	createdAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO slip`).WithArgs(3, "a.png", "abc", "image/png", int64(42), "e-slip1.png").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, createdAt))
+
+	slip, err := repo.Create(Slip{SpenderID: 3, Key: "a.png", SHA256: "abc", ContentType: "image/png", Size: 42, Filename: "e-slip1.png"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, slip.ID)
+	assert.Equal(t, createdAt, slip.CreatedAt)
+}
+
+func TestCreateTransaction(t *testing.T) {
+	date := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+	request := transaction.CreateTransactionRequest{
+		Date: &date, Amount: 350_50, Currency: "THB", ImageUrl: "/api/v1/slips/a.png", Note: "SCB",
+		SpenderId: 3, TxnType: "expense", Status: transaction.StatusDraft,
+	}
+
+	t.Run("should create the transaction and link the slip in one transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT transaction_id FROM slip WHERE id = \$1 FOR UPDATE`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_id"}).AddRow(nil))
+		mock.ExpectQuery(`INSERT INTO transaction`).
+			WithArgs(&date, "350.50", "", "expense", "SCB", "/api/v1/slips/a.png", 3, transaction.StatusDraft, "THB").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectExec(`UPDATE slip SET reference = \$1, transaction_id = \$2 WHERE id = \$3`).WithArgs("ref1", 9, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		id, err := repo.CreateTransaction(1, "ref1", request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 9, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should create nothing when the slip is already linked", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT transaction_id FROM slip`).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"transaction_id"}).AddRow(7))
+		mock.ExpectRollback()
+
+		id, err := repo.CreateTransaction(1, "ref1", request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,65 @@
+package eslip
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers carrying a webhook request's signature. The signature is
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<key>.<body>"
+// keyed with the shared secret, where key is the slip key of the route; the
+// timestamp is in Unix seconds.
+const (
+	HeaderTimestamp = "X-Hongjot-Timestamp"
+	HeaderSignature = "X-Hongjot-Signature"
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrStaleTimestamp   = errors.New("timestamp outside the accepted window")
+)
+
+// Sign returns the signature header value for body sent at timestamp about
+// the slip with key. Signing the key keeps a captured request from being
+// replayed against another slip.
+func Sign(secret string, timestamp int64, key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write([]byte(key))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature checks that body was signed with secret for the slip with
+// key at a timestamp no further than tolerance from now, which bounds how
+// long a captured request can be replayed.
+func verifySignature(secret string, tolerance time.Duration, now time.Time, timestamp, signature, key string, body []byte) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	skew := now.Sub(time.Unix(ts, 0))
+	if skew > tolerance || skew < -tolerance {
+		return ErrStaleTimestamp
+	}
+
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, key, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package eslip
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1716000000, 0)
+	body := []byte(`{"amount": 100}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	valid := Sign("secret", now.Unix(), "a.png", body)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		key       string
+		body      []byte
+		err       error
+	}{
+		{"valid", ts, valid, "a.png", body, nil},
+		{"missing signature", ts, "", "a.png", body, ErrMissingSignature},
+		{"missing timestamp", "", valid, "a.png", body, ErrMissingSignature},
+		{"signed with another secret", ts, Sign("other", now.Unix(), "a.png", body), "a.png", body, ErrInvalidSignature},
+		{"body changed", ts, valid, "a.png", []byte(`{"amount": 1000}`), ErrInvalidSignature},
+		{"signed for another slip", ts, valid, "b.png", body, ErrInvalidSignature},
+		{"timestamp changed", strconv.FormatInt(now.Unix()+1, 10), valid, "a.png", body, ErrInvalidSignature},
+		{"without prefix", ts, valid[len("sha256="):], "a.png", body, ErrInvalidSignature},
+		{"stale", strconv.FormatInt(now.Add(-6*time.Minute).Unix(), 10), Sign("secret", now.Add(-6*time.Minute).Unix(), "a.png", body), "a.png", body, ErrStaleTimestamp},
+		{"from the future", strconv.FormatInt(now.Add(6*time.Minute).Unix(), 10), Sign("secret", now.Add(6*time.Minute).Unix(), "a.png", body), "a.png", body, ErrStaleTimestamp},
+		{"not a number", "yesterday", valid, "a.png", body, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifySignature("secret", 5*time.Minute, now, tt.timestamp, tt.signature, tt.key, tt.body)
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package eslip
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotFound = errors.New("slip not found")
+
+// Slip records an uploaded e-slip. Identical content uploaded again by the
+// same spender is matched on SHA256.
+type Slip struct {
+	ID            int         `json:"id"`
+	SpenderID     int         `json:"spender_id"`
+	Key           string      `json:"key"`
+	SHA256        string      `json:"sha256"`
+	ContentType   string      `json:"content_type"`
+	Size          int64       `json:"size"`
+	Filename      string      `json:"filename"`
+	Reference     string      `json:"reference"`
+	TransactionID *int        `json:"transaction_id"`
+	ExtractedAt   *time.Time  `json:"extracted_at"`
+	Extraction    *Extraction `json:"extraction"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
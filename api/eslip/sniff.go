@@ -0,0 +1,43 @@
+package eslip
+
+import "bytes"
+
+// sniffLen is how many leading bytes sniff needs.
+const sniffLen = 16
+
+// slipType is a file format accepted as an e-slip.
+type slipType struct {
+	ContentType string
+	Ext         string
+}
+
+var (
+	typePNG  = slipType{ContentType: "image/png", Ext: ".png"}
+	typeJPEG = slipType{ContentType: "image/jpeg", Ext: ".jpg"}
+	typeHEIC = slipType{ContentType: "image/heic", Ext: ".heic"}
+	typePDF  = slipType{ContentType: "application/pdf", Ext: ".pdf"}
+)
+
+// heicBrands are the ISO-BMFF major brands used by HEIC/HEIF images.
+var heicBrands = []string{"heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1"}
+
+// sniff identifies a slip by its magic bytes. The file name and the
+// client's Content-Type are never trusted.
+func sniff(head []byte) (slipType, bool) {
+	switch {
+	case bytes.HasPrefix(head, []byte("\x89PNG\r\n\x1a\n")):
+		return typePNG, true
+	case bytes.HasPrefix(head, []byte{0xFF, 0xD8, 0xFF}):
+		return typeJPEG, true
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return typePDF, true
+	case len(head) >= 12 && string(head[4:8]) == "ftyp":
+		brand := string(head[8:12])
+		for _, b := range heicBrands {
+			if brand == b {
+				return typeHEIC, true
+			}
+		}
+	}
+	return slipType{}, false
+}
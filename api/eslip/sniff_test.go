@@ -0,0 +1,35 @@
+package eslip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want slipType
+		ok   bool
+	}{
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", typePNG, true},
+		{"jpeg", "\xFF\xD8\xFF\xE0\x00\x10JFIF", typeJPEG, true},
+		{"pdf", "%PDF-1.7\n", typePDF, true},
+		{"heic", "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", typeHEIC, true},
+		{"heif", "\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00", typeHEIC, true},
+		{"mp4", "\x00\x00\x00\x18ftypisom\x00\x00\x00\x00", slipType{}, false},
+		{"gif", "GIF89a", slipType{}, false},
+		{"html", "<html><script>", slipType{}, false},
+		{"empty", "", slipType{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sniff([]byte(tt.head))
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
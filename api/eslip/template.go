@@ -0,0 +1,222 @@
+package eslip
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Confidence of a field found by a bank's template, and by the generic
+// patterns used for other banks and for fields a template missed.
+const (
+	templateConfidence = 0.9
+	genericConfidence  = 0.5
+)
+
+// slipZone is the time zone Thai banks print slip times in.
+var slipZone = time.FixedZone("ICT", 7*60*60)
+
+// dateExpr matches a slip date like "18 พ.ค. 67 10:30", "18 พ.ค. 2567 -
+// 10:30" or "18 May 2024, 10:30".
+const dateExpr = `\d{1,2}\s*(?:\p{Thai}{1,3}\.\s?\p{Thai}{1,2}\.?|[A-Za-z]{3,9}\.?)\s*\d{2,4}(?:[\s,\-]*\d{1,2}:\d{2})?`
+
+// titleExpr matches the title that starts a Thai account name.
+const titleExpr = `(?:นาย|นางสาว|นาง|น\.ส\.|MR\.?|MRS\.?|MS\.?|MISS)`
+
+// bankTemplate reads the slip layout of one bank. Each pattern has one
+// capture group holding the value; a nil pattern is not on the slip.
+type bankTemplate struct {
+	bank      string
+	detect    *regexp.Regexp
+	amount    *regexp.Regexp
+	date      *regexp.Regexp
+	sender    *regexp.Regexp
+	receiver  *regexp.Regexp
+	reference *regexp.Regexp
+}
+
+var bankTemplates = []bankTemplate{
+	{
+		// K PLUS puts the date on top and the two account names, each
+		// followed by its bank and masked account, without labels.
+		bank:      "KBANK",
+		detect:    regexp.MustCompile(`(?i)K\s?PLUS|KASIKORN|กสิกรไทย`),
+		amount:    regexp.MustCompile(`จำนวน:?\s*([\d,]+\.\d{2})`),
+		date:      regexp.MustCompile(`(?m)^\s*(` + dateExpr + `)`),
+		sender:    regexp.MustCompile(`(?m)^\s*(` + titleExpr + `[^\n]+)`),
+		receiver:  regexp.MustCompile(`(?s)` + titleExpr + `[^\n]+\n.*?\n\s*(` + titleExpr + `[^\n]+)`),
+		reference: regexp.MustCompile(`เลขที่รายการ:?\s*([A-Za-z0-9]{10,})`),
+	},
+	{
+		bank:      "SCB",
+		detect:    regexp.MustCompile(`(?i)\bSCB\b|SIAM COMMERCIAL|ไทยพาณิชย์`),
+		amount:    regexp.MustCompile(`จำนวนเงิน:?\s*([\d,]+\.\d{2})`),
+		date:      regexp.MustCompile(`(` + dateExpr + `)`),
+		sender:    regexp.MustCompile(`(?m)^\s*จาก:?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		receiver:  regexp.MustCompile(`(?m)^\s*ไปยัง:?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		reference: regexp.MustCompile(`รหัสอ้างอิง:?\s*([A-Za-z0-9]{10,})`),
+	},
+	{
+		bank:      "BBL",
+		detect:    regexp.MustCompile(`(?i)BANGKOK BANK|\bBBL\b|ธนาคารกรุงเทพ|ธ\.กรุงเทพ`),
+		amount:    regexp.MustCompile(`(?i)(?:Amount|จำนวนเงิน):?\s*(?:THB\s*)?([\d,]+\.\d{2})`),
+		date:      regexp.MustCompile(`(` + dateExpr + `)`),
+		sender:    regexp.MustCompile(`(?im)^\s*(?:From\b|จาก):?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		receiver:  regexp.MustCompile(`(?im)^\s*(?:To\b|ไปที่|ไปยัง):?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		reference: regexp.MustCompile(`(?i)(?:Reference No\.?|Transaction Reference|เลขที่อ้างอิง):?\s*([A-Za-z0-9]{10,})`),
+	},
+	{
+		bank:      "KTB",
+		detect:    regexp.MustCompile(`(?i)KRUNGTHAI|\bKTB\b|กรุงไทย`),
+		amount:    regexp.MustCompile(`จำนวนเงิน:?\s*([\d,]+\.\d{2})`),
+		date:      regexp.MustCompile(`(?:วันที่ทำรายการ:?\s*)?(` + dateExpr + `)`),
+		sender:    regexp.MustCompile(`(?m)^\s*จาก:?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		receiver:  regexp.MustCompile(`(?m)^\s*(?:ไปยัง|ไปที่|ถึง):?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+		reference: regexp.MustCompile(`รหัสอ้างอิง:?\s*([A-Za-z0-9]{10,})`),
+	},
+}
+
+// genericTemplate finds the fields most Thai slips print in some form.
+var genericTemplate = bankTemplate{
+	amount:    regexp.MustCompile(`(?i)([\d,]+\.\d{2})\s*(?:บาท|THB|Baht)`),
+	date:      regexp.MustCompile(`(` + dateExpr + `)`),
+	sender:    regexp.MustCompile(`(?im)^\s*(?:จาก|From\b):?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+	receiver:  regexp.MustCompile(`(?im)^\s*(?:ไปยัง|ไปที่|ถึง|To\b):?[ \t]*\n?[ \t]*(\S[^\n]*)`),
+	reference: regexp.MustCompile(`(?i)(?:Ref(?:erence)?\.?(?:\s*No\.?)?|เลขที่รายการ|รหัสอ้างอิง|เลขที่อ้างอิง):?\s*([A-Za-z0-9]{6,})`),
+}
+
+// ParseSlipText reads the fields of a slip from its OCR text. The bank is
+// the one whose name appears first, which is the sending bank on the
+// supported layouts; its template is tried before the generic patterns.
+func ParseSlipText(text string) Extraction {
+	var e Extraction
+	if t, ok := detectBank(text); ok {
+		e.Bank = Field{Value: t.bank, Confidence: templateConfidence}
+		t.read(text, templateConfidence, &e)
+	}
+	genericTemplate.read(text, genericConfidence, &e)
+	return e
+}
+
+func detectBank(text string) (bankTemplate, bool) {
+	var found bankTemplate
+	at := -1
+	for _, t := range bankTemplates {
+		loc := t.detect.FindStringIndex(text)
+		if loc != nil && (at < 0 || loc[0] < at) {
+			found, at = t, loc[0]
+		}
+	}
+	return found, at >= 0
+}
+
+// read fills the fields of e that are still empty with what the template
+// finds in text.
+func (t bankTemplate) read(text string, confidence float64, e *Extraction) {
+	fill(&e.Amount, t.amount, text, confidence, normalizeAmount)
+	fill(&e.Date, t.date, text, confidence, normalizeDate)
+	fill(&e.Sender, t.sender, text, confidence, normalizeName)
+	fill(&e.Receiver, t.receiver, text, confidence, normalizeName)
+	fill(&e.Reference, t.reference, text, confidence, normalizeReference)
+}
+
+// fill sets f to the first match of pattern that normalize accepts.
+func fill(f *Field, pattern *regexp.Regexp, text string, confidence float64, normalize func(string) (string, bool)) {
+	if f.Value != "" || pattern == nil {
+		return
+	}
+	for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+		if v, ok := normalize(m[1]); ok {
+			*f = Field{Value: v, Confidence: confidence}
+			return
+		}
+	}
+}
+
+// normalizeAmount skips zero amounts so a fee line is not read as the
+// amount.
+func normalizeAmount(s string) (string, bool) {
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil || amount <= 0 {
+		return "", false
+	}
+	return strconv.FormatFloat(amount, 'f', 2, 64), true
+}
+
+func normalizeDate(s string) (string, bool) {
+	d, ok := parseSlipDate(s)
+	if !ok {
+		return "", false
+	}
+	return d.Format(time.RFC3339), true
+}
+
+func normalizeName(s string) (string, bool) {
+	name := strings.Join(strings.Fields(s), " ")
+	return name, name != ""
+}
+
+func normalizeReference(s string) (string, bool) {
+	return s, s != ""
+}
+
+var slipDate = regexp.MustCompile(`^(\d{1,2})\s*(\p{Thai}{1,3}\.\s?\p{Thai}{1,2}\.?|[A-Za-z]{3,9}\.?)\s*(\d{2,4})(?:[\s,\-]*(\d{1,2}):(\d{2}))?`)
+
+// thaiMonths maps Thai month abbreviations, without dots, to their month.
+var thaiMonths = map[string]time.Month{
+	"มค": time.January, "กพ": time.February, "มีค": time.March, "เมย": time.April,
+	"พค": time.May, "มิย": time.June, "กค": time.July, "สค": time.August,
+	"กย": time.September, "ตค": time.October, "พย": time.November, "ธค": time.December,
+}
+
+// parseSlipDate reads a date matched by dateExpr. Years of 2400 and later
+// are Buddhist Era; two digit years are Buddhist Era after a Thai month
+// and Common Era after an English one.
+func parseSlipDate(s string) (time.Time, bool) {
+	m := slipDate.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	day, _ := strconv.Atoi(m[1])
+	month, thai := thaiMonths[strings.NewReplacer(".", "", " ", "").Replace(m[2])]
+	if !thai {
+		name := strings.ToLower(strings.TrimSuffix(m[2], "."))
+		for mo := time.January; mo <= time.December; mo++ {
+			if len(name) >= 3 && strings.HasPrefix(strings.ToLower(mo.String()), name) {
+				month = mo
+			}
+		}
+		if month == 0 {
+			return time.Time{}, false
+		}
+	}
+
+	year, _ := strconv.Atoi(m[3])
+	switch {
+	case len(m[3]) == 2 && thai:
+		year += 2500 - 543
+	case len(m[3]) == 2:
+		year += 2000
+	case len(m[3]) == 3:
+		return time.Time{}, false
+	case year >= 2400:
+		year -= 543
+	}
+
+	hour, minute := 0, 0
+	if m[4] != "" {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+		if hour > 23 || minute > 59 {
+			return time.Time{}, false
+		}
+	}
+
+	d := time.Date(year, month, day, hour, minute, 0, 0, slipZone)
+	if d.Day() != day {
+		return time.Time{}, false
+	}
+	return d, true
+}
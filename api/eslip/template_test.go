@@ -0,0 +1,160 @@
+package eslip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSlipText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Extraction
+	}{
+		{
+			name: "KBank",
+			text: `โอนเงินสำเร็จ
+18 พ.ค. 67 10:30 น.
+นาย สมชาย ใจดี
+ธ.กสิกรไทย
+xxx-x-x1234-x
+น.ส. สมหญิง  รักดี
+ธ.ไทยพาณิชย์
+xxx-x-x5678-x
+เลขที่รายการ:
+016139103035BTF05421
+จำนวน:
+1,250.00 บาท
+ค่าธรรมเนียม:
+0.00 บาท`,
+			want: Extraction{
+				Amount:    Field{"1250.00", templateConfidence},
+				Date:      Field{"2024-05-18T10:30:00+07:00", templateConfidence},
+				Sender:    Field{"นาย สมชาย ใจดี", templateConfidence},
+				Receiver:  Field{"น.ส. สมหญิง รักดี", templateConfidence},
+				Bank:      Field{"KBANK", templateConfidence},
+				Reference: Field{"016139103035BTF05421", templateConfidence},
+			},
+		},
+		{
+			name: "SCB",
+			text: `SCB
+โอนเงินสำเร็จ
+18 พ.ค. 2567 - 10:30
+รหัสอ้างอิง: 202405181030ABC1234
+จาก
+นาย สมชาย ใจดี
+xxx-xxx123-4
+ไปยัง
+บริษัท ตัวอย่าง จำกัด
+xxx-xxx567-8
+จำนวนเงิน
+350.50`,
+			want: Extraction{
+				Amount:    Field{"350.50", templateConfidence},
+				Date:      Field{"2024-05-18T10:30:00+07:00", templateConfidence},
+				Sender:    Field{"นาย สมชาย ใจดี", templateConfidence},
+				Receiver:  Field{"บริษัท ตัวอย่าง จำกัด", templateConfidence},
+				Bank:      Field{"SCB", templateConfidence},
+				Reference: Field{"202405181030ABC1234", templateConfidence},
+			},
+		},
+		{
+			name: "Bangkok Bank",
+			text: `Bangkok Bank
+Transfer successful
+18 May 2024, 10:30
+From
+MR. SOMCHAI JAIDEE
+To
+MS. SOMYING RAKDEE
+Amount
+THB 2,000.00
+Reference No. 0123456789ABCD`,
+			want: Extraction{
+				Amount:    Field{"2000.00", templateConfidence},
+				Date:      Field{"2024-05-18T10:30:00+07:00", templateConfidence},
+				Sender:    Field{"MR. SOMCHAI JAIDEE", templateConfidence},
+				Receiver:  Field{"MS. SOMYING RAKDEE", templateConfidence},
+				Bank:      Field{"BBL", templateConfidence},
+				Reference: Field{"0123456789ABCD", templateConfidence},
+			},
+		},
+		{
+			name: "Krungthai",
+			text: `Krungthai
+โอนเงินสำเร็จ
+รหัสอ้างอิง: A1B2C3D4E5F6
+วันที่ทำรายการ: 1 ม.ค. 2567 - 08:05
+จาก
+นาย สมชาย ใจดี
+ไปยัง
+นาง สมศรี มีสุข
+จำนวนเงิน 99.00 บาท`,
+			want: Extraction{
+				Amount:    Field{"99.00", templateConfidence},
+				Date:      Field{"2024-01-01T08:05:00+07:00", templateConfidence},
+				Sender:    Field{"นาย สมชาย ใจดี", templateConfidence},
+				Receiver:  Field{"นาง สมศรี มีสุข", templateConfidence},
+				Bank:      Field{"KTB", templateConfidence},
+				Reference: Field{"A1B2C3D4E5F6", templateConfidence},
+			},
+		},
+		{
+			name: "other banks with generic patterns",
+			text: `ttb touch
+โอนเงินสำเร็จ
+18 พ.ค. 67 10:30
+จาก นาย สมชาย ใจดี
+ไปยัง นาย สมปอง ดีใจ
+ค่าธรรมเนียม 0.00 บาท
+จำนวนเงิน 500.00 บาท
+Ref. 8271635401`,
+			want: Extraction{
+				Amount:    Field{"500.00", genericConfidence},
+				Date:      Field{"2024-05-18T10:30:00+07:00", genericConfidence},
+				Sender:    Field{"นาย สมชาย ใจดี", genericConfidence},
+				Receiver:  Field{"นาย สมปอง ดีใจ", genericConfidence},
+				Reference: Field{"8271635401", genericConfidence},
+			},
+		},
+		{
+			name: "text that is not a slip",
+			text: "Total: 5 items",
+			want: Extraction{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSlipText(tt.text))
+		})
+	}
+}
+
+func TestParseSlipDate(t *testing.T) {
+	tests := []struct {
+		text string
+		want time.Time
+		ok   bool
+	}{
+		{"18 พ.ค. 67 10:30", time.Date(2024, time.May, 18, 10, 30, 0, 0, slipZone), true},
+		{"1 มี.ค. 2567", time.Date(2024, time.March, 1, 0, 0, 0, 0, slipZone), true},
+		{"18 May 2024, 10:30", time.Date(2024, time.May, 18, 10, 30, 0, 0, slipZone), true},
+		{"5 Sept 24 09:05", time.Date(2024, time.September, 5, 9, 5, 0, 0, slipZone), true},
+		{"30 ก.พ. 2567", time.Time{}, false},
+		{"18 พ.ค. 67 25:00", time.Time{}, false},
+		{"18 THB 2024", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := parseSlipDate(tt.text)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.True(t, tt.want.Equal(got), "got %v", got)
+		})
+	}
+}
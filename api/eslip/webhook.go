@@ -0,0 +1,209 @@
+package eslip
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/blob"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// maxExtractionBody bounds the webhook body read before the signature is
+// checked.
+const maxExtractionBody = 64 << 10
+
+var (
+	ErrWebhookDisabled = errors.New("extraction webhook is not configured")
+	ErrInvalidAmount   = errors.New("amount must be positive")
+	ErrInvalidTxnType  = errors.New("transaction_type must be expense or income")
+)
+
+// ExtractionRequest is what the extraction service read from a slip. Amount
+// is in baht, like every slip.
+type ExtractionRequest struct {
+	Date     *time.Time   `json:"date"`
+	Amount   money.Amount `json:"amount"`
+	Category string       `json:"category"`
+	Note     string       `json:"note"`
+	TxnType  string       `json:"transaction_type"`
+}
+
+type ExtractionResponse struct {
+	SlipID        int    `json:"slip_id"`
+	Key           string `json:"key"`
+	TransactionID *int   `json:"transaction_id"`
+	// Replayed is set when the result had already been applied by an
+	// earlier delivery and nothing was changed.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+type webhookHandler struct {
+	cfg          config.Webhook
+	repo         Repository
+	transactions Transactions
+	now          func() time.Time
+}
+
+type WebhookHandler interface {
+	Extraction(c echo.Context) error
+}
+
+// NewWebhookHandler serves the extraction service's callback. It is not
+// behind user authentication; every request must carry a signature made
+// with cfg.Secret.
+func NewWebhookHandler(cfg config.Webhook, repo Repository, transactions Transactions) WebhookHandler {
+	return webhookHandler{cfg: cfg, repo: repo, transactions: transactions, now: time.Now}
+}
+
+// Extraction applies an extraction result to the slip. The first delivery
+// creates the slip's transaction, or fills in the draft read from its QR
+// code; later deliveries for the same slip change nothing and answer with
+// the same transaction.
+func (h webhookHandler) Extraction(c echo.Context) error {
+	logger := mlog.L(c)
+	if h.cfg.Secret == "" {
+		return c.JSON(http.StatusServiceUnavailable, errs.Build(ErrWebhookDisabled))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxExtractionBody+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	if len(body) > maxExtractionBody {
+		return c.JSON(http.StatusRequestEntityTooLarge, errs.Build(ErrRequestTooLarge))
+	}
+	header := c.Request().Header
+	err = verifySignature(h.cfg.Secret, h.cfg.Tolerance, h.now(), header.Get(HeaderTimestamp), header.Get(HeaderSignature), c.Param("key"), body)
+	if err != nil {
+		logger.Warn("rejected extraction webhook", zap.Error(err))
+		return c.JSON(http.StatusUnauthorized, errs.Build(err))
+	}
+
+	var request ExtractionRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	if request.Amount <= 0 {
+		return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidAmount))
+	}
+	if request.TxnType == "" {
+		request.TxnType = "expense"
+	}
+	if request.TxnType != "expense" && request.TxnType != "income" {
+		return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidTxnType))
+	}
+
+	key := c.Param("key")
+	if !blob.ValidKey(key) {
+		return c.JSON(http.StatusBadRequest, errs.Build(blob.ErrInvalidKey))
+	}
+	slip, err := h.repo.GetByKey(key)
+	if errors.Is(err, ErrNotFound) {
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	}
+	if err != nil {
+		logger.Error("get slip error", zap.String("key", key), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	claimed, err := h.repo.ClaimExtraction(slip.ID)
+	if err != nil {
+		logger.Error("claim extraction error", zap.String("key", key), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+	if !claimed {
+		// Another delivery may still be applying the result; answer with
+		// whatever it has linked so far.
+		if current, err := h.repo.GetByKey(key); err == nil {
+			slip = current
+		}
+		return c.JSON(http.StatusOK, ExtractionResponse{SlipID: slip.ID, Key: slip.Key, TransactionID: slip.TransactionID, Replayed: true})
+	}
+
+	id, err := h.apply(slip, request)
+	if err != nil {
+		logger.Error("apply extraction error", zap.String("key", key), zap.Error(err))
+		if err := h.repo.ReleaseExtraction(slip.ID); err != nil {
+			logger.Error("release extraction error", zap.String("key", key), zap.Error(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	logger.Info("slip extracted", zap.String("key", key), zap.Int("transaction_id", id))
+	return c.JSON(http.StatusCreated, ExtractionResponse{SlipID: slip.ID, Key: slip.Key, TransactionID: &id})
+}
+
+// apply writes the result to the slip's transaction, creating it as a draft
+// for the spender to review when the slip has none, and links it back to the
+// slip through its image_url. A result without a date is dated when the slip
+// was uploaded. Only a draft is filled in, keeping the account and category
+// the spender chose; a transaction the spender has reviewed is left as it is.
+func (h webhookHandler) apply(slip Slip, request ExtractionRequest) (int, error) {
+	imageURL := slipPath + slip.Key
+	if request.Date == nil {
+		request.Date = &slip.CreatedAt
+	}
+
+	if slip.TransactionID != nil {
+		existing, err := h.transactions.Get(slip.SpenderID, *slip.TransactionID)
+		if err == nil {
+			return existing.ID, h.fillDraft(existing, request, imageURL)
+		}
+		if !errors.Is(err, transaction.ErrNotFound) {
+			return 0, err
+		}
+		// The draft was deleted in the meantime; create a new transaction.
+	}
+
+	created, err := h.transactions.Create(transaction.CreateTransactionRequest{
+		Date:      request.Date,
+		Amount:    request.Amount,
+		Currency:  "THB",
+		Category:  request.Category,
+		ImageUrl:  imageURL,
+		Note:      request.Note,
+		SpenderId: slip.SpenderID,
+		TxnType:   request.TxnType,
+		Status:    transaction.StatusDraft,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := h.repo.Link(slip.ID, slip.Reference, created.ID); err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// fillDraft updates the draft existing with the result. The draft keeps its
+// transaction type, as UpdateExpense never changes one: a draft is created
+// from a slip the spender paid with, so it is an expense.
+func (h webhookHandler) fillDraft(existing transaction.Transaction, request ExtractionRequest, imageURL string) error {
+	if existing.Status != transaction.StatusDraft {
+		return nil
+	}
+
+	update := transaction.Transaction{
+		ID:        existing.ID,
+		Date:      request.Date,
+		Amount:    request.Amount,
+		Category:  request.Category,
+		AccountID: existing.AccountID,
+		ImageUrl:  imageURL,
+		Note:      request.Note,
+	}
+	if existing.CategoryID != nil {
+		update.Category, update.CategoryID = existing.Category, existing.CategoryID
+	}
+	return h.transactions.UpdateExpense(existing.SpenderId, update)
+}
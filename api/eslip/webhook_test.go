@@ -0,0 +1,232 @@
+package eslip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var webhookConfig = config.Webhook{Secret: "webhook-secret", Tolerance: 5 * time.Minute}
+
+func newExtractionContext(key, body, secret string) (echo.Context, *httptest.ResponseRecorder) {
+	now := time.Now().Unix()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/slips/"+key+"/extraction", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, now, key, []byte(body)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v1/slips/:key/extraction")
+	c.SetParamNames("key")
+	c.SetParamValues(key)
+	return c, rec
+}
+
+func decodeExtraction(t *testing.T, rec *httptest.ResponseRecorder) ExtractionResponse {
+	var res ExtractionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	return res
+}
+
+func TestExtraction(t *testing.T) {
+	body := `{"date": "2024-05-18T10:00:00Z", "amount": 888.88, "category": "Food", "note": "lunch", "transaction_type": "expense"}`
+
+	t.Run("should create the slip's transaction as a draft and link it", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		slip, _ := repo.Create(Slip{SpenderID: 1, Key: "slip.png"})
+		transactions := transaction.NewMemoryRepository()
+		h := NewWebhookHandler(webhookConfig, repo, transactions)
+		c, rec := newExtractionContext("slip.png", body, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		res := decodeExtraction(t, rec)
+		require.NotNil(t, res.TransactionID)
+		assert.Equal(t, slip.ID, res.SlipID)
+
+		all := listTransactions(t, transactions, 1)
+		require.Len(t, all, 1)
+		assert.Equal(t, money.Amount(888_88), all[0].Amount)
+		assert.Equal(t, "/api/v1/slips/slip.png", all[0].ImageUrl)
+		assert.Equal(t, transaction.StatusDraft, all[0].Status)
+		linked, _ := repo.GetByKey("slip.png")
+		assert.Equal(t, res.TransactionID, linked.TransactionID)
+		assert.NotNil(t, linked.ExtractedAt)
+	})
+
+	t.Run("should apply a redelivered result only once", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png"})
+		transactions := transaction.NewMemoryRepository()
+		h := NewWebhookHandler(webhookConfig, repo, transactions)
+		c, rec := newExtractionContext("slip.png", body, webhookConfig.Secret)
+		require.NoError(t, h.Extraction(c))
+		first := decodeExtraction(t, rec)
+
+		c, rec = newExtractionContext("slip.png", body, webhookConfig.Secret)
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		again := decodeExtraction(t, rec)
+		assert.True(t, again.Replayed)
+		assert.Equal(t, first.TransactionID, again.TransactionID)
+		all := listTransactions(t, transactions, 1)
+		assert.Len(t, all, 1)
+	})
+
+	t.Run("should fill in the draft read from the QR code", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		transactions := transaction.NewMemoryRepository()
+		draft, _ := transactions.Create(transaction.CreateTransactionRequest{SpenderId: 1, TxnType: "expense", Note: "KBANK ref 123", Status: transaction.StatusDraft})
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png", Reference: "123", TransactionID: &draft.ID})
+		h := NewWebhookHandler(webhookConfig, repo, transactions)
+		c, rec := newExtractionContext("slip.png", body, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, &draft.ID, decodeExtraction(t, rec).TransactionID)
+		all := listTransactions(t, transactions, 1)
+		require.Len(t, all, 1)
+		assert.Equal(t, money.Amount(888_88), all[0].Amount)
+		assert.Equal(t, "Food", all[0].Category)
+	})
+
+	t.Run("should keep the account and category chosen on the draft", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		transactions := transaction.NewMemoryRepository()
+		categories := category.NewMemoryRepository()
+		transactions.UseCategories(categories)
+		accountID, transportID := 4, 5
+		draft, _ := transactions.Create(transaction.CreateTransactionRequest{SpenderId: 1, TxnType: "expense", AccountID: &accountID, CategoryID: &transportID, Status: transaction.StatusDraft})
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png", TransactionID: &draft.ID})
+		h := NewWebhookHandler(webhookConfig, repo, transactions)
+		c, rec := newExtractionContext("slip.png", body, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		got, err := transactions.Get(1, draft.ID)
+		require.NoError(t, err)
+		assert.Equal(t, money.Amount(888_88), got.Amount)
+		assert.Equal(t, &accountID, got.AccountID)
+		assert.Equal(t, &transportID, got.CategoryID)
+		assert.Equal(t, "Transport", got.Category)
+	})
+
+	t.Run("should leave a transaction the spender has reviewed alone", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		transactions := transaction.NewMemoryRepository()
+		confirmed, _ := transactions.Create(transaction.CreateTransactionRequest{SpenderId: 1, TxnType: "expense", Amount: 120_00, Note: "edited"})
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png", TransactionID: &confirmed.ID})
+		h := NewWebhookHandler(webhookConfig, repo, transactions)
+		c, rec := newExtractionContext("slip.png", body, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, &confirmed.ID, decodeExtraction(t, rec).TransactionID)
+		got, err := transactions.Get(1, confirmed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, money.Amount(120_00), got.Amount)
+		assert.Equal(t, "edited", got.Note)
+	})
+
+	t.Run("should reject a request signed with another secret", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png"})
+		h := NewWebhookHandler(webhookConfig, repo, transaction.NewMemoryRepository())
+		c, rec := newExtractionContext("slip.png", body, "guessed-secret")
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		slip, _ := repo.GetByKey("slip.png")
+		assert.Nil(t, slip.ExtractedAt)
+	})
+
+	t.Run("should reject a signature made for another slip", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: 1, Key: "a.png"})
+		repo.Create(Slip{SpenderID: 1, Key: "b.png"})
+		h := NewWebhookHandler(webhookConfig, repo, transaction.NewMemoryRepository())
+		signedForA, _ := newExtractionContext("a.png", body, webhookConfig.Secret)
+		c, rec := newExtractionContext("b.png", body, webhookConfig.Secret)
+		c.Request().Header = signedForA.Request().Header
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		slip, _ := repo.GetByKey("b.png")
+		assert.Nil(t, slip.ExtractedAt)
+	})
+
+	t.Run("should be unavailable without a secret", func(t *testing.T) {
+		h := NewWebhookHandler(config.Webhook{}, NewMemoryRepository(transaction.NewMemoryRepository()), transaction.NewMemoryRepository())
+		c, rec := newExtractionContext("slip.png", body, "")
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("should return not found for an unknown slip", func(t *testing.T) {
+		h := NewWebhookHandler(webhookConfig, NewMemoryRepository(transaction.NewMemoryRepository()), transaction.NewMemoryRepository())
+		c, rec := newExtractionContext("missing.png", body, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should return bad request when amount is missing", func(t *testing.T) {
+		repo := NewMemoryRepository(transaction.NewMemoryRepository())
+		repo.Create(Slip{SpenderID: 1, Key: "slip.png"})
+		h := NewWebhookHandler(webhookConfig, repo, transaction.NewMemoryRepository())
+		c, rec := newExtractionContext("slip.png", `{"note": "unreadable"}`, webhookConfig.Secret)
+
+		err := h.Extraction(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should return bad request for a transaction type other than expense or income", func(t *testing.T) {
+		for _, txnType := range []string{"transfer", "refund"} {
+			repo := NewMemoryRepository(transaction.NewMemoryRepository())
+			repo.Create(Slip{SpenderID: 1, Key: "slip.png"})
+			transactions := transaction.NewMemoryRepository()
+			h := NewWebhookHandler(webhookConfig, repo, transactions)
+			c, rec := newExtractionContext("slip.png", `{"amount": 100, "transaction_type": "`+txnType+`"}`, webhookConfig.Secret)
+
+			err := h.Extraction(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, txnType)
+			assert.Empty(t, listTransactions(t, transactions, 1))
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package health
 
 import (
-	"database/sql"
 	"fmt"
 	"net/http"
 	"time"
@@ -9,7 +8,12 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func Check(db *sql.DB) func(c echo.Context) error {
+// Pinger is satisfied by *sql.DB.
+type Pinger interface {
+	Ping() error
+}
+
+func Check(db Pinger) func(c echo.Context) error {
 	return func(c echo.Context) error {
 		if err := db.Ping(); err != nil {
 			return c.JSON(http.StatusInternalServerError, map[string]string{
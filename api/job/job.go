@@ -0,0 +1,38 @@
+package job
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	// StatusDead is the dead-letter state of a job that failed every
+	// attempt. It is kept for inspection and never run again.
+	StatusDead = "dead"
+)
+
+var (
+	ErrNoJob    = errors.New("no job ready")
+	ErrNotFound = errors.New("job not found")
+	// ErrLeaseLost means the job was claimed again after its lease expired,
+	// so the caller's result is dropped in favour of the new run.
+	ErrLeaseLost = errors.New("job lease lost")
+)
+
+// Job is a unit of background work. Kind selects the handler and Ref
+// names what it works on, e.g. a slip key.
+type Job struct {
+	ID        int        `json:"id"`
+	Kind      string     `json:"kind"`
+	Ref       string     `json:"ref"`
+	Status    string     `json:"status"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error"`
+	RunAt     time.Time  `json:"run_at"`
+	LockedAt  *time.Time `json:"locked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryQueue struct {
+	mu     sync.Mutex
+	jobs   map[int]Job
+	nextID int
+	now    func() time.Time
+}
+
+// NewMemoryQueue keeps jobs in process memory for unit tests and the
+// --storage=memory server mode. Pending jobs are lost on restart.
+func NewMemoryQueue() Queue {
+	return &memoryQueue{
+		jobs:   map[int]Job{},
+		nextID: 1,
+		now:    time.Now,
+	}
+}
+
+func (q *memoryQueue) Enqueue(kind, ref string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	j := Job{ID: q.nextID, Kind: kind, Ref: ref, Status: StatusPending, RunAt: now, CreatedAt: now, UpdatedAt: now}
+	q.nextID++
+	q.jobs[j.ID] = j
+
+	return j, nil
+}
+
+func (q *memoryQueue) Claim(lease time.Duration) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var next Job
+	for _, j := range q.jobs {
+		ready := (j.Status == StatusPending && !j.RunAt.After(now)) ||
+			(j.Status == StatusRunning && j.LockedAt != nil && j.LockedAt.Before(now.Add(-lease)))
+		if !ready {
+			continue
+		}
+		if next.ID == 0 || j.RunAt.Before(next.RunAt) || (j.RunAt.Equal(next.RunAt) && j.ID < next.ID) {
+			next = j
+		}
+	}
+	if next.ID == 0 {
+		return Job{}, ErrNoJob
+	}
+
+	next.Status = StatusRunning
+	next.Attempts++
+	next.LockedAt = &now
+	next.UpdatedAt = now
+	q.jobs[next.ID] = next
+
+	return next, nil
+}
+
+func (q *memoryQueue) Complete(j Job) error {
+	return q.finish(j, func(j *Job) {
+		j.Status = StatusDone
+		j.LastError = ""
+	})
+}
+
+func (q *memoryQueue) Retry(j Job, lastError string, runAt time.Time) error {
+	return q.finish(j, func(j *Job) {
+		j.Status = StatusPending
+		j.LastError = lastError
+		j.RunAt = runAt
+	})
+}
+
+func (q *memoryQueue) Bury(j Job, lastError string) error {
+	return q.finish(j, func(j *Job) {
+		j.Status = StatusDead
+		j.LastError = lastError
+	})
+}
+
+func (q *memoryQueue) Latest(kind, ref string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var latest Job
+	for _, j := range q.jobs {
+		if j.Kind == kind && j.Ref == ref && j.ID > latest.ID {
+			latest = j
+		}
+	}
+	if latest.ID == 0 {
+		return Job{}, ErrNotFound
+	}
+	return latest, nil
+}
+
+func (q *memoryQueue) finish(claimed Job, change func(j *Job)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, ok := q.jobs[claimed.ID]
+	if !ok || j.LockedAt == nil || claimed.LockedAt == nil || !j.LockedAt.Equal(*claimed.LockedAt) {
+		return ErrLeaseLost
+	}
+	change(&j)
+	j.LockedAt = nil
+	j.UpdatedAt = q.now()
+	q.jobs[j.ID] = j
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryQueue_Claim(t *testing.T) {
+	now := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+	q := NewMemoryQueue().(*memoryQueue)
+	q.now = func() time.Time { return now }
+	first, _ := q.Enqueue("test", "a")
+	second, _ := q.Enqueue("test", "b")
+	claimed, _ := q.Claim(time.Minute)
+	q.Retry(claimed, "boom", now.Add(time.Minute))
+
+	j, err := q.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, j.ID, "a job waiting for its retry is not ready")
+	assert.Equal(t, StatusRunning, j.Status)
+	assert.Equal(t, 1, j.Attempts)
+
+	_, err = q.Claim(time.Minute)
+	assert.Equal(t, ErrNoJob, err)
+
+	now = now.Add(2 * time.Minute)
+	j, err = q.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, j.ID, "a job whose lease expired is claimed again")
+	assert.Equal(t, 2, j.Attempts)
+
+	j, err = q.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, j.ID)
+	assert.Equal(t, 2, j.Attempts)
+}
+
+func TestMemoryQueue_FinishAfterLeaseLost(t *testing.T) {
+	now := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+	q := NewMemoryQueue().(*memoryQueue)
+	q.now = func() time.Time { return now }
+	q.Enqueue("test", "a")
+	stale, _ := q.Claim(time.Minute)
+	now = now.Add(2 * time.Minute)
+	current, _ := q.Claim(time.Minute)
+
+	assert.Equal(t, ErrLeaseLost, q.Complete(stale))
+	assert.Equal(t, ErrLeaseLost, q.Bury(stale, "boom"))
+	j, _ := q.Latest("test", "a")
+	assert.Equal(t, StatusRunning, j.Status, "the stale worker must not finish the newer run")
+
+	assert.NoError(t, q.Complete(current))
+	j, _ = q.Latest("test", "a")
+	assert.Equal(t, StatusDone, j.Status)
+}
@@ -0,0 +1,175 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"go.uber.org/zap"
+)
+
+// Handler does the work of one job. Returning an error schedules a retry.
+type Handler func(ctx context.Context, j Job) error
+
+// Pool runs jobs from a Queue on a fixed number of workers.
+type Pool struct {
+	queue    Queue
+	cfg      config.Jobs
+	logger   *zap.Logger
+	handlers map[string]Handler
+	now      func() time.Time
+
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	ctx        context.Context
+	cancelJobs context.CancelFunc
+}
+
+func NewPool(queue Queue, cfg config.Jobs, logger *zap.Logger) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Pool{
+		queue:      queue,
+		cfg:        cfg,
+		logger:     logger,
+		handlers:   map[string]Handler{},
+		now:        time.Now,
+		stop:       make(chan struct{}),
+		ctx:        ctx,
+		cancelJobs: cancel,
+	}
+}
+
+// Handle registers the handler of a job kind. It must be called before
+// Start.
+func (p *Pool) Handle(kind string, h Handler) {
+	p.handlers[kind] = h
+}
+
+func (p *Pool) Start() {
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	p.logger.Info("job workers started", zap.Int("workers", p.cfg.Workers))
+}
+
+// Shutdown stops claiming jobs and waits for running ones to finish. When
+// ctx ends first their contexts are cancelled; the queue hands them out
+// again once their lease expires.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancelJobs()
+		return nil
+	case <-ctx.Done():
+		p.cancelJobs()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		j, err := p.queue.Claim(p.cfg.Lease)
+		if err != nil {
+			if err != ErrNoJob {
+				p.logger.Error("claim job error", zap.Error(err))
+			}
+			select {
+			case <-p.stop:
+				return
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+
+		p.run(j)
+	}
+}
+
+func (p *Pool) run(j Job) {
+	logger := p.logger.With(zap.Int("job_id", j.ID), zap.String("kind", j.Kind), zap.String("ref", j.Ref), zap.Int("attempt", j.Attempts))
+
+	err := p.handle(j)
+	if err == nil {
+		p.finished(logger, "complete", p.queue.Complete(j))
+		return
+	}
+
+	if j.Attempts >= p.cfg.MaxAttempts {
+		logger.Error("job dead-lettered", zap.Error(err))
+		p.finished(logger, "bury", p.queue.Bury(j, err.Error()))
+		return
+	}
+
+	runAt := p.now().Add(p.backoff(j.Attempts))
+	logger.Warn("job failed, retrying", zap.Time("run_at", runAt), zap.Error(err))
+	p.finished(logger, "retry", p.queue.Retry(j, err.Error(), runAt))
+}
+
+// finished logs the outcome of recording a job's result. A lost lease is
+// expected after a slow run and only warned about.
+func (p *Pool) finished(logger *zap.Logger, action string, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrLeaseLost):
+		logger.Warn(action+" job skipped, lease lost", zap.Error(err))
+	default:
+		logger.Error(action+" job error", zap.Error(err))
+	}
+}
+
+// handle runs the job's handler, turning a panic into an error so one bad
+// job cannot take a worker down. The handler is cancelled after Timeout so
+// it gives up before its lease expires and another worker claims the job.
+func (p *Pool) handle(j Job) (err error) {
+	h, ok := p.handlers[j.Kind]
+	if !ok {
+		return fmt.Errorf("no handler for job kind %q", j.Kind)
+	}
+
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return h(ctx, j)
+}
+
+// backoff is the delay before the retry that follows the given attempt:
+// BackoffBase doubled for every earlier attempt, capped at BackoffMax.
+func (p *Pool) backoff(attempt int) time.Duration {
+	d := p.cfg.BackoffBase
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.cfg.BackoffMax {
+			return p.cfg.BackoffMax
+		}
+	}
+	if d > p.cfg.BackoffMax {
+		return p.cfg.BackoffMax
+	}
+	return d
+}
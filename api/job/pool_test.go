@@ -0,0 +1,143 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var poolConfig = config.Jobs{
+	Workers:      2,
+	PollInterval: 5 * time.Millisecond,
+	MaxAttempts:  3,
+	BackoffBase:  2 * time.Second,
+	BackoffMax:   5 * time.Second,
+	Lease:        time.Minute,
+	Timeout:      30 * time.Second,
+}
+
+func TestBackoff(t *testing.T) {
+	p := NewPool(NewMemoryQueue(), poolConfig, zap.NewNop())
+
+	assert.Equal(t, 2*time.Second, p.backoff(1))
+	assert.Equal(t, 4*time.Second, p.backoff(2))
+	assert.Equal(t, 5*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(30))
+}
+
+func TestPool_Run(t *testing.T) {
+	now := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		attempt   int
+		handler   Handler
+		status    string
+		lastError string
+		runAt     time.Time
+	}{
+		{"done when handler succeeds", 1, func(context.Context, Job) error { return nil }, StatusDone, "", now},
+		{"retried with backoff when handler fails", 2, func(context.Context, Job) error { return errors.New("boom") }, StatusPending, "boom", now.Add(4 * time.Second)},
+		{"dead after the last attempt", 3, func(context.Context, Job) error { return errors.New("boom") }, StatusDead, "boom", now},
+		{"retried when handler panics", 1, func(context.Context, Job) error { panic("oops") }, StatusPending, "job panicked: oops", now.Add(2 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewMemoryQueue().(*memoryQueue)
+			q.now = func() time.Time { return now }
+			p := NewPool(q, poolConfig, zap.NewNop())
+			p.now = q.now
+			p.Handle("test", tt.handler)
+			q.Enqueue("test", "ref")
+			var j Job
+			for i := 1; i < tt.attempt; i++ {
+				j, _ = q.Claim(time.Minute)
+				q.Retry(j, "", now)
+			}
+			j, _ = q.Claim(time.Minute)
+
+			p.run(j)
+
+			got, err := q.Latest("test", "ref")
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, got.Status)
+			assert.Equal(t, tt.lastError, got.LastError)
+			assert.Equal(t, tt.runAt, got.RunAt)
+			assert.Nil(t, got.LockedAt)
+		})
+	}
+}
+
+func TestPool_RunCancelsHandlerAfterTimeout(t *testing.T) {
+	q := NewMemoryQueue()
+	cfg := poolConfig
+	cfg.Timeout = 10 * time.Millisecond
+	p := NewPool(q, cfg, zap.NewNop())
+	p.Handle("stuck", func(ctx context.Context, j Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q.Enqueue("stuck", "ref")
+	j, _ := q.Claim(cfg.Lease)
+
+	p.run(j)
+
+	got, _ := q.Latest("stuck", "ref")
+	assert.Equal(t, StatusPending, got.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), got.LastError)
+}
+
+func TestPool_StartAndShutdown(t *testing.T) {
+	q := NewMemoryQueue()
+	p := NewPool(q, poolConfig, zap.NewNop())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p.Handle("slow", func(ctx context.Context, j Job) error {
+		close(started)
+		<-release
+		return nil
+	})
+	q.Enqueue("slow", "ref")
+
+	p.Start()
+	<-started
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	err := p.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	j, _ := q.Latest("slow", "ref")
+	assert.Equal(t, StatusDone, j.Status)
+}
+
+func TestPool_ShutdownCancelsJobsAfterDeadline(t *testing.T) {
+	q := NewMemoryQueue()
+	p := NewPool(q, poolConfig, zap.NewNop())
+	started := make(chan struct{})
+	p.Handle("stuck", func(ctx context.Context, j Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q.Enqueue("stuck", "ref")
+
+	p.Start()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := p.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	j, _ := q.Latest("stuck", "ref")
+	assert.Equal(t, StatusPending, j.Status)
+	assert.Equal(t, context.Canceled.Error(), j.LastError)
+}
@@ -0,0 +1,105 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type Queue interface {
+	Enqueue(kind, ref string) (Job, error)
+	// Claim marks the next ready job as running and counts the attempt. A
+	// running job is ready again once it has been locked for longer than
+	// lease, which recovers jobs of crashed workers.
+	Claim(lease time.Duration) (Job, error)
+	// Complete, Retry and Bury finish a claimed job. They return
+	// ErrLeaseLost when the job has been claimed again since j was, so a
+	// worker that overran its lease cannot overwrite the newer run.
+	Complete(j Job) error
+	Retry(j Job, lastError string, runAt time.Time) error
+	Bury(j Job, lastError string) error
+	Latest(kind, ref string) (Job, error)
+}
+
+type queue struct {
+	db *sql.DB
+}
+
+func NewQueue(db *sql.DB) Queue {
+	return queue{db: db}
+}
+
+const jobColumns = `id, kind, ref, status, attempts, last_error, run_at, locked_at, created_at, updated_at`
+
+func (q queue) Enqueue(kind, ref string) (Job, error) {
+	query := `INSERT INTO job (kind, ref) VALUES ($1, $2) RETURNING ` + jobColumns
+	return scanJob(q.db.QueryRow(query, kind, ref))
+}
+
+func (q queue) Claim(lease time.Duration) (Job, error) {
+	query := `UPDATE job SET status = 'running', attempts = attempts + 1, locked_at = now(), updated_at = now()
+		WHERE id = (
+			SELECT id FROM job
+			WHERE (status = 'pending' AND run_at <= now())
+				OR (status = 'running' AND locked_at < now() - make_interval(secs => $1))
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+	j, err := scanJob(q.db.QueryRow(query, lease.Seconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrNoJob
+	}
+	return j, err
+}
+
+func (q queue) Complete(j Job) error {
+	return q.finish(`UPDATE job SET status = 'done', last_error = '', locked_at = NULL, updated_at = now() WHERE id = $1 AND locked_at = $2`, j.ID, j.LockedAt)
+}
+
+func (q queue) Retry(j Job, lastError string, runAt time.Time) error {
+	return q.finish(`UPDATE job SET status = 'pending', last_error = $3, run_at = $4, locked_at = NULL, updated_at = now() WHERE id = $1 AND locked_at = $2`, j.ID, j.LockedAt, lastError, runAt)
+}
+
+func (q queue) Bury(j Job, lastError string) error {
+	return q.finish(`UPDATE job SET status = 'dead', last_error = $3, locked_at = NULL, updated_at = now() WHERE id = $1 AND locked_at = $2`, j.ID, j.LockedAt, lastError)
+}
+
+func (q queue) Latest(kind, ref string) (Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM job WHERE kind = $1 AND ref = $2 ORDER BY id DESC LIMIT 1`
+	j, err := scanJob(q.db.QueryRow(query, kind, ref))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, ErrNotFound
+	}
+	return j, err
+}
+
+// finish runs an update guarded by the claim's locked_at, which Claim sets
+// anew on every claim and so serves as the lease token.
+func (q queue) finish(query string, args ...interface{}) error {
+	result, err := q.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row scanner) (Job, error) {
+	var j Job
+	err := row.Scan(&j.ID, &j.Kind, &j.Ref, &j.Status, &j.Attempts, &j.LastError, &j.RunAt, &j.LockedAt, &j.CreatedAt, &j.UpdatedAt)
+	return j, err
+}
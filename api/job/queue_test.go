@@ -0,0 +1,74 @@
+package job
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var jobRows = []string{"id", "kind", "ref", "status", "attempts", "last_error", "run_at", "locked_at", "created_at", "updated_at"}
+
+func TestClaim(t *testing.T) {
+	t.Run("should lock the next ready job", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		q := NewQueue(db)
+		now := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows(jobRows).AddRow(1, "slip.extract", "a.png", StatusRunning, 1, "", now, now, now, now)
+		mock.ExpectQuery(`UPDATE job SET status = 'running', attempts = attempts \+ 1(.+)FOR UPDATE SKIP LOCKED`).WithArgs(float64(300)).WillReturnRows(rows)
+
+		j, err := q.Claim(5 * time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Job{
+			ID: 1, Kind: "slip.extract", Ref: "a.png", Status: StatusRunning, Attempts: 1,
+			RunAt: now, LockedAt: &now, CreatedAt: now, UpdatedAt: now,
+		}, j)
+	})
+
+	t.Run("should report when no job is ready", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		q := NewQueue(db)
+		mock.ExpectQuery(`UPDATE job`).WillReturnError(sql.ErrNoRows)
+
+		_, err = q.Claim(5 * time.Minute)
+
+		assert.Equal(t, ErrNoJob, err)
+	})
+}
+
+func TestRetry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	q := NewQueue(db)
+	runAt := time.Date(2024, time.May, 18, 10, 0, 0, 0, time.UTC)
+	lockedAt := runAt.Add(-time.Minute)
+	j := Job{ID: 1, LockedAt: &lockedAt}
+
+	t.Run("should reschedule the job while the lease is held", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE job SET status = 'pending', last_error = \$3, run_at = \$4(.+)WHERE id = \$1 AND locked_at = \$2`).WithArgs(1, &lockedAt, "boom", runAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := q.Retry(j, "boom", runAt)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should report a lost lease", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE job SET status = 'pending'`).WithArgs(1, &lockedAt, "boom", runAt).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := q.Retry(j, "boom", runAt)
+
+		assert.Equal(t, ErrLeaseLost, err)
+	})
+}
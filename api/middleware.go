@@ -1,11 +1,39 @@
 package api
 
 import (
+	"errors"
+
 	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 )
 
-func AuthCheck(username, password string, c echo.Context) (bool, error) {
-	isPass := auth.Check(username, password)
-	return isPass, nil
+// AuthCheck validates Basic credentials against the users table. When
+// EnableLegacyAuth is on, the shared workshop credential is still accepted for
+// clients that have not been given their own account yet.
+func AuthCheck(users user.Service, flag config.FeatureFlag) middleware.BasicAuthValidator {
+	return func(username, password string, c echo.Context) (bool, error) {
+		u, err := users.Authenticate(username, password)
+		switch {
+		case err == nil:
+			auth.SetIdentity(c, u.Identity())
+			return true, nil
+		case errors.Is(err, user.ErrLocked):
+			return false, nil
+		case !errors.Is(err, user.ErrInvalidCredentials):
+			return false, err
+		}
+
+		if !flag.EnableLegacyAuth {
+			return false, nil
+		}
+
+		id, isPass := auth.Check(username, password)
+		if isPass {
+			auth.SetIdentity(c, id)
+		}
+		return isPass, nil
+	}
 }
@@ -2,35 +2,92 @@ package api
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/stretchr/testify/assert"
 )
 
+type stubUsers struct {
+	users map[string]user.User
+	err   error
+}
+
+func (s stubUsers) Register(request user.RegisterRequest) (user.RegisterResponse, error) {
+	return user.RegisterResponse{}, nil
+}
+
+func (s stubUsers) Authenticate(username, password string) (user.User, error) {
+	if s.err != nil {
+		return user.User{}, s.err
+	}
+	u, ok := s.users[username+":"+password]
+	if !ok {
+		return user.User{}, user.ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+func (s stubUsers) Get(id int) (user.User, error) {
+	return user.User{}, user.ErrNotFound
+}
+
+func (s stubUsers) ChangePassword(userID int, request user.ChangePasswordRequest) error {
+	return nil
+}
+
 func TestAuthMiddleware(t *testing.T) {
+	users := stubUsers{users: map[string]user.User{
+		"hongjot:p@ssw0rd!": {ID: 7, Username: "hongjot", SpenderID: 3},
+	}}
+
 	tests := []struct {
+		name           string
+		users          user.Service
+		flag           config.FeatureFlag
 		auth           string
 		wantStatusCode int
+		wantSpender    string
 	}{
-		{"user:secret", http.StatusOK},
-		{"user:wrong-secret", http.StatusUnauthorized},
+		{"database user", users, config.FeatureFlag{}, "hongjot:p@ssw0rd!", http.StatusOK, "3"},
+		{"database user wrong password", users, config.FeatureFlag{}, "hongjot:wrong", http.StatusUnauthorized, ""},
+		{"legacy credential when enabled", users, config.FeatureFlag{EnableLegacyAuth: true}, "user:secret", http.StatusOK, "1"},
+		{"legacy credential when disabled", users, config.FeatureFlag{}, "user:secret", http.StatusUnauthorized, ""},
+		{"legacy wrong secret", users, config.FeatureFlag{EnableLegacyAuth: true}, "user:wrong-secret", http.StatusUnauthorized, ""},
+		{"locked account does not fall back", stubUsers{err: user.ErrLocked}, config.FeatureFlag{EnableLegacyAuth: true}, "user:secret", http.StatusUnauthorized, ""},
+		{"database error", stubUsers{err: errors.New("db down")}, config.FeatureFlag{}, "hongjot:p@ssw0rd!", http.StatusInternalServerError, ""},
 	}
 
 	for _, tc := range tests {
-		e := echo.New()
-		e.Use(middleware.BasicAuth(AuthCheck))
-		e.GET("/", func(c echo.Context) error { return c.String(http.StatusOK, "[]") })
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		auth := "basic " + base64.StdEncoding.EncodeToString([]byte(tc.auth))
-		req.Header.Set(echo.HeaderAuthorization, auth)
-		rec := httptest.NewRecorder()
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(middleware.BasicAuth(AuthCheck(tc.users, tc.flag)))
+			e.GET("/", func(c echo.Context) error {
+				id, ok := auth.IdentityFrom(c)
+				if !ok {
+					return c.NoContent(http.StatusInternalServerError)
+				}
+				return c.String(http.StatusOK, fmt.Sprint(id.SpenderID))
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			auth := "basic " + base64.StdEncoding.EncodeToString([]byte(tc.auth))
+			req.Header.Set(echo.HeaderAuthorization, auth)
+			rec := httptest.NewRecorder()
 
-		e.ServeHTTP(rec, req)
+			e.ServeHTTP(rec, req)
 
-		assert.Equal(t, tc.wantStatusCode, rec.Code)
+			assert.Equal(t, tc.wantStatusCode, rec.Code)
+			if tc.wantSpender != "" {
+				assert.Equal(t, tc.wantSpender, rec.Body.String())
+			}
+		})
 	}
 }
@@ -0,0 +1,178 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// Amount is a sum of money in minor units, satang for baht, so adding up and
+// comparing amounts is exact. It is written to JSON as a number with two
+// decimal places and to the database as decimal text, and reads both back
+// without going through a float.
+type Amount int64
+
+var ErrInvalidAmount = errors.New("amount must be a number with at most two decimal places")
+
+// decimal matches a JSON number. Its exponent is kept to three digits so a
+// single value cannot make Parse allocate without bound.
+var decimal = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]{1,3})?$`)
+
+var hundred = big.NewRat(100, 1)
+
+// Parse reads a decimal such as 19.99. It rejects anything that is not a
+// whole number of satang, so 19.999 is an error rather than 20.00.
+func Parse(s string) (Amount, error) {
+	satang, err := satang(s)
+	if err != nil {
+		return 0, err
+	}
+	if !satang.IsInt() {
+		return 0, ErrInvalidAmount
+	}
+	return fromInt(satang.Num())
+}
+
+// Round reads a decimal and rounds it half away from zero to the satang, for
+// values such as converted totals that the database computes at a higher
+// precision.
+func Round(s string) (Amount, error) {
+	satang, err := satang(s)
+	if err != nil {
+		return 0, err
+	}
+	return round(satang)
+}
+
+// Scale multiplies the amount by an exact ratio, such as one exchange rate
+// over another, and rounds the result half away from zero to the satang.
+func (a Amount) Scale(ratio *big.Rat) (Amount, error) {
+	return round(new(big.Rat).Mul(new(big.Rat).SetInt64(int64(a)), ratio))
+}
+
+func round(satang *big.Rat) (Amount, error) {
+	quo, rem := new(big.Int).QuoRem(satang.Num(), satang.Denom(), new(big.Int))
+	if twice := new(big.Int).Abs(rem); twice.Lsh(twice, 1).Cmp(satang.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(satang.Num().Sign())))
+	}
+	return fromInt(quo)
+}
+
+func satang(s string) (*big.Rat, error) {
+	if !decimal.MatchString(s) {
+		return nil, ErrInvalidAmount
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrInvalidAmount
+	}
+	return r.Mul(r, hundred), nil
+}
+
+func fromInt(i *big.Int) (Amount, error) {
+	if !i.IsInt64() {
+		return 0, ErrInvalidAmount
+	}
+	return Amount(i.Int64()), nil
+}
+
+// FromFloat rounds f half away from zero to the satang. It is for values that
+// arrive as floats, such as a driver that scans a DECIMAL into a float64.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * 100))
+}
+
+// Float is the amount in major units, for ratios such as percentages.
+func (a Amount) Float() float64 {
+	return float64(a) / 100
+}
+
+// Div divides the amount into n equal parts, rounded half away from zero.
+func (a Amount) Div(n int) Amount {
+	q, r := int64(a)/int64(n), int64(a)%int64(n)
+	if 2*abs(r) >= abs(int64(n)) {
+		if (a < 0) != (n < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount(q)
+}
+
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// String formats the amount with two decimal places, as in -1234.50.
+func (a Amount) String() string {
+	sign, units := "", int64(a)
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units/100, units%100)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON takes a JSON number, or a string holding one, and rejects it
+// with ErrInvalidAmount when it has more than two decimal places.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Scan reads a DECIMAL column, which the driver returns as text, rounding
+// computed values to the satang. Integers are whole units, like a DECIMAL
+// without a fraction, and NULL is zero.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	case int64:
+		*a = Amount(v * 100)
+	case float64:
+		*a = FromFloat(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into an amount", src)
+	}
+	return nil
+}
+
+func (a *Amount) scanText(s string) error {
+	amount, err := Round(s)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q into an amount", s)
+	}
+	*a = amount
+	return nil
+}
+
+// Value writes the amount as decimal text so the database stores it exactly.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -0,0 +1,169 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+		err      error
+	}{
+		{"19.99", 1999, nil},
+		{"100", 10000, nil},
+		{"0.1", 10, nil},
+		{"-5.5", -550, nil},
+		{"19.990", 1999, nil},
+		{"1.5e2", 15000, nil},
+		{"19.999", 0, ErrInvalidAmount},
+		{"0.001", 0, ErrInvalidAmount},
+		{"", 0, ErrInvalidAmount},
+		{"abc", 0, ErrInvalidAmount},
+		{"1/3", 0, ErrInvalidAmount},
+		{"0x10", 0, ErrInvalidAmount},
+		{"1e999", 0, ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := Parse(tt.input)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+	}{
+		{"19.99", 1999},
+		{"12.345", 1235},
+		{"12.3449999", 1234},
+		{"-12.345", -1235},
+		{"359.6400000000", 35964},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			amount, err := Round(tt.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "19.99", Amount(1999).String())
+	assert.Equal(t, "0.05", Amount(5).String())
+	assert.Equal(t, "-1234.50", Amount(-123450).String())
+}
+
+func TestAmount_Div(t *testing.T) {
+	assert.Equal(t, Amount(333), Amount(1000).Div(3))
+	assert.Equal(t, Amount(667), Amount(2000).Div(3))
+	assert.Equal(t, Amount(-667), Amount(-2000).Div(3))
+	assert.Equal(t, Amount(50), Amount(100).Div(2))
+}
+
+func TestAmount_Scale(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Amount
+		ratio    *big.Rat
+		expected Amount
+	}{
+		{"whole", 10_00, big.NewRat(36, 1), 360_00},
+		{"rounds half away from zero", 20_00, big.NewRat(40, 36), 22_22},
+		{"rounds up", 5, big.NewRat(1, 2), 3},
+		{"negative", -5, big.NewRat(1, 2), -3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := tt.amount.Scale(tt.ratio)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	t.Run("round trips exactly", func(t *testing.T) {
+		var body struct {
+			Amount Amount `json:"amount"`
+		}
+
+		err := json.Unmarshal([]byte(`{"amount": 19.99}`), &body)
+		out, _ := json.Marshal(body)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Amount(1999), body.Amount)
+		assert.JSONEq(t, `{"amount": 19.99}`, string(out))
+	})
+
+	t.Run("takes a string", func(t *testing.T) {
+		var amount Amount
+
+		err := json.Unmarshal([]byte(`"250.50"`), &amount)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Amount(25050), amount)
+	})
+
+	t.Run("rejects more than two decimals", func(t *testing.T) {
+		var amount Amount
+
+		err := json.Unmarshal([]byte(`19.999`), &amount)
+
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+	})
+}
+
+func TestAmount_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      interface{}
+		expected Amount
+	}{
+		{"decimal text", []byte("19.99"), 1999},
+		{"computed total", "1079.5050", 107951},
+		{"whole units", int64(300), 30000},
+		{"float", 0.1 + 0.2, 30},
+		{"null", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := Amount(1)
+
+			err := amount.Scan(tt.src)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, amount)
+		})
+	}
+
+	t.Run("rejects what is not a number", func(t *testing.T) {
+		var amount Amount
+
+		assert.Error(t, amount.Scan("n/a"))
+		assert.Error(t, amount.Scan(true))
+	})
+}
+
+func TestAmount_Value(t *testing.T) {
+	value, err := Amount(-1999).Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-19.99", value)
+}
@@ -0,0 +1,195 @@
+package recurring
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	List(c echo.Context) error
+	Get(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+	Skip(c echo.Context) error
+	Upcoming(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) List(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	result, err := h.service.List(caller.SpenderID)
+	if err != nil {
+		return h.fail(c, "list recurring transactions error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Get(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid recurring transaction ID"})
+	}
+
+	result, err := h.service.Get(caller.SpenderID, id)
+	if err != nil {
+		return h.fail(c, "get recurring transaction error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Create(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var request Request
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Create(caller.SpenderID, request)
+	if err != nil {
+		return h.fail(c, "create recurring transaction error", err)
+	}
+
+	mlog.L(c).Info("create recurring transaction successfully", zap.Int("id", result.ID))
+	return c.JSON(http.StatusCreated, result)
+}
+
+// Update edits the series from its next occurrence on.
+func (h handler) Update(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid recurring transaction ID"})
+	}
+
+	var request Request
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Update(caller.SpenderID, id, request)
+	if err != nil {
+		return h.fail(c, "update recurring transaction error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Delete(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid recurring transaction ID"})
+	}
+
+	if err := h.service.Delete(caller.SpenderID, id); err != nil {
+		return h.fail(c, "delete recurring transaction error", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h handler) Skip(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid recurring transaction ID"})
+	}
+
+	var request SkipRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Skip(caller.SpenderID, id, request.Date)
+	if err != nil {
+		return h.fail(c, "skip recurring transaction error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Upcoming lists the occurrences of the next days given by the days query
+// parameter.
+func (h handler) Upcoming(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	days := 0
+	if param := c.QueryParam("days"); param != "" {
+		d, err := strconv.Atoi(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidDays))
+		}
+		days = d
+	}
+
+	result, err := h.service.Upcoming(caller.SpenderID, days)
+	if err != nil {
+		return h.fail(c, "list upcoming recurring transactions error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, errs.Build(err))
+	case errors.Is(err, ErrConflict):
+		return c.JSON(http.StatusConflict, errs.Build(err))
+	case errors.Is(err, ErrInvalidFrequency), errors.Is(err, ErrInvalidInterval), errors.Is(err, ErrInvalidDayOfMonth),
+		errors.Is(err, ErrInvalidDate), errors.Is(err, ErrInvalidEnd), errors.Is(err, ErrInvalidTxnType),
+		errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrInvalidCategory), errors.Is(err, ErrInvalidOccurrence),
+		errors.Is(err, ErrInvalidDays), errors.Is(err, ErrStartTooEarly):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	default:
+		mlog.L(c).Error(msg, zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+}
@@ -0,0 +1,203 @@
+package recurring
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) List(spenderID int) ([]Rule, error) {
+	args := m.Called(spenderID)
+	return args.Get(0).([]Rule), args.Error(1)
+}
+
+func (m *MockService) Get(spenderID int, id int) (Rule, error) {
+	args := m.Called(spenderID, id)
+	return args.Get(0).(Rule), args.Error(1)
+}
+
+func (m *MockService) Create(spenderID int, request Request) (Rule, error) {
+	args := m.Called(spenderID, request)
+	return args.Get(0).(Rule), args.Error(1)
+}
+
+func (m *MockService) Update(spenderID int, id int, request Request) (Rule, error) {
+	args := m.Called(spenderID, id, request)
+	return args.Get(0).(Rule), args.Error(1)
+}
+
+func (m *MockService) Delete(spenderID int, id int) error {
+	args := m.Called(spenderID, id)
+	return args.Error(0)
+}
+
+func (m *MockService) Skip(spenderID int, id int, date string) (Rule, error) {
+	args := m.Called(spenderID, id, date)
+	return args.Get(0).(Rule), args.Error(1)
+}
+
+func (m *MockService) Upcoming(spenderID int, days int) ([]Occurrence, error) {
+	args := m.Called(spenderID, days)
+	return args.Get(0).([]Occurrence), args.Error(1)
+}
+
+func (m *MockService) RunDue() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func newAuthenticatedContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3})
+	return c, rec
+}
+
+func TestHandler_Create(t *testing.T) {
+	request := Request{Frequency: FrequencyMonthly, DayOfMonth: intPtr(1), TxnType: "expense", Amount: 12000_00, Category: "Rent"}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"invalid frequency", ErrInvalidFrequency, http.StatusBadRequest},
+		{"invalid end", ErrInvalidEnd, http.StatusBadRequest},
+		{"invalid category", ErrInvalidCategory, http.StatusBadRequest},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, "/recurring",
+				`{"frequency": "monthly", "day_of_month": 1, "transaction_type": "expense", "amount": 12000, "category": "Rent"}`)
+			mockService := new(MockService)
+			mockService.On("Create", 3, request).Return(Rule{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Create(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"updated", nil, http.StatusOK},
+		{"not found", ErrNotFound, http.StatusNotFound},
+		{"changed meanwhile", ErrConflict, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPut, "/recurring/1", `{"frequency": "weekly", "transaction_type": "expense", "amount": 100}`)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			mockService := new(MockService)
+			mockService.On("Update", 3, 1, Request{Frequency: FrequencyWeekly, TxnType: "expense", Amount: 100_00}).Return(Rule{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Update(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Skip(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockError      error
+		expectedStatus int
+	}{
+		{"skipped", "1", nil, http.StatusOK},
+		{"not an occurrence", "1", ErrInvalidOccurrence, http.StatusBadRequest},
+		{"not found", "1", ErrNotFound, http.StatusNotFound},
+		{"invalid id", "abc", nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newAuthenticatedContext(http.MethodPost, "/recurring/"+tt.id+"/skip", `{"date": "2024-06-01"}`)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			mockService := new(MockService)
+			mockService.On("Skip", 3, 1, "2024-06-01").Return(Rule{ID: 1}, tt.mockError)
+
+			err := NewHandler(mockService).Skip(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Upcoming(t *testing.T) {
+	t.Run("lists occurrences", func(t *testing.T) {
+		c, rec := newAuthenticatedContext(http.MethodGet, "/recurring/upcoming?days=7", "")
+		mockService := new(MockService)
+		mockService.On("Upcoming", 3, 7).Return([]Occurrence{{RuleID: 1, Date: "2024-05-15", TxnType: "expense", Amount: 100_00, Note: "gym"}}, nil)
+
+		err := NewHandler(mockService).Upcoming(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{"recurring_id": 1, "date": "2024-05-15", "transaction_type": "expense", "amount": 100,
+			"category": "", "category_id": null, "note": "gym"}]`, rec.Body.String())
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		c, rec := newAuthenticatedContext(http.MethodGet, "/recurring/upcoming?days=week", "")
+
+		err := NewHandler(new(MockService)).Upcoming(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestHandler_GetListAndDelete(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("List", 3).Return([]Rule{{ID: 1, SpenderID: 3, Skipped: []string{}}}, nil)
+	mockService.On("Get", 3, 2).Return(Rule{}, ErrNotFound)
+	mockService.On("Delete", 3, 1).Return(nil)
+	h := NewHandler(mockService)
+
+	listCtx, listRec := newAuthenticatedContext(http.MethodGet, "/recurring", "")
+	getCtx, getRec := newAuthenticatedContext(http.MethodGet, "/recurring/2", "")
+	getCtx.SetParamNames("id")
+	getCtx.SetParamValues("2")
+	deleteCtx, deleteRec := newAuthenticatedContext(http.MethodDelete, "/recurring/1", "")
+	deleteCtx.SetParamNames("id")
+	deleteCtx.SetParamValues("1")
+
+	assert.NoError(t, h.List(listCtx))
+	assert.NoError(t, h.Get(getCtx))
+	assert.NoError(t, h.Delete(deleteCtx))
+
+	assert.Equal(t, http.StatusOK, listRec.Code)
+	assert.NotContains(t, listRec.Body.String(), "next_index")
+	assert.Equal(t, http.StatusNotFound, getRec.Code)
+	assert.Equal(t, http.StatusNoContent, deleteRec.Code)
+}
@@ -0,0 +1,136 @@
+package recurring
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+type memoryRepository struct {
+	mu           sync.Mutex
+	rules        map[int]Rule
+	nextID       int
+	transactions transaction.Repository
+}
+
+// NewMemoryRepository keeps rules in process memory for the
+// --storage=memory server mode and generates their occurrences into
+// transactions.
+func NewMemoryRepository(transactions transaction.Repository) Repository {
+	return &memoryRepository{rules: map[int]Rule{}, nextID: 1, transactions: transactions}
+}
+
+func (r *memoryRepository) List(spenderID int) ([]Rule, error) {
+	return r.filter(func(rule Rule) bool { return rule.SpenderID == spenderID }), nil
+}
+
+func (r *memoryRepository) Due(until string) ([]Rule, error) {
+	return r.filter(func(rule Rule) bool { return rule.NextDate != nil && *rule.NextDate <= until }), nil
+}
+
+func (r *memoryRepository) filter(keep func(Rule) bool) []Rule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := []Rule{}
+	for _, rule := range r.rules {
+		if keep(rule) {
+			rules = append(rules, clone(rule))
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	return rules
+}
+
+func (r *memoryRepository) Get(spenderID int, id int) (Rule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.rules[id]
+	if !ok || rule.SpenderID != spenderID {
+		return Rule{}, ErrNotFound
+	}
+
+	return clone(rule), nil
+}
+
+func (r *memoryRepository) Create(rule Rule) (Rule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule.ID = r.nextID
+	r.nextID++
+	r.rules[rule.ID] = clone(rule)
+
+	return rule, nil
+}
+
+func (r *memoryRepository) Update(rule Rule, index int) (Rule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.rules[rule.ID]
+	if !ok || current.SpenderID != rule.SpenderID || current.NextIndex != index {
+		return Rule{}, ErrConflict
+	}
+	r.rules[rule.ID] = clone(rule)
+
+	return rule, nil
+}
+
+func (r *memoryRepository) Delete(spenderID int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.rules[id]
+	if !ok || rule.SpenderID != spenderID {
+		return ErrNotFound
+	}
+	delete(r.rules, id)
+
+	return nil
+}
+
+func (r *memoryRepository) Skip(spenderID int, id int, date string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.rules[id]
+	if !ok || rule.SpenderID != spenderID || rule.skips(date) {
+		return nil
+	}
+	rule.Skipped = append(slices.Clone(rule.Skipped), date)
+	r.rules[id] = rule
+
+	return nil
+}
+
+// Advance holds the lock while creating the transaction, so concurrent
+// schedulers cannot both generate the occurrence.
+func (r *memoryRepository) Advance(rule Rule, next *string, txn *transaction.CreateTransactionRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.rules[rule.ID]
+	if !ok || current.NextIndex != rule.NextIndex {
+		return ErrConflict
+	}
+	if txn != nil {
+		if _, err := r.transactions.Create(*txn); err != nil {
+			return err
+		}
+	}
+	current.NextIndex++
+	current.NextDate = next
+	r.rules[rule.ID] = current
+
+	return nil
+}
+
+func clone(rule Rule) Rule {
+	rule.Skipped = append([]string{}, rule.Skipped...)
+	return rule
+}
@@ -0,0 +1,196 @@
+package recurring
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+// Frequencies of a Rule.
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// Bounds of the days parameter of Service.Upcoming.
+const (
+	DefaultUpcomingDays = 30
+	MaxUpcomingDays     = 366
+)
+
+// MaxBackfill bounds how many occurrences of a new series may already be
+// due. They are all generated at the next run, so a start date far in the
+// past would flood the spender's transactions in one go.
+const MaxBackfill = 31
+
+const dateLayout = "2006-01-02"
+
+// Rule repeats a transaction every Interval days, weeks, months or years
+// from StartDate, until EndDate or for Count occurrences. Monthly rules fall
+// on DayOfMonth, or on the day of StartDate, moved back to the last day of
+// shorter months; yearly rules on 29 February fall on 28 February in other
+// years. Dates are YYYY-MM-DD on the spender's calendar.
+//
+// NextIndex counts the occurrences already generated or skipped, and
+// NextDate is the date of the next one, nil once the series has ended.
+type Rule struct {
+	ID         int          `json:"id"`
+	SpenderID  int          `json:"spender_id"`
+	Frequency  string       `json:"frequency"`
+	Interval   int          `json:"interval"`
+	DayOfMonth *int         `json:"day_of_month"`
+	StartDate  string       `json:"start_date"`
+	EndDate    *string      `json:"end_date"`
+	Count      *int         `json:"count"`
+	TxnType    string       `json:"transaction_type"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	Note       string       `json:"note"`
+	Skipped    []string     `json:"skipped"`
+	NextIndex  int          `json:"-"`
+	NextDate   *string      `json:"next_date"`
+}
+
+// Request creates a rule or replaces its series. Interval defaults to 1
+// and StartDate to today; EndDate and Count are optional and exclusive.
+type Request struct {
+	Frequency  string       `json:"frequency"`
+	Interval   int          `json:"interval"`
+	DayOfMonth *int         `json:"day_of_month"`
+	StartDate  string       `json:"start_date"`
+	EndDate    *string      `json:"end_date"`
+	Count      *int         `json:"count"`
+	TxnType    string       `json:"transaction_type"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	Note       string       `json:"note"`
+}
+
+type SkipRequest struct {
+	Date string `json:"date"`
+}
+
+// Occurrence is a transaction a rule will generate.
+type Occurrence struct {
+	RuleID     int          `json:"recurring_id"`
+	Date       string       `json:"date"`
+	TxnType    string       `json:"transaction_type"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	Note       string       `json:"note"`
+}
+
+var (
+	ErrNotFound          = errors.New("recurring transaction not found")
+	ErrConflict          = errors.New("the series changed meanwhile, try again")
+	ErrInvalidFrequency  = errors.New("frequency must be daily, weekly, monthly or yearly")
+	ErrInvalidInterval   = errors.New("interval must be at least 1")
+	ErrInvalidDayOfMonth = errors.New("day_of_month must be between 1 and 31 and is for monthly rules only")
+	ErrInvalidDate       = errors.New("dates must be formatted as YYYY-MM-DD")
+	ErrInvalidEnd        = errors.New("set end_date or count, not both; end_date must not be before start_date and count must be at least 1")
+	ErrInvalidTxnType    = errors.New("transaction_type must be expense or income")
+	ErrInvalidAmount     = errors.New("amount must be greater than 0")
+	ErrInvalidCategory   = errors.New("category_id must be a system category or one of your own")
+	ErrInvalidOccurrence = errors.New("date is not an upcoming occurrence of the series")
+	ErrInvalidDays       = errors.New("days must be between 1 and 366")
+	ErrStartTooEarly     = errors.New("start_date is too far back, at most 31 occurrences may already be due")
+)
+
+// occurrence returns the date of the rule's nth occurrence, counting from
+// 0, and false once the series has ended.
+func (r Rule) occurrence(n int) (time.Time, bool) {
+	start, err := time.Parse(dateLayout, r.StartDate)
+	if err != nil || (r.Count != nil && n >= *r.Count) {
+		return time.Time{}, false
+	}
+
+	step := n * r.Interval
+	var d time.Time
+	switch r.Frequency {
+	case FrequencyDaily:
+		d = start.AddDate(0, 0, step)
+	case FrequencyWeekly:
+		d = start.AddDate(0, 0, 7*step)
+	case FrequencyMonthly:
+		day := start.Day()
+		if r.DayOfMonth != nil {
+			day = *r.DayOfMonth
+		}
+		// A day of month before the start day begins the next month.
+		if onDay(start.Year(), start.Month(), day).Before(start) {
+			step++
+		}
+		d = onDay(start.Year(), start.Month()+time.Month(step), day)
+	case FrequencyYearly:
+		d = onDay(start.Year()+step, start.Month(), start.Day())
+	default:
+		return time.Time{}, false
+	}
+
+	if r.EndDate != nil {
+		end, err := time.Parse(dateLayout, *r.EndDate)
+		if err != nil || d.After(end) {
+			return time.Time{}, false
+		}
+	}
+	return d, true
+}
+
+// onDay returns the day of the month, or its last day when the month is
+// shorter. The month may be out of range and is normalized.
+func onDay(year int, month time.Month, day int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// dateOf formats the nth occurrence, nil once the series has ended.
+func (r Rule) dateOf(n int) *string {
+	d, ok := r.occurrence(n)
+	if !ok {
+		return nil
+	}
+	s := d.Format(dateLayout)
+	return &s
+}
+
+// indexAfter returns the index of the first occurrence after day.
+func (r Rule) indexAfter(day time.Time) int {
+	n := 0
+	for {
+		d, ok := r.occurrence(n)
+		if !ok || d.After(day) {
+			return n
+		}
+		n++
+	}
+}
+
+func (r Rule) skips(date string) bool {
+	return slices.Contains(r.Skipped, date)
+}
+
+// transaction is the confirmed transaction of the occurrence on date, at
+// the start of that day in loc. It is recorded in the spender's base
+// currency.
+func (r Rule) transaction(date string, loc *time.Location) transaction.CreateTransactionRequest {
+	d, _ := time.ParseInLocation(dateLayout, date, loc)
+	return transaction.CreateTransactionRequest{
+		Date:       &d,
+		Amount:     r.Amount,
+		Category:   r.Category,
+		CategoryID: r.CategoryID,
+		Note:       r.Note,
+		SpenderId:  r.SpenderID,
+		TxnType:    r.TxnType,
+		Status:     transaction.StatusConfirmed,
+	}
+}
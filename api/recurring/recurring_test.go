@@ -0,0 +1,51 @@
+package recurring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(n int) *int       { return &n }
+func strPtr(s string) *string { return &s }
+
+func TestRule_Occurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		want []string
+	}{
+		{"daily every 2 days", Rule{Frequency: FrequencyDaily, Interval: 2, StartDate: "2024-02-27"},
+			[]string{"2024-02-27", "2024-02-29", "2024-03-02"}},
+		{"weekly", Rule{Frequency: FrequencyWeekly, Interval: 1, StartDate: "2024-05-06"},
+			[]string{"2024-05-06", "2024-05-13", "2024-05-20"}},
+		{"monthly on the 31st", Rule{Frequency: FrequencyMonthly, Interval: 1, StartDate: "2024-01-31"},
+			[]string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}},
+		{"monthly on an earlier day starts next month", Rule{Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: intPtr(1), StartDate: "2024-05-10"},
+			[]string{"2024-06-01", "2024-07-01"}},
+		{"every 3 months across a year", Rule{Frequency: FrequencyMonthly, Interval: 3, StartDate: "2024-11-15"},
+			[]string{"2024-11-15", "2025-02-15", "2025-05-15"}},
+		{"yearly on 29 February", Rule{Frequency: FrequencyYearly, Interval: 1, StartDate: "2024-02-29"},
+			[]string{"2024-02-29", "2025-02-28", "2026-02-28"}},
+		{"ends after count", Rule{Frequency: FrequencyDaily, Interval: 1, StartDate: "2024-05-01", Count: intPtr(2)},
+			[]string{"2024-05-01", "2024-05-02"}},
+		{"ends on end date", Rule{Frequency: FrequencyWeekly, Interval: 1, StartDate: "2024-05-01", EndDate: strPtr("2024-05-15")},
+			[]string{"2024-05-01", "2024-05-08", "2024-05-15"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for n := range tt.want {
+				if d := tt.rule.dateOf(n); d != nil {
+					got = append(got, *d)
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+			if tt.rule.Count != nil || tt.rule.EndDate != nil {
+				assert.Nil(t, tt.rule.dateOf(len(tt.want)), "the series has ended")
+			}
+		})
+	}
+}
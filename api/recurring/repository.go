@@ -0,0 +1,186 @@
+package recurring
+
+import (
+	"database/sql"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/lib/pq"
+)
+
+// Repository stores recurring rules. Methods taking a spender are scoped to
+// it; rules of another spender are reported as ErrNotFound.
+type Repository interface {
+	List(spenderID int) ([]Rule, error)
+	Get(spenderID int, id int) (Rule, error)
+	Create(r Rule) (Rule, error)
+	// Update replaces the series of r unless its NextIndex is no longer
+	// index, in which case it returns ErrConflict.
+	Update(r Rule, index int) (Rule, error)
+	Delete(spenderID int, id int) error
+	Skip(spenderID int, id int, date string) error
+	// Due returns the rules whose next occurrence is on or before until.
+	Due(until string) ([]Rule, error)
+	// Advance moves r past its occurrence at r.NextIndex, whose successor
+	// is on next, and creates txn for it in the same database transaction.
+	// A nil txn skips the occurrence. It returns ErrConflict when the
+	// occurrence was already handled, by another replica or before a
+	// restart, so each occurrence is generated exactly once.
+	Advance(r Rule, next *string, txn *transaction.CreateTransactionRequest) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	columns = `id, spender_id, frequency, every, day_of_month, start_date::text, end_date::text, count, ` +
+		`transaction_type, amount, category, category_id, note, skipped::text[], next_index, next_date::text`
+	listStmt   = `SELECT ` + columns + ` FROM recurring WHERE spender_id = $1 ORDER BY id`
+	getStmt    = `SELECT ` + columns + ` FROM recurring WHERE id = $1 AND spender_id = $2`
+	dueStmt    = `SELECT ` + columns + ` FROM recurring WHERE next_date <= $1 ORDER BY id`
+	createStmt = `INSERT INTO recurring (spender_id, frequency, every, day_of_month, start_date, end_date, count, ` +
+		`transaction_type, amount, category, category_id, note, skipped, next_index, next_date) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id`
+	updateStmt = `UPDATE recurring SET frequency = $1, every = $2, day_of_month = $3, start_date = $4, end_date = $5, count = $6, ` +
+		`transaction_type = $7, amount = $8, category = $9, category_id = $10, note = $11, skipped = $12, next_index = $13, next_date = $14 ` +
+		`WHERE id = $15 AND spender_id = $16 AND next_index = $17`
+	deleteStmt  = `DELETE FROM recurring WHERE id = $1 AND spender_id = $2`
+	skipStmt    = `UPDATE recurring SET skipped = array_append(skipped, $3::date) WHERE id = $1 AND spender_id = $2 AND NOT $3::date = ANY(skipped)`
+	advanceStmt = `UPDATE recurring SET next_index = next_index + 1, next_date = $3 WHERE id = $1 AND next_index = $2`
+	insertStmt  = `INSERT INTO transaction (date, amount, category, category_id, transaction_type, note, spender_id, status, recurring_id, currency) ` +
+		`VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, (SELECT base_currency FROM spender WHERE id = $7))`
+)
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRule(row scanner) (Rule, error) {
+	var r Rule
+	err := row.Scan(&r.ID, &r.SpenderID, &r.Frequency, &r.Interval, &r.DayOfMonth, &r.StartDate, &r.EndDate, &r.Count,
+		&r.TxnType, &r.Amount, &r.Category, &r.CategoryID, &r.Note, pq.Array(&r.Skipped), &r.NextIndex, &r.NextDate)
+	if r.Skipped == nil {
+		r.Skipped = []string{}
+	}
+	return r, err
+}
+
+func (r repository) list(query string, arg interface{}) ([]Rule, error) {
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []Rule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r repository) List(spenderID int) ([]Rule, error) {
+	return r.list(listStmt, spenderID)
+}
+
+func (r repository) Due(until string) ([]Rule, error) {
+	return r.list(dueStmt, until)
+}
+
+func (r repository) Get(spenderID int, id int) (Rule, error) {
+	rule, err := scanRule(r.db.QueryRow(getStmt, id, spenderID))
+	if err == sql.ErrNoRows {
+		return Rule{}, ErrNotFound
+	}
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r repository) Create(rule Rule) (Rule, error) {
+	err := r.db.QueryRow(createStmt, rule.SpenderID, rule.Frequency, rule.Interval, rule.DayOfMonth, rule.StartDate, rule.EndDate, rule.Count,
+		rule.TxnType, rule.Amount, rule.Category, rule.CategoryID, rule.Note, pq.Array(rule.Skipped), rule.NextIndex, rule.NextDate).Scan(&rule.ID)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r repository) Update(rule Rule, index int) (Rule, error) {
+	result, err := r.db.Exec(updateStmt, rule.Frequency, rule.Interval, rule.DayOfMonth, rule.StartDate, rule.EndDate, rule.Count,
+		rule.TxnType, rule.Amount, rule.Category, rule.CategoryID, rule.Note, pq.Array(rule.Skipped), rule.NextIndex, rule.NextDate,
+		rule.ID, rule.SpenderID, index)
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := affectedOne(result, ErrConflict); err != nil {
+		return Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r repository) Delete(spenderID int, id int) error {
+	result, err := r.db.Exec(deleteStmt, id, spenderID)
+	if err != nil {
+		return err
+	}
+
+	return affectedOne(result, ErrNotFound)
+}
+
+// Skip records date as skipped; skipping a date twice is harmless.
+func (r repository) Skip(spenderID int, id int, date string) error {
+	_, err := r.db.Exec(skipStmt, id, spenderID, date)
+	return err
+}
+
+func (r repository) Advance(rule Rule, next *string, txn *transaction.CreateTransactionRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(advanceStmt, rule.ID, rule.NextIndex, next)
+	if err != nil {
+		return err
+	}
+	if err := affectedOne(result, ErrConflict); err != nil {
+		return err
+	}
+
+	if txn != nil {
+		_, err := tx.Exec(insertStmt, txn.Date, txn.Amount, txn.Category, txn.CategoryID, txn.TxnType, txn.Note, txn.SpenderId, txn.Status, rule.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// affectedOne reports notFound when a statement matched no row.
+func affectedOne(result sql.Result, notFound error) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+
+	return nil
+}
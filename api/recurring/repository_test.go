@@ -0,0 +1,185 @@
+package recurring
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+var ruleColumns = []string{"id", "spender_id", "frequency", "every", "day_of_month", "start_date", "end_date", "count",
+	"transaction_type", "amount", "category", "category_id", "note", "skipped", "next_index", "next_date"}
+
+func TestRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	rows := sqlmock.NewRows(ruleColumns).
+		AddRow(1, 3, "monthly", 1, 25, "2024-03-01", nil, nil, "income", "50000.00", "Salary", 18, "", "{2024-06-25}", 2, "2024-05-25").
+		AddRow(2, 3, "daily", 1, nil, "2024-05-01", nil, 2, "expense", "60.00", "", nil, "", "{}", 2, nil)
+	mock.ExpectQuery(listStmt).WithArgs(3).WillReturnRows(rows)
+
+	rules, err := NewRepository(db).List(3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{ID: 1, SpenderID: 3, Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: intPtr(25), StartDate: "2024-03-01", TxnType: "income",
+			Amount: 50000_00, Category: "Salary", CategoryID: intPtr(18), Skipped: []string{"2024-06-25"}, NextIndex: 2, NextDate: strPtr("2024-05-25")},
+		{ID: 2, SpenderID: 3, Frequency: FrequencyDaily, Interval: 1, StartDate: "2024-05-01", Count: intPtr(2), TxnType: "expense",
+			Amount: 60_00, Skipped: []string{}, NextIndex: 2},
+	}, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Get(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectQuery(getStmt).WithArgs(1, 4).WillReturnError(sql.ErrNoRows)
+
+	_, err := NewRepository(db).Get(4, 1)
+
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectQuery(createStmt).
+		WithArgs(3, "weekly", 1, nil, "2024-05-06", nil, nil, "expense", "100.00", "", nil, "", "{}", 0, "2024-05-06").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	rule, err := NewRepository(db).Create(Rule{SpenderID: 3, Frequency: FrequencyWeekly, Interval: 1, StartDate: "2024-05-06",
+		TxnType: "expense", Amount: 100_00, Skipped: []string{}, NextDate: strPtr("2024-05-06")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, rule.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Update(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{"series unchanged meanwhile", 1, nil},
+		{"advanced meanwhile or another spender's", 0, ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			defer db.Close()
+
+			mock.ExpectExec(updateStmt).
+				WithArgs("monthly", 1, 1, "2024-03-01", nil, nil, "expense", "449.00", "", nil, "streaming", "{}", 3, "2024-06-01", 7, 3, 3).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			_, err := NewRepository(db).Update(Rule{ID: 7, SpenderID: 3, Frequency: FrequencyMonthly, Interval: 1, DayOfMonth: intPtr(1),
+				StartDate: "2024-03-01", TxnType: "expense", Amount: 449_00, Note: "streaming", Skipped: []string{}, NextIndex: 3, NextDate: strPtr("2024-06-01")}, 3)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_DeleteAndSkip(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+	repo := NewRepository(db)
+
+	mock.ExpectExec(skipStmt).WithArgs(7, 3, "2024-06-01").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteStmt).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteStmt).WithArgs(7, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.Skip(3, 7, "2024-06-01"))
+	assert.NoError(t, repo.Delete(3, 7))
+	assert.Equal(t, ErrNotFound, repo.Delete(4, 7))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Due(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	defer db.Close()
+
+	mock.ExpectQuery(dueStmt).WithArgs("2024-05-10").WillReturnRows(sqlmock.NewRows(ruleColumns))
+
+	rules, err := NewRepository(db).Due("2024-05-10")
+
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Advance(t *testing.T) {
+	rule := Rule{ID: 7, SpenderID: 3, NextIndex: 2}
+	date := time.Date(2024, time.May, 25, 0, 0, 0, 0, time.UTC)
+	txn := transaction.CreateTransactionRequest{Date: &date, Amount: 50000_00, Category: "Salary", CategoryID: intPtr(18),
+		SpenderId: 3, TxnType: "income", Status: transaction.StatusConfirmed}
+
+	t.Run("generates the occurrence with the advance", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(advanceStmt).WithArgs(7, 2, "2024-06-25").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertStmt).WithArgs(date, "50000.00", "Salary", 18, "income", "", 3, "confirmed", 7).WillReturnResult(sqlmock.NewResult(99, 1))
+		mock.ExpectCommit()
+
+		err := NewRepository(db).Advance(rule, strPtr("2024-06-25"), &txn)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("skips without a transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(advanceStmt).WithArgs(7, 2, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := NewRepository(db).Advance(rule, nil, nil)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("conflicts when already advanced", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(advanceStmt).WithArgs(7, 2, "2024-06-25").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := NewRepository(db).Advance(rule, strPtr("2024-06-25"), &txn)
+
+		assert.Equal(t, ErrConflict, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rolls back the advance when the insert fails", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(advanceStmt).WithArgs(7, 2, "2024-06-25").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insertStmt).WillReturnError(errors.New("duplicate key"))
+		mock.ExpectRollback()
+
+		err := NewRepository(db).Advance(rule, strPtr("2024-06-25"), &txn)
+
+		assert.EqualError(t, err, "duplicate key")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,76 @@
+package recurring
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Scheduler runs Service.RunDue every interval. Replicas may each run one:
+// the repository generates every occurrence once however many try.
+type Scheduler struct {
+	service  Service
+	interval time.Duration
+	logger   *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewScheduler(service Service, interval time.Duration, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		service:  service,
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background, starting with occurrences
+// that came due while the server was down.
+func (s *Scheduler) Start() {
+	go s.run()
+	s.logger.Info("recurring transaction scheduler started", zap.Duration("interval", s.interval))
+}
+
+// Shutdown stops the scheduler and waits for a run in progress to finish,
+// or for ctx to end.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	close(s.stop)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick()
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick() {
+	created, err := s.service.RunDue()
+	if err != nil {
+		s.logger.Error("generate recurring transactions error", zap.Int("created", created), zap.Error(err))
+		return
+	}
+	if created > 0 {
+		s.logger.Info("generated recurring transactions", zap.Int("created", created))
+	}
+}
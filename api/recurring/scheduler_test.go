@@ -0,0 +1,30 @@
+package recurring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestScheduler(t *testing.T) {
+	mockService := new(MockService)
+	ran := make(chan struct{}, 10)
+	mockService.On("RunDue").Return(1, nil).Run(func(_ mock.Arguments) { ran <- struct{}{} })
+	s := NewScheduler(mockService, 10*time.Millisecond, zap.NewNop())
+
+	s.Start()
+	<-ran
+	<-ran
+	err := s.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	select {
+	case <-s.done:
+	default:
+		t.Fatal("scheduler still running after Shutdown")
+	}
+}
@@ -0,0 +1,331 @@
+package recurring
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+)
+
+type service struct {
+	repository Repository
+	categories category.Repository
+	timeZones  transaction.TimeZones
+	now        func() time.Time
+}
+
+type Service interface {
+	List(spenderID int) ([]Rule, error)
+	Get(spenderID int, id int) (Rule, error)
+	Create(spenderID int, request Request) (Rule, error)
+	Update(spenderID int, id int, request Request) (Rule, error)
+	Delete(spenderID int, id int) error
+	Skip(spenderID int, id int, date string) (Rule, error)
+	Upcoming(spenderID int, days int) ([]Occurrence, error)
+	// RunDue generates the transactions of every occurrence that is due
+	// on its spender's calendar and returns how many it created.
+	RunDue() (int, error)
+}
+
+// NewService checks rule categories against categories and dates rules on
+// the calendar of the spender's time zone.
+func NewService(repository Repository, categories category.Repository, timeZones transaction.TimeZones) Service {
+	return service{
+		repository: repository,
+		categories: categories,
+		timeZones:  timeZones,
+		now:        time.Now,
+	}
+}
+
+func (s service) List(spenderID int) ([]Rule, error) {
+	return s.repository.List(spenderID)
+}
+
+func (s service) Get(spenderID int, id int) (Rule, error) {
+	return s.repository.Get(spenderID, id)
+}
+
+// Create adds a rule. Occurrences from StartDate on are generated as they
+// come due, including those already past, of which there may be at most
+// MaxBackfill.
+func (s service) Create(spenderID int, request Request) (Rule, error) {
+	rule, err := s.rule(spenderID, request)
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := s.checkBackfill(rule); err != nil {
+		return Rule{}, err
+	}
+	rule.Skipped = []string{}
+	rule.NextDate = rule.dateOf(0)
+
+	return s.repository.Create(rule)
+}
+
+// Update replaces the series. Occurrences already generated or skipped are
+// kept; the new series continues after the last of them.
+func (s service) Update(spenderID int, id int, request Request) (Rule, error) {
+	current, err := s.repository.Get(spenderID, id)
+	if err != nil {
+		return Rule{}, err
+	}
+	rule, err := s.rule(spenderID, request)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rule.ID = id
+	rule.Skipped = current.Skipped
+	if current.NextIndex > 0 {
+		last, _ := current.occurrence(current.NextIndex - 1)
+		rule.NextIndex = rule.indexAfter(last)
+	} else if err := s.checkBackfill(rule); err != nil {
+		return Rule{}, err
+	}
+	rule.NextDate = rule.dateOf(rule.NextIndex)
+
+	return s.repository.Update(rule, current.NextIndex)
+}
+
+func (s service) Delete(spenderID int, id int) error {
+	return s.repository.Delete(spenderID, id)
+}
+
+// Skip keeps the occurrence on date from being generated. date must be an
+// occurrence that has not been generated yet.
+func (s service) Skip(spenderID int, id int, date string) (Rule, error) {
+	rule, err := s.repository.Get(spenderID, id)
+	if err != nil {
+		return Rule{}, err
+	}
+	day, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return Rule{}, ErrInvalidDate
+	}
+
+	n := rule.NextIndex
+	d, ok := rule.occurrence(n)
+	for ok && d.Before(day) {
+		n++
+		d, ok = rule.occurrence(n)
+	}
+	if !ok || !d.Equal(day) {
+		return Rule{}, ErrInvalidOccurrence
+	}
+
+	if err := s.repository.Skip(spenderID, id, date); err != nil {
+		return Rule{}, err
+	}
+	if !rule.skips(date) {
+		rule.Skipped = append(rule.Skipped, date)
+	}
+
+	return rule, nil
+}
+
+// Upcoming lists the occurrences of the spender's rules from today through
+// the given number of days, leaving out skipped ones, by date.
+func (s service) Upcoming(spenderID int, days int) ([]Occurrence, error) {
+	if days == 0 {
+		days = DefaultUpcomingDays
+	}
+	if days < 1 || days > MaxUpcomingDays {
+		return nil, ErrInvalidDays
+	}
+
+	rules, err := s.repository.List(spenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := s.today(spenderID)
+	until := today.AddDate(0, 0, days)
+	occurrences := []Occurrence{}
+	for _, rule := range rules {
+		for n := rule.NextIndex; ; n++ {
+			d, ok := rule.occurrence(n)
+			if !ok || d.After(until) {
+				break
+			}
+			date := d.Format(dateLayout)
+			if d.Before(today) || rule.skips(date) {
+				continue
+			}
+			occurrences = append(occurrences, Occurrence{
+				RuleID:     rule.ID,
+				Date:       date,
+				TxnType:    rule.TxnType,
+				Amount:     rule.Amount,
+				Category:   rule.Category,
+				CategoryID: rule.CategoryID,
+				Note:       rule.Note,
+			})
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool { return occurrences[i].Date < occurrences[j].Date })
+
+	return occurrences, nil
+}
+
+func (s service) RunDue() (int, error) {
+	now := s.now()
+	// No calendar runs more than 14 hours ahead of UTC.
+	rules, err := s.repository.Due(now.UTC().Add(14 * time.Hour).Format(dateLayout))
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, rule := range rules {
+		loc := s.location(rule.SpenderID)
+		today := now.In(loc).Format(dateLayout)
+		for rule.NextDate != nil && *rule.NextDate <= today {
+			next := rule.dateOf(rule.NextIndex + 1)
+			var txn *transaction.CreateTransactionRequest
+			if !rule.skips(*rule.NextDate) {
+				t := rule.transaction(*rule.NextDate, loc)
+				txn = &t
+			}
+
+			err := s.repository.Advance(rule, next, txn)
+			if errors.Is(err, ErrConflict) {
+				break
+			}
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			if txn != nil {
+				created++
+			}
+			rule.NextIndex++
+			rule.NextDate = next
+		}
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// rule validates request and turns it into a rule of the spender, without
+// its progress.
+func (s service) rule(spenderID int, request Request) (Rule, error) {
+	switch request.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return Rule{}, ErrInvalidFrequency
+	}
+	if request.Interval == 0 {
+		request.Interval = 1
+	}
+	if request.Interval < 1 {
+		return Rule{}, ErrInvalidInterval
+	}
+	if request.DayOfMonth != nil && (request.Frequency != FrequencyMonthly || *request.DayOfMonth < 1 || *request.DayOfMonth > 31) {
+		return Rule{}, ErrInvalidDayOfMonth
+	}
+	if request.TxnType != "expense" && request.TxnType != "income" {
+		return Rule{}, ErrInvalidTxnType
+	}
+	if request.Amount <= 0 {
+		return Rule{}, ErrInvalidAmount
+	}
+
+	if request.StartDate == "" {
+		request.StartDate = s.today(spenderID).Format(dateLayout)
+	}
+	start, err := time.Parse(dateLayout, request.StartDate)
+	if err != nil {
+		return Rule{}, ErrInvalidDate
+	}
+	if request.EndDate != nil {
+		end, err := time.Parse(dateLayout, *request.EndDate)
+		if err != nil {
+			return Rule{}, ErrInvalidDate
+		}
+		if end.Before(start) || request.Count != nil {
+			return Rule{}, ErrInvalidEnd
+		}
+	}
+	if request.Count != nil && *request.Count < 1 {
+		return Rule{}, ErrInvalidEnd
+	}
+
+	categoryID, name, err := s.resolveCategory(spenderID, request.CategoryID, request.Category)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return Rule{
+		SpenderID:  spenderID,
+		Frequency:  request.Frequency,
+		Interval:   request.Interval,
+		DayOfMonth: request.DayOfMonth,
+		StartDate:  request.StartDate,
+		EndDate:    request.EndDate,
+		Count:      request.Count,
+		TxnType:    request.TxnType,
+		Amount:     request.Amount,
+		Category:   name,
+		CategoryID: categoryID,
+		Note:       request.Note,
+	}, nil
+}
+
+// checkBackfill reports ErrStartTooEarly when more than MaxBackfill
+// occurrences of a series that has generated nothing yet are due today.
+func (s service) checkBackfill(rule Rule) error {
+	today, _ := time.Parse(dateLayout, s.today(rule.SpenderID).Format(dateLayout))
+	if d, ok := rule.occurrence(MaxBackfill); ok && !d.After(today) {
+		return ErrStartTooEarly
+	}
+	return nil
+}
+
+// resolveCategory matches the category like transactions do: by id, or by
+// an English or Thai name preferring the spender's own categories. A name
+// that matches none is kept as free text.
+func (s service) resolveCategory(spenderID int, id *int, name string) (*int, string, error) {
+	if id == nil && name == "" {
+		return nil, "", nil
+	}
+	visible, err := s.categories.List(spenderID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var found *category.Category
+	for i, c := range visible {
+		matches := (id != nil && c.ID == *id) || (id == nil && c.Named(name))
+		if matches && (found == nil || c.SpenderID != nil) {
+			found = &visible[i]
+		}
+	}
+	switch {
+	case found == nil && id != nil:
+		return nil, "", ErrInvalidCategory
+	case found == nil:
+		return nil, name, nil
+	case name == "":
+		name = found.NameEN
+	}
+	return &found.ID, name, nil
+}
+
+// today is the spender's current date, in UTC like the dates of
+// occurrences.
+func (s service) today(spenderID int) time.Time {
+	now := s.now().In(s.location(spenderID))
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s service) location(spenderID int) *time.Location {
+	loc, err := time.LoadLocation(s.timeZones.TimeZone(spenderID))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,220 @@
+package recurring
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedTimeZone string
+
+func (z fixedTimeZone) TimeZone(int) string { return string(z) }
+
+// newTestService runs on Bangkok time with the clock at noon on
+// 10 May 2024 there.
+func newTestService() (*service, transaction.Repository) {
+	transactions := transaction.NewMemoryRepository()
+	categories := category.NewMemoryRepository()
+	transactions.UseCategories(categories)
+	return &service{
+		repository: NewMemoryRepository(transactions),
+		categories: categories,
+		timeZones:  fixedTimeZone("Asia/Bangkok"),
+		now:        func() time.Time { return time.Date(2024, time.May, 10, 5, 0, 0, 0, time.UTC) },
+	}, transactions
+}
+
+func spent(t *testing.T, transactions transaction.Repository) []transaction.Transaction {
+	all, err := transactions.GetAll(3, transaction.Filter{}, transaction.Pagination{Page: 1, ItemPerPage: 100})
+	assert.NoError(t, err)
+	return all
+}
+
+func TestService_Create(t *testing.T) {
+	food, unknown := 1, 99
+
+	tests := []struct {
+		name     string
+		request  Request
+		expected Rule
+		err      error
+	}{
+		{"monthly rent starting today", Request{Frequency: FrequencyMonthly, TxnType: "expense", Amount: 12000_00, Category: "Rent", Note: "condo"},
+			Rule{ID: 1, SpenderID: 3, Frequency: FrequencyMonthly, Interval: 1, StartDate: "2024-05-10", TxnType: "expense", Amount: 12000_00,
+				Category: "Rent", CategoryID: intPtr(10), Note: "condo", Skipped: []string{}, NextDate: strPtr("2024-05-10")}, nil},
+		{"category by id", Request{Frequency: FrequencyWeekly, Interval: 2, StartDate: "2024-06-03", TxnType: "expense", Amount: 500_00, CategoryID: &food, Count: intPtr(3)},
+			Rule{ID: 1, SpenderID: 3, Frequency: FrequencyWeekly, Interval: 2, StartDate: "2024-06-03", Count: intPtr(3), TxnType: "expense", Amount: 500_00,
+				Category: "Food", CategoryID: &food, Skipped: []string{}, NextDate: strPtr("2024-06-03")}, nil},
+		{"free text category", Request{Frequency: FrequencyYearly, StartDate: "2024-12-25", TxnType: "expense", Amount: 990_00, Category: "domain name"},
+			Rule{ID: 1, SpenderID: 3, Frequency: FrequencyYearly, Interval: 1, StartDate: "2024-12-25", TxnType: "expense", Amount: 990_00,
+				Category: "domain name", Skipped: []string{}, NextDate: strPtr("2024-12-25")}, nil},
+		{"unknown frequency", Request{Frequency: "hourly", TxnType: "expense", Amount: 1_00}, Rule{}, ErrInvalidFrequency},
+		{"too many occurrences already due", Request{Frequency: FrequencyDaily, StartDate: "2024-04-09", TxnType: "expense", Amount: 1_00}, Rule{}, ErrStartTooEarly},
+		{"negative interval", Request{Frequency: FrequencyDaily, Interval: -1, TxnType: "expense", Amount: 1_00}, Rule{}, ErrInvalidInterval},
+		{"day of month on a weekly rule", Request{Frequency: FrequencyWeekly, DayOfMonth: intPtr(1), TxnType: "expense", Amount: 1_00}, Rule{}, ErrInvalidDayOfMonth},
+		{"day of month out of range", Request{Frequency: FrequencyMonthly, DayOfMonth: intPtr(32), TxnType: "expense", Amount: 1_00}, Rule{}, ErrInvalidDayOfMonth},
+		{"unknown type", Request{Frequency: FrequencyDaily, TxnType: "transfer", Amount: 1_00}, Rule{}, ErrInvalidTxnType},
+		{"zero amount", Request{Frequency: FrequencyDaily, TxnType: "income"}, Rule{}, ErrInvalidAmount},
+		{"bad start date", Request{Frequency: FrequencyDaily, TxnType: "income", Amount: 1_00, StartDate: "10/05/2024"}, Rule{}, ErrInvalidDate},
+		{"end date and count", Request{Frequency: FrequencyDaily, TxnType: "income", Amount: 1_00, EndDate: strPtr("2024-06-01"), Count: intPtr(3)}, Rule{}, ErrInvalidEnd},
+		{"end before start", Request{Frequency: FrequencyDaily, TxnType: "income", Amount: 1_00, EndDate: strPtr("2024-05-01")}, Rule{}, ErrInvalidEnd},
+		{"unknown category", Request{Frequency: FrequencyDaily, TxnType: "expense", Amount: 1_00, CategoryID: &unknown}, Rule{}, ErrInvalidCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestService()
+
+			rule, err := s.Create(3, tt.request)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, rule)
+		})
+	}
+}
+
+func TestService_RunDue(t *testing.T) {
+	t.Run("generates due occurrences once, in the spender's calendar", func(t *testing.T) {
+		// Arrange
+		s, transactions := newTestService()
+		salary, _ := s.Create(3, Request{Frequency: FrequencyMonthly, DayOfMonth: intPtr(25), StartDate: "2024-03-01", TxnType: "income", Amount: 50000_00, Category: "Salary"})
+		_, _ = s.Create(3, Request{Frequency: FrequencyDaily, StartDate: "2024-05-11", TxnType: "expense", Amount: 60_00})
+
+		// Act
+		created, err := s.RunDue()
+		again, againErr := s.RunDue()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, againErr)
+		assert.Equal(t, 2, created)
+		assert.Equal(t, 0, again)
+
+		generated := spent(t, transactions)
+		assert.Len(t, generated, 2)
+		bangkok, _ := time.LoadLocation("Asia/Bangkok")
+		assert.True(t, time.Date(2024, time.March, 25, 0, 0, 0, 0, bangkok).Equal(*generated[0].Date))
+		assert.Equal(t, money.Amount(50000_00), generated[1].Amount)
+		assert.Equal(t, "Salary", generated[1].Category)
+
+		rule, _ := s.Get(3, salary.ID)
+		assert.Equal(t, "2024-05-25", *rule.NextDate)
+	})
+
+	t.Run("occurrences come due at the spender's midnight", func(t *testing.T) {
+		s, transactions := newTestService()
+		_, _ = s.Create(3, Request{Frequency: FrequencyDaily, StartDate: "2024-05-11", TxnType: "expense", Amount: 60_00})
+		s.now = func() time.Time { return time.Date(2024, time.May, 10, 17, 0, 0, 0, time.UTC) }
+
+		created, err := s.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, created, "it is already 11 May in Bangkok")
+		assert.Len(t, spent(t, transactions), 1)
+	})
+
+	t.Run("skipped occurrences are passed over", func(t *testing.T) {
+		s, transactions := newTestService()
+		rule, _ := s.Create(3, Request{Frequency: FrequencyDaily, StartDate: "2024-05-12", TxnType: "expense", Amount: 60_00})
+		_, err := s.Skip(3, rule.ID, "2024-05-13")
+		assert.NoError(t, err)
+		s.now = func() time.Time { return time.Date(2024, time.May, 14, 5, 0, 0, 0, time.UTC) }
+
+		created, err := s.RunDue()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, created)
+		for _, txn := range spent(t, transactions) {
+			assert.NotEqual(t, 13, txn.Date.Day())
+		}
+	})
+
+	t.Run("concurrent schedulers generate each occurrence once", func(t *testing.T) {
+		s, transactions := newTestService()
+		_, _ = s.Create(3, Request{Frequency: FrequencyDaily, StartDate: "2024-04-11", TxnType: "expense", Amount: 60_00})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = s.RunDue()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, spent(t, transactions), 30)
+	})
+}
+
+func TestService_Skip(t *testing.T) {
+	s, _ := newTestService()
+	rule, _ := s.Create(3, Request{Frequency: FrequencyWeekly, StartDate: "2024-05-06", TxnType: "expense", Amount: 100_00})
+	_, _ = s.RunDue()
+
+	skipped, err := s.Skip(3, rule.ID, "2024-05-20")
+	_, twiceErr := s.Skip(3, rule.ID, "2024-05-20")
+	_, pastErr := s.Skip(3, rule.ID, "2024-05-06")
+	_, offErr := s.Skip(3, rule.ID, "2024-05-21")
+	_, badErr := s.Skip(3, rule.ID, "20 May")
+	_, otherErr := s.Skip(4, rule.ID, "2024-05-20")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-05-20"}, skipped.Skipped)
+	assert.NoError(t, twiceErr)
+	assert.Equal(t, ErrInvalidOccurrence, pastErr, "already generated")
+	assert.Equal(t, ErrInvalidOccurrence, offErr)
+	assert.Equal(t, ErrInvalidDate, badErr)
+	assert.Equal(t, ErrNotFound, otherErr)
+
+	rule, _ = s.Get(3, rule.ID)
+	assert.Equal(t, []string{"2024-05-20"}, rule.Skipped)
+}
+
+func TestService_Update(t *testing.T) {
+	// Arrange
+	s, transactions := newTestService()
+	rule, _ := s.Create(3, Request{Frequency: FrequencyMonthly, StartDate: "2024-03-01", TxnType: "expense", Amount: 399_00, Note: "streaming"})
+	_, _ = s.RunDue()
+
+	// Act
+	updated, err := s.Update(3, rule.ID, Request{Frequency: FrequencyMonthly, DayOfMonth: intPtr(1), StartDate: "2024-03-01", TxnType: "expense", Amount: 449_00, Note: "streaming"})
+	_, otherErr := s.Update(4, rule.ID, Request{Frequency: FrequencyMonthly, TxnType: "expense", Amount: 1_00})
+	s.now = func() time.Time { return time.Date(2024, time.June, 2, 5, 0, 0, 0, time.UTC) }
+	created, _ := s.RunDue()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, ErrNotFound, otherErr)
+	assert.Equal(t, "2024-06-01", *updated.NextDate)
+	assert.Equal(t, 1, created)
+	generated := spent(t, transactions)
+	assert.Len(t, generated, 4)
+	assert.Equal(t, money.Amount(399_00), generated[2].Amount, "earlier occurrences keep their amount")
+	assert.Equal(t, money.Amount(449_00), generated[3].Amount)
+}
+
+func TestService_Upcoming(t *testing.T) {
+	s, _ := newTestService()
+	rent, _ := s.Create(3, Request{Frequency: FrequencyMonthly, StartDate: "2024-06-01", TxnType: "expense", Amount: 12000_00})
+	_, _ = s.Create(3, Request{Frequency: FrequencyWeekly, StartDate: "2024-05-01", TxnType: "expense", Amount: 100_00})
+	_, _ = s.Create(4, Request{Frequency: FrequencyDaily, TxnType: "expense", Amount: 1_00})
+	_, _ = s.Skip(3, rent.ID, "2024-06-01")
+
+	occurrences, err := s.Upcoming(3, 0)
+	_, daysErr := s.Upcoming(3, 400)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ErrInvalidDays, daysErr)
+	var dates []string
+	for _, o := range occurrences {
+		dates = append(dates, o.Date)
+	}
+	assert.Equal(t, []string{"2024-05-15", "2024-05-22", "2024-05-29", "2024-06-05"}, dates,
+		"occurrences before today are not upcoming and skipped ones are left out")
+}
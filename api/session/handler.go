@@ -0,0 +1,79 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	Login(c echo.Context) error
+	Refresh(c echo.Context) error
+	Logout(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) Login(c echo.Context) error {
+	var request LoginRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Login(request)
+	if err != nil {
+		return h.fail(c, "login error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Refresh(c echo.Context) error {
+	var request RefreshRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Refresh(request.RefreshToken)
+	if err != nil {
+		return h.fail(c, "refresh token error", err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h handler) Logout(c echo.Context) error {
+	var request RefreshRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	if err := h.service.Logout(request.RefreshToken); err != nil {
+		return h.fail(c, "logout error", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, user.ErrInvalidCredentials), errors.Is(err, user.ErrLocked), errors.Is(err, ErrInvalidRefreshToken):
+		return c.JSON(http.StatusUnauthorized, errs.Build(err))
+	default:
+		mlog.L(c).Error(msg, zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Login(request LoginRequest) (TokenResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(TokenResponse), args.Error(1)
+}
+
+func (m *MockService) Refresh(refreshToken string) (TokenResponse, error) {
+	args := m.Called(refreshToken)
+	return args.Get(0).(TokenResponse), args.Error(1)
+}
+
+func (m *MockService) Logout(refreshToken string) error {
+	args := m.Called(refreshToken)
+	return args.Error(0)
+}
+
+func newJSONContext(body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestHandler_Login(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"invalid credentials", user.ErrInvalidCredentials, http.StatusUnauthorized},
+		{"locked", user.ErrLocked, http.StatusUnauthorized},
+		{"internal error", errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newJSONContext(`{"username": "hongjot", "password": "p@ssw0rd!"}`)
+			mockService := new(MockService)
+			mockService.On("Login", LoginRequest{Username: "hongjot", Password: "p@ssw0rd!"}).Return(TokenResponse{AccessToken: "a"}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Login(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Refresh(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"invalid refresh token", ErrInvalidRefreshToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newJSONContext(`{"refresh_token": "refresh"}`)
+			mockService := new(MockService)
+			mockService.On("Refresh", "refresh").Return(TokenResponse{AccessToken: "a"}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Refresh(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_Logout(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"invalid refresh token", ErrInvalidRefreshToken, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newJSONContext(`{"refresh_token": "refresh"}`)
+			mockService := new(MockService)
+			mockService.On("Logout", "refresh").Return(tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Logout(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu     sync.Mutex
+	tokens map[int]RefreshToken
+	nextID int
+}
+
+// MemoryRepository is the in-memory refresh token store. RevokeUser lets the
+// in-memory user repository revoke a user's tokens when the password changes,
+// as the SQL one does in the same transaction.
+type MemoryRepository interface {
+	Repository
+	RevokeUser(userID int)
+}
+
+// NewMemoryRepository keeps refresh tokens in process memory for the
+// --storage=memory server mode.
+func NewMemoryRepository() MemoryRepository {
+	return &memoryRepository{
+		tokens: map[int]RefreshToken{},
+		nextID: 1,
+	}
+}
+
+func (r *memoryRepository) Create(token RefreshToken) (RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = r.nextID
+	r.nextID++
+	r.tokens[token.ID] = token
+
+	return token, nil
+}
+
+func (r *memoryRepository) FindByHash(tokenHash string) (RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return RefreshToken{}, ErrNotFound
+}
+
+func (r *memoryRepository) Revoke(id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[id]
+	if !ok || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	r.tokens[id] = t
+
+	return true, nil
+}
+
+func (r *memoryRepository) RevokeFamily(familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+			r.tokens[id] = t
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) RevokeUser(userID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+			r.tokens[id] = t
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var ErrNotFound = errors.New("refresh token not found")
+
+type Repository interface {
+	Create(token RefreshToken) (RefreshToken, error)
+	FindByHash(tokenHash string) (RefreshToken, error)
+	Revoke(id int) (bool, error)
+	RevokeFamily(familyID string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+func (r repository) Create(token RefreshToken) (RefreshToken, error) {
+	query := `INSERT INTO refresh_token (user_id, token_hash, family_id, expires_at) VALUES ($1, $2, $3, $4) RETURNING id;`
+	err := r.db.QueryRow(query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt).Scan(&token.ID)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r repository) FindByHash(tokenHash string) (RefreshToken, error) {
+	query := `SELECT id, user_id, token_hash, family_id, expires_at, revoked_at FROM refresh_token WHERE token_hash = $1`
+
+	var t RefreshToken
+	err := r.db.QueryRow(query, tokenHash).Scan(&t.ID, &t.UserID, &t.TokenHash, &t.FamilyID, &t.ExpiresAt, &t.RevokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RefreshToken{}, ErrNotFound
+	}
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	return t, nil
+}
+
+// Revoke marks a token as used. It reports false when the token had already
+// been revoked, which means another request rotated it first.
+func (r repository) Revoke(id int) (bool, error) {
+	query := `UPDATE refresh_token SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+func (r repository) RevokeFamily(familyID string) error {
+	query := `UPDATE refresh_token SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(query, familyID)
+	return err
+}
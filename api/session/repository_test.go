@@ -0,0 +1,72 @@
+package session
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindByHash(t *testing.T) {
+	t.Run("should return refresh token", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		expiresAt := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows([]string{"id", "user_id", "token_hash", "family_id", "expires_at", "revoked_at"}).
+			AddRow(1, 7, "hash", "family", expiresAt, nil)
+		mock.ExpectQuery(`SELECT id, user_id, token_hash, family_id, expires_at, revoked_at FROM refresh_token WHERE token_hash = \$1`).
+			WithArgs("hash").WillReturnRows(rows)
+
+		token, err := repo.FindByHash("hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, RefreshToken{ID: 1, UserID: 7, TokenHash: "hash", FamilyID: "family", ExpiresAt: expiresAt}, token)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectQuery(`SELECT (.+) FROM refresh_token`).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindByHash("hash")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{"first rotation wins", 1, true},
+		{"already revoked", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+			repo := NewRepository(db)
+			mock.ExpectExec(`UPDATE refresh_token SET revoked_at = now\(\) WHERE id = \$1 AND revoked_at IS NULL`).
+				WithArgs(1).WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			revoked, err := repo.Revoke(1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, revoked)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
+	"github.com/google/uuid"
+)
+
+const tokenType = "Bearer"
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+type service struct {
+	repository Repository
+	users      user.Service
+	signer     auth.Signer
+	cfg        config.Auth
+	now        func() time.Time
+}
+
+type Service interface {
+	Login(request LoginRequest) (TokenResponse, error)
+	Refresh(refreshToken string) (TokenResponse, error)
+	Logout(refreshToken string) error
+}
+
+func NewService(repository Repository, users user.Service, signer auth.Signer, cfg config.Auth) Service {
+	return service{
+		repository: repository,
+		users:      users,
+		signer:     signer,
+		cfg:        cfg,
+		now:        time.Now,
+	}
+}
+
+func (s service) Login(request LoginRequest) (TokenResponse, error) {
+	u, err := s.users.Authenticate(request.Username, request.Password)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return s.issue(u, uuid.NewString())
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and a new
+// one is issued in the same family. Presenting a token that was already
+// rotated means it has leaked, so the whole family is revoked and the
+// legitimate holder has to log in again.
+func (s service) Refresh(refreshToken string) (TokenResponse, error) {
+	t, err := s.repository.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, ErrNotFound) {
+		return TokenResponse{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if t.RevokedAt != nil {
+		if err := s.repository.RevokeFamily(t.FamilyID); err != nil {
+			return TokenResponse{}, err
+		}
+		return TokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	if !s.now().Before(t.ExpiresAt) {
+		return TokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	u, err := s.users.Get(t.UserID)
+	if errors.Is(err, user.ErrNotFound) {
+		return TokenResponse{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if u.Locked(s.now()) {
+		return TokenResponse{}, user.ErrLocked
+	}
+
+	revoked, err := s.repository.Revoke(t.ID)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if !revoked {
+		if err := s.repository.RevokeFamily(t.FamilyID); err != nil {
+			return TokenResponse{}, err
+		}
+		return TokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	return s.issue(u, t.FamilyID)
+}
+
+// Logout revokes every token in the presented token's family. Access tokens
+// already issued stay valid until they expire.
+func (s service) Logout(refreshToken string) error {
+	t, err := s.repository.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repository.RevokeFamily(t.FamilyID)
+}
+
+func (s service) issue(u user.User, familyID string) (TokenResponse, error) {
+	accessToken, expiresAt, err := s.signer.Sign(u.Identity())
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	_, err = s.repository.Create(RefreshToken{
+		UserID:    u.ID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: s.now().Add(s.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    tokenType,
+		ExpiresIn:    int(expiresAt.Sub(s.now()).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is what gets stored, so a database leak does not hand out usable
+// refresh tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,232 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(token RefreshToken) (RefreshToken, error) {
+	args := m.Called(token)
+	return args.Get(0).(RefreshToken), args.Error(1)
+}
+
+func (m *MockRepository) FindByHash(tokenHash string) (RefreshToken, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(RefreshToken), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(id int) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) RevokeFamily(familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+type MockUsers struct {
+	mock.Mock
+}
+
+func (m *MockUsers) Register(request user.RegisterRequest) (user.RegisterResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(user.RegisterResponse), args.Error(1)
+}
+
+func (m *MockUsers) Authenticate(username, password string) (user.User, error) {
+	args := m.Called(username, password)
+	return args.Get(0).(user.User), args.Error(1)
+}
+
+func (m *MockUsers) Get(id int) (user.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(user.User), args.Error(1)
+}
+
+func (m *MockUsers) ChangePassword(userID int, request user.ChangePasswordRequest) error {
+	args := m.Called(userID, request)
+	return args.Error(0)
+}
+
+type stubSigner struct{}
+
+func (stubSigner) Sign(id auth.Identity) (string, time.Time, error) {
+	return "access-token", testNow.Add(15 * time.Minute), nil
+}
+
+func (stubSigner) Verify(token string) (auth.Identity, error) {
+	return auth.Identity{}, auth.ErrInvalidToken
+}
+
+var testNow = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestService(repo Repository, users user.Service) service {
+	return service{
+		repository: repo,
+		users:      users,
+		signer:     stubSigner{},
+		cfg:        config.Auth{RefreshTokenTTL: 24 * time.Hour},
+		now:        func() time.Time { return testNow },
+	}
+}
+
+func TestService_Login(t *testing.T) {
+	t.Run("should issue access and refresh token", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		users.On("Authenticate", "hongjot", "p@ssw0rd!").Return(user.User{ID: 7, SpenderID: 3}, nil)
+		repo.On("Create", mock.MatchedBy(func(t RefreshToken) bool {
+			return t.UserID == 7 && t.FamilyID != "" && len(t.TokenHash) == 64 && t.ExpiresAt.Equal(testNow.Add(24*time.Hour))
+		})).Return(RefreshToken{ID: 1}, nil)
+
+		result, err := s.Login(LoginRequest{Username: "hongjot", Password: "p@ssw0rd!"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", result.AccessToken)
+		assert.Equal(t, "Bearer", result.TokenType)
+		assert.Equal(t, 900, result.ExpiresIn)
+		assert.NotEmpty(t, result.RefreshToken)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should not issue tokens for invalid credentials", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		users.On("Authenticate", "hongjot", "wrong").Return(user.User{}, user.ErrInvalidCredentials)
+
+		_, err := s.Login(LoginRequest{Username: "hongjot", Password: "wrong"})
+
+		assert.Equal(t, user.ErrInvalidCredentials, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestService_Refresh(t *testing.T) {
+	revokedAt := testNow.Add(-time.Minute)
+	active := RefreshToken{ID: 1, UserID: 7, FamilyID: "family", ExpiresAt: testNow.Add(time.Hour)}
+
+	t.Run("should rotate refresh token within the same family", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		repo.On("FindByHash", hashToken("refresh")).Return(active, nil)
+		users.On("Get", 7).Return(user.User{ID: 7, SpenderID: 3}, nil)
+		repo.On("Revoke", 1).Return(true, nil)
+		repo.On("Create", mock.MatchedBy(func(t RefreshToken) bool {
+			return t.FamilyID == "family" && t.TokenHash != hashToken("refresh")
+		})).Return(RefreshToken{ID: 2}, nil)
+
+		result, err := s.Refresh("refresh")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "refresh", result.RefreshToken)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should revoke whole family when a rotated token is reused", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		reused := active
+		reused.RevokedAt = &revokedAt
+		repo.On("FindByHash", hashToken("refresh")).Return(reused, nil)
+		repo.On("RevokeFamily", "family").Return(nil).Once()
+
+		_, err := s.Refresh("refresh")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should revoke whole family when a concurrent request rotated first", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		repo.On("FindByHash", hashToken("refresh")).Return(active, nil)
+		users.On("Get", 7).Return(user.User{ID: 7, SpenderID: 3}, nil)
+		repo.On("Revoke", 1).Return(false, nil)
+		repo.On("RevokeFamily", "family").Return(nil).Once()
+
+		_, err := s.Refresh("refresh")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should reject expired token", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		expired := active
+		expired.ExpiresAt = testNow
+		repo.On("FindByHash", hashToken("refresh")).Return(expired, nil)
+
+		_, err := s.Refresh("refresh")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+	})
+
+	t.Run("should reject unknown token", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		repo.On("FindByHash", hashToken("refresh")).Return(RefreshToken{}, ErrNotFound)
+
+		_, err := s.Refresh("refresh")
+
+		assert.Equal(t, ErrInvalidRefreshToken, err)
+	})
+
+	t.Run("should reject locked user", func(t *testing.T) {
+		repo := new(MockRepository)
+		users := new(MockUsers)
+		s := newTestService(repo, users)
+		lockedUntil := testNow.Add(time.Minute)
+		repo.On("FindByHash", hashToken("refresh")).Return(active, nil)
+		users.On("Get", 7).Return(user.User{ID: 7, LockedUntil: &lockedUntil}, nil)
+
+		_, err := s.Refresh("refresh")
+
+		assert.Equal(t, user.ErrLocked, err)
+		repo.AssertNotCalled(t, "Revoke", mock.Anything)
+	})
+}
+
+func TestService_Logout(t *testing.T) {
+	t.Run("should revoke token family", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(repo, new(MockUsers))
+		repo.On("FindByHash", hashToken("refresh")).Return(RefreshToken{ID: 1, FamilyID: "family"}, nil)
+		repo.On("RevokeFamily", "family").Return(nil).Once()
+
+		err := s.Logout("refresh")
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		repo := new(MockRepository)
+		s := newTestService(repo, new(MockUsers))
+		repo.On("FindByHash", hashToken("refresh")).Return(RefreshToken{}, errors.New("db down"))
+
+		err := s.Logout("refresh")
+
+		assert.EqualError(t, err, "db down")
+	})
+}
@@ -0,0 +1,28 @@
+package session
+
+import "time"
+
+type RefreshToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	FamilyID  string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
@@ -0,0 +1,33 @@
+package spender
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// emailIndex is the case-insensitive unique index on spender.email.
+const emailIndex = "spender_email_key"
+
+var (
+	ErrInvalidEmail = errors.New("email is not a valid address")
+	ErrEmailTaken   = errors.New("email is already registered")
+)
+
+// ValidEmail accepts a bare address such as "hong@jot.ok". Display names
+// ("HongJot <hong@jot.ok>") are rejected since only the address is stored.
+func ValidEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil {
+		return false
+	}
+	return addr.Address == email && strings.Contains(email[strings.LastIndex(email, "@"):], ".")
+}
+
+// IsEmailTaken reports whether err is a violation of the unique email index.
+func IsEmailTaken(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == emailIndex
+}
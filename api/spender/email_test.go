@@ -0,0 +1,25 @@
+package spender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidEmail(t *testing.T) {
+	cases := []struct {
+		email string
+		want  bool
+	}{
+		{"hong@jot.ok", true},
+		{"Hong.Jot+slip@jot.co.th", true},
+		{"hong-at-jot", false},
+		{"hong@jot", false},
+		{"HongJot <hong@jot.ok>", false},
+		{"", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, ValidEmail(tc.email), tc.email)
+	}
+}
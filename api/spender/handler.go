@@ -0,0 +1,149 @@
+package spender
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type handler struct {
+	flag    config.FeatureFlag
+	service Service
+}
+
+type Handler interface {
+	GetAll(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Patch(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+func NewHandler(flag config.FeatureFlag, service Service) Handler {
+	return handler{
+		flag:    flag,
+		service: service,
+	}
+}
+
+func (h handler) Create(c echo.Context) error {
+	if !h.flag.EnableCreateSpender {
+		return c.JSON(http.StatusForbidden, "create new spender feature is disabled")
+	}
+
+	logger := mlog.L(c)
+	var sp Spender
+	err := c.Bind(&sp)
+	if err != nil {
+		logger.Error("bad request body", zap.Error(err))
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	sp, err = h.service.Create(sp)
+	if err != nil {
+		return h.fail(c, "create spender error", err)
+	}
+
+	logger.Info("create successfully", zap.Int64("id", sp.ID))
+	return c.JSON(http.StatusCreated, sp)
+}
+
+func (h handler) GetAll(c echo.Context) error {
+	sps, err := h.service.GetAll()
+	if err != nil {
+		return h.fail(c, "get spenders error", err)
+	}
+
+	return c.JSON(http.StatusOK, sps)
+}
+
+func (h handler) Get(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid spender id")
+	}
+
+	sp, err := h.service.Get(id)
+	if err != nil {
+		return h.fail(c, "get spender error", err)
+	}
+
+	return c.JSON(http.StatusOK, sp)
+}
+
+func (h handler) Update(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid spender id")
+	}
+
+	var sp Spender
+	if err := c.Bind(&sp); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	sp.ID = int64(id)
+
+	sp, err = h.service.Update(sp)
+	if err != nil {
+		return h.fail(c, "update spender error", err)
+	}
+
+	mlog.L(c).Info("update successfully", zap.Int64("id", sp.ID))
+	return c.JSON(http.StatusOK, sp)
+}
+
+func (h handler) Patch(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid spender id")
+	}
+
+	var patch PatchSpender
+	if err := c.Bind(&patch); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	sp, err := h.service.Patch(id, patch)
+	if err != nil {
+		return h.fail(c, "patch spender error", err)
+	}
+
+	mlog.L(c).Info("update successfully", zap.Int64("id", sp.ID))
+	return c.JSON(http.StatusOK, sp)
+}
+
+func (h handler) Delete(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid spender id")
+	}
+
+	policy := c.QueryParam("policy")
+	if err := h.service.Delete(id, policy); err != nil {
+		return h.fail(c, "delete spender error", err)
+	}
+
+	mlog.L(c).Info("delete successfully", zap.Int("id", id), zap.String("policy", policy))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// fail maps service errors to their status code.
+func (h handler) fail(c echo.Context, msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return c.JSON(http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrEmailTaken), errors.Is(err, ErrHasTransactions):
+		return c.JSON(http.StatusConflict, err.Error())
+	case errors.Is(err, ErrInvalidName), errors.Is(err, ErrInvalidEmail), errors.Is(err, ErrInvalidPolicy), errors.Is(err, ErrInvalidTimeZone), errors.Is(err, ErrInvalidCurrency):
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	mlog.L(c).Error(msg, zap.Error(err))
+	return c.JSON(http.StatusInternalServerError, err.Error())
+}
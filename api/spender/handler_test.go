@@ -0,0 +1,246 @@
+package spender
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContext(method, body, id string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+	return c, rec
+}
+
+// newSeededService returns a service over the in-memory repository holding
+// spender 1, HongJot.
+func newSeededService(t *testing.T, txns Transactions) Service {
+	s := NewService(NewMemoryRepository(txns))
+	if _, err := s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestCreateSpender(t *testing.T) {
+
+	t.Run("create spender succesfully when feature toggle is enable", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{"name": "HongJot", "email": "hong@jot.ok"}`, "")
+		cfg := config.FeatureFlag{EnableCreateSpender: true}
+
+		h := NewHandler(cfg, NewService(NewMemoryRepository(nil)))
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"id": 1, "name": "HongJot", "email": "hong@jot.ok", "time_zone": "Asia/Bangkok", "base_currency": "THB"}`, rec.Body.String())
+	})
+
+	t.Run("create spender failed when feature toggle is disable", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{"name": "HongJot", "email": "hong@jot.ok"}`, "")
+		cfg := config.FeatureFlag{EnableCreateSpender: false}
+
+		h := NewHandler(cfg, nil)
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("create spender failed when bad request body", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{ bad request body }`, "")
+		cfg := config.FeatureFlag{EnableCreateSpender: true}
+
+		h := NewHandler(cfg, nil)
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "invalid character")
+	})
+
+	t.Run("create spender failed when email is invalid", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{"name": "HongJot", "email": "hong-at-jot"}`, "")
+		cfg := config.FeatureFlag{EnableCreateSpender: true}
+
+		h := NewHandler(cfg, NewService(NewMemoryRepository(nil)))
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), ErrInvalidEmail.Error())
+	})
+
+	t.Run("create spender failed when email is already registered", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{"name": "HongJot", "email": "Hong@Jot.ok"}`, "")
+		cfg := config.FeatureFlag{EnableCreateSpender: true}
+
+		h := NewHandler(cfg, newSeededService(t, nil))
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("create spender failed on database (feature toggle is enable) ", func(t *testing.T) {
+		c, rec := newContext(http.MethodPost, `{"name": "HongJot", "email": "hong@jot.ok"}`, "")
+
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(cStmt).WithArgs("HongJot", "hong@jot.ok", DefaultTimeZone, DefaultBaseCurrency).WillReturnError(assert.AnError)
+		cfg := config.FeatureFlag{EnableCreateSpender: true}
+
+		h := NewHandler(cfg, NewService(NewRepository(db)))
+		err := h.Create(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestGetAllSpender(t *testing.T) {
+	t.Run("get all spender succesfully", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "", "")
+		s := newSeededService(t, nil)
+		_, _ = s.Create(Spender{Name: "JotHong", Email: "jot@jot.ok"})
+
+		h := NewHandler(config.FeatureFlag{}, s)
+		err := h.GetAll(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{"id": 1, "name": "HongJot", "email": "hong@jot.ok", "time_zone": "Asia/Bangkok", "base_currency": "THB"},
+		{"id": 2, "name": "JotHong", "email": "jot@jot.ok", "time_zone": "Asia/Bangkok", "base_currency": "THB"}]`, rec.Body.String())
+	})
+
+	t.Run("get all spender failed on database", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "", "")
+
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT id, name, email, time_zone, base_currency FROM spender`).WillReturnError(assert.AnError)
+
+		h := NewHandler(config.FeatureFlag{}, NewService(NewRepository(db)))
+		err := h.GetAll(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestGetSpender(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{"get spender succesfully", "1", http.StatusOK},
+		{"get spender failed when not found", "9", http.StatusNotFound},
+		{"get spender failed when id is invalid", "abc", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodGet, "", tt.id)
+
+			h := NewHandler(config.FeatureFlag{}, newSeededService(t, nil))
+			err := h.Get(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestUpdateSpender(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		{"update spender succesfully", "1", `{"name": "JotHong", "email": "jot@jot.ok"}`, http.StatusOK},
+		{"update spender failed when name is missing", "1", `{"email": "jot@jot.ok"}`, http.StatusBadRequest},
+		{"update spender failed when time zone is unknown", "1", `{"name": "JotHong", "email": "jot@jot.ok", "time_zone": "Mars/Olympus"}`, http.StatusBadRequest},
+		{"update spender failed when not found", "9", `{"name": "JotHong", "email": "jot@jot.ok"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodPut, tt.body, tt.id)
+
+			h := NewHandler(config.FeatureFlag{}, newSeededService(t, nil))
+			err := h.Update(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestPatchSpender(t *testing.T) {
+	t.Run("patch spender changes only given fields", func(t *testing.T) {
+		c, rec := newContext(http.MethodPatch, `{"email": "jot@jot.ok"}`, "1")
+
+		h := NewHandler(config.FeatureFlag{}, newSeededService(t, nil))
+		err := h.Patch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id": 1, "name": "HongJot", "email": "jot@jot.ok", "time_zone": "Asia/Bangkok", "base_currency": "THB"}`, rec.Body.String())
+	})
+
+	t.Run("patch spender failed when email is invalid", func(t *testing.T) {
+		c, rec := newContext(http.MethodPatch, `{"email": "jot"}`, "1")
+
+		h := NewHandler(config.FeatureFlag{}, newSeededService(t, nil))
+		err := h.Patch(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestDeleteSpender(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		policy         string
+		expectedStatus int
+	}{
+		{"delete spender rejected when it has transactions", "1", "", http.StatusConflict},
+		{"delete spender cascades to transactions", "1", PolicyCascade, http.StatusNoContent},
+		{"delete spender archives transactions", "1", PolicyArchive, http.StatusNoContent},
+		{"delete spender failed when not found", "9", PolicyCascade, http.StatusNotFound},
+		{"delete spender failed when policy is unknown", "1", "orphan", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodDelete, "", tt.id)
+			c.QueryParams().Set("policy", tt.policy)
+			txns := &stubTransactions{counts: map[int]int{1: 2}}
+
+			h := NewHandler(config.FeatureFlag{}, newSeededService(t, txns))
+			err := h.Delete(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
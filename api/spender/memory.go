@@ -0,0 +1,149 @@
+package spender
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Transactions is what the in-memory repository needs from transaction
+// storage to apply a delete policy.
+type Transactions interface {
+	CountBySpender(spenderID int) int
+	RemoveBySpender(spenderID int, archive bool)
+}
+
+type memoryRepository struct {
+	mu           sync.Mutex
+	spenders     map[int64]Spender
+	nextID       int64
+	transactions Transactions
+}
+
+// NewMemoryRepository keeps spenders in process memory. It backs unit tests
+// and the --storage=memory server mode. transactions may be nil, in which
+// case every spender is treated as having none.
+func NewMemoryRepository(transactions Transactions) Repository {
+	return &memoryRepository{
+		spenders:     map[int64]Spender{},
+		nextID:       1,
+		transactions: transactions,
+	}
+}
+
+func (r *memoryRepository) GetAll() ([]Spender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sps []Spender
+	for _, sp := range r.spenders {
+		sps = append(sps, sp)
+	}
+	sort.Slice(sps, func(i, j int) bool { return sps[i].ID < sps[j].ID })
+
+	return sps, nil
+}
+
+func (r *memoryRepository) Get(id int) (Spender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sp, ok := r.spenders[int64(id)]
+	if !ok {
+		return Spender{}, ErrNotFound
+	}
+
+	return sp, nil
+}
+
+func (r *memoryRepository) Create(sp Spender) (Spender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(sp.Email, 0) {
+		return Spender{}, ErrEmailTaken
+	}
+
+	sp.ID = r.nextID
+	r.nextID++
+	r.spenders[sp.ID] = sp
+
+	return sp, nil
+}
+
+func (r *memoryRepository) Update(sp Spender) (Spender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.spenders[sp.ID]; !ok {
+		return Spender{}, ErrNotFound
+	}
+	if r.emailTaken(sp.Email, sp.ID) {
+		return Spender{}, ErrEmailTaken
+	}
+	r.spenders[sp.ID] = sp
+
+	return sp, nil
+}
+
+func (r *memoryRepository) Patch(id int, patch PatchSpender) (Spender, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sp, ok := r.spenders[int64(id)]
+	if !ok {
+		return Spender{}, ErrNotFound
+	}
+	if patch.Name != nil {
+		sp.Name = *patch.Name
+	}
+	if patch.Email != nil {
+		if r.emailTaken(*patch.Email, sp.ID) {
+			return Spender{}, ErrEmailTaken
+		}
+		sp.Email = *patch.Email
+	}
+	if patch.TimeZone != nil {
+		sp.TimeZone = *patch.TimeZone
+	}
+	if patch.BaseCurrency != nil {
+		sp.BaseCurrency = *patch.BaseCurrency
+	}
+	r.spenders[sp.ID] = sp
+
+	return sp, nil
+}
+
+func (r *memoryRepository) Delete(id int, policy string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.spenders[int64(id)]; !ok {
+		return ErrNotFound
+	}
+
+	if r.transactions != nil {
+		switch policy {
+		case PolicyReject:
+			if r.transactions.CountBySpender(id) > 0 {
+				return ErrHasTransactions
+			}
+		case PolicyCascade, PolicyArchive:
+			r.transactions.RemoveBySpender(id, policy == PolicyArchive)
+		}
+	}
+	delete(r.spenders, int64(id))
+
+	return nil
+}
+
+// emailTaken mirrors the case-insensitive unique index, ignoring the spender
+// being updated.
+func (r *memoryRepository) emailTaken(email string, except int64) bool {
+	for _, sp := range r.spenders {
+		if sp.ID != except && strings.EqualFold(sp.Email, email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package spender
+
+import (
+	"database/sql"
+	"errors"
+)
+
+type Repository interface {
+	GetAll() ([]Spender, error)
+	Get(id int) (Spender, error)
+	Create(sp Spender) (Spender, error)
+	Update(sp Spender) (Spender, error)
+	Patch(id int, patch PatchSpender) (Spender, error)
+	Delete(id int, policy string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	cStmt = `INSERT INTO spender (name, email, time_zone, base_currency) VALUES ($1, $2, $3, $4) RETURNING id;`
+	gStmt = `SELECT id, name, email, time_zone, base_currency FROM spender WHERE id = $1;`
+	uStmt = `UPDATE spender SET name = $1, email = $2, time_zone = $3, base_currency = $4 WHERE id = $5 RETURNING id, name, email, time_zone, base_currency;`
+	pStmt = `UPDATE spender SET name = COALESCE($1, name), email = COALESCE($2, email), time_zone = COALESCE($3, time_zone), base_currency = COALESCE($4, base_currency) WHERE id = $5 RETURNING id, name, email, time_zone, base_currency;`
+
+	lockStmt          = `SELECT id FROM spender WHERE id = $1 FOR UPDATE;`
+	countTxnStmt      = `SELECT COUNT(*) FROM transaction WHERE spender_id = $1;`
+	archiveTxnStmt    = `INSERT INTO transaction_archive (id, date, amount, category, category_id, transaction_type, note, image_url, spender_id, account_id, entry, transfer_id, currency, status, recurring_id) SELECT id, date, amount, category, category_id, transaction_type, note, image_url, spender_id, account_id, entry, transfer_id, currency, status, recurring_id FROM transaction WHERE spender_id = $1;`
+	deleteTxnStmt     = `DELETE FROM transaction WHERE spender_id = $1;`
+	deleteSpenderStmt = `DELETE FROM spender WHERE id = $1;`
+)
+
+func (r repository) GetAll() ([]Spender, error) {
+	rows, err := r.db.Query(`SELECT id, name, email, time_zone, base_currency FROM spender`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sps []Spender
+	for rows.Next() {
+		var sp Spender
+		if err := rows.Scan(&sp.ID, &sp.Name, &sp.Email, &sp.TimeZone, &sp.BaseCurrency); err != nil {
+			return nil, err
+		}
+		sps = append(sps, sp)
+	}
+
+	return sps, rows.Err()
+}
+
+func (r repository) Get(id int) (Spender, error) {
+	var sp Spender
+	err := r.db.QueryRow(gStmt, id).Scan(&sp.ID, &sp.Name, &sp.Email, &sp.TimeZone, &sp.BaseCurrency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Spender{}, ErrNotFound
+	}
+	if err != nil {
+		return Spender{}, err
+	}
+
+	return sp, nil
+}
+
+func (r repository) Create(sp Spender) (Spender, error) {
+	err := r.db.QueryRow(cStmt, sp.Name, sp.Email, sp.TimeZone, sp.BaseCurrency).Scan(&sp.ID)
+	if IsEmailTaken(err) {
+		return Spender{}, ErrEmailTaken
+	}
+	if err != nil {
+		return Spender{}, err
+	}
+
+	return sp, nil
+}
+
+func (r repository) Update(sp Spender) (Spender, error) {
+	return r.update(uStmt, sp.Name, sp.Email, sp.TimeZone, sp.BaseCurrency, sp.ID)
+}
+
+func (r repository) Patch(id int, patch PatchSpender) (Spender, error) {
+	return r.update(pStmt, patch.Name, patch.Email, patch.TimeZone, patch.BaseCurrency, id)
+}
+
+func (r repository) update(stmt string, name, email, timeZone, baseCurrency, id interface{}) (Spender, error) {
+	var sp Spender
+	err := r.db.QueryRow(stmt, name, email, timeZone, baseCurrency, id).Scan(&sp.ID, &sp.Name, &sp.Email, &sp.TimeZone, &sp.BaseCurrency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Spender{}, ErrNotFound
+	}
+	if IsEmailTaken(err) {
+		return Spender{}, ErrEmailTaken
+	}
+	if err != nil {
+		return Spender{}, err
+	}
+
+	return sp, nil
+}
+
+// Delete removes the spender together with its accounts and API keys, and
+// applies policy to its transactions in the same database transaction.
+func (r repository) Delete(id int, policy string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locking the spender row keeps the reject check and the delete atomic
+	// with respect to other deletes of the same spender.
+	if err := tx.QueryRow(lockStmt, id).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	switch policy {
+	case PolicyReject:
+		var count int
+		if err := tx.QueryRow(countTxnStmt, id).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrHasTransactions
+		}
+	case PolicyArchive:
+		if _, err := tx.Exec(archiveTxnStmt, id); err != nil {
+			return err
+		}
+		fallthrough
+	case PolicyCascade:
+		if _, err := tx.Exec(deleteTxnStmt, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(deleteSpenderStmt, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,217 @@
+package spender
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryCreate(t *testing.T) {
+	t.Run("create spender succesfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		row := sqlmock.NewRows([]string{"id"}).AddRow(1)
+		mock.ExpectQuery(cStmt).WithArgs("HongJot", "hong@jot.ok", "Asia/Bangkok", "THB").WillReturnRows(row)
+
+		sp, err := NewRepository(db).Create(Spender{Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"}, sp)
+	})
+
+	t.Run("create spender failed when email is already registered", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(cStmt).WithArgs("HongJot", "Hong@Jot.ok", "Asia/Bangkok", "THB").WillReturnError(&pq.Error{Code: "23505", Constraint: emailIndex})
+
+		_, err := NewRepository(db).Create(Spender{Name: "HongJot", Email: "Hong@Jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"})
+
+		assert.Equal(t, ErrEmailTaken, err)
+	})
+
+	t.Run("create spender failed on database", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(cStmt).WithArgs("HongJot", "hong@jot.ok", "Asia/Bangkok", "THB").WillReturnError(assert.AnError)
+
+		_, err := NewRepository(db).Create(Spender{Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"})
+
+		assert.Equal(t, assert.AnError, err)
+	})
+}
+
+func TestRepositoryGetAll(t *testing.T) {
+	t.Run("get all spender succesfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "time_zone", "base_currency"}).
+			AddRow(1, "HongJot", "hong@jot.ok", "Asia/Bangkok", "THB").
+			AddRow(2, "JotHong", "jot@jot.ok", "Europe/London", "THB")
+		mock.ExpectQuery(`SELECT id, name, email, time_zone, base_currency FROM spender`).WillReturnRows(rows)
+
+		sps, err := NewRepository(db).GetAll()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Spender{
+			{ID: 1, Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"},
+			{ID: 2, Name: "JotHong", Email: "jot@jot.ok", TimeZone: "Europe/London", BaseCurrency: "THB"},
+		}, sps)
+	})
+
+	t.Run("get all spender failed on database", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT id, name, email, time_zone, base_currency FROM spender`).WillReturnError(assert.AnError)
+
+		_, err := NewRepository(db).GetAll()
+
+		assert.Equal(t, assert.AnError, err)
+	})
+}
+
+func TestRepositoryGet(t *testing.T) {
+	t.Run("get spender succesfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "time_zone", "base_currency"}).AddRow(1, "HongJot", "hong@jot.ok", "Asia/Bangkok", "THB")
+		mock.ExpectQuery(gStmt).WithArgs(1).WillReturnRows(rows)
+
+		sp, err := NewRepository(db).Get(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"}, sp)
+	})
+
+	t.Run("get spender failed when not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(gStmt).WithArgs(9).WillReturnError(sql.ErrNoRows)
+
+		_, err := NewRepository(db).Get(9)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRepositoryUpdate(t *testing.T) {
+	t.Run("update spender succesfully", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "time_zone", "base_currency"}).AddRow(1, "JotHong", "jot@jot.ok", "Asia/Tokyo", "THB")
+		mock.ExpectQuery(uStmt).WithArgs("JotHong", "jot@jot.ok", "Asia/Tokyo", "THB", 1).WillReturnRows(rows)
+
+		sp, err := NewRepository(db).Update(Spender{ID: 1, Name: "JotHong", Email: "jot@jot.ok", TimeZone: "Asia/Tokyo", BaseCurrency: "THB"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "JotHong", Email: "jot@jot.ok", TimeZone: "Asia/Tokyo", BaseCurrency: "THB"}, sp)
+	})
+
+	t.Run("update spender failed when email is already registered", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(uStmt).WillReturnError(&pq.Error{Code: "23505", Constraint: emailIndex})
+
+		_, err := NewRepository(db).Update(Spender{ID: 1, Name: "JotHong", Email: "jot@jot.ok"})
+
+		assert.Equal(t, ErrEmailTaken, err)
+	})
+}
+
+func TestRepositoryPatch(t *testing.T) {
+	t.Run("patch spender keeps fields that are not given", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "time_zone", "base_currency"}).AddRow(1, "HongJot", "jot@jot.ok", "Asia/Bangkok", "THB")
+		mock.ExpectQuery(pStmt).WithArgs(nil, "jot@jot.ok", nil, nil, 1).WillReturnRows(rows)
+		email := "jot@jot.ok"
+
+		sp, err := NewRepository(db).Patch(1, PatchSpender{Email: &email})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "HongJot", Email: "jot@jot.ok", TimeZone: "Asia/Bangkok", BaseCurrency: "THB"}, sp)
+	})
+
+	t.Run("patch spender failed when not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+
+		mock.ExpectQuery(pStmt).WithArgs("JotHong", nil, nil, nil, 9).WillReturnError(sql.ErrNoRows)
+		name := "JotHong"
+
+		_, err := NewRepository(db).Patch(9, PatchSpender{Name: &name})
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRepositoryDelete(t *testing.T) {
+	t.Run("delete spender rejected when it has transactions", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStmt).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(countTxnStmt).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		err := NewRepository(db).Delete(1, PolicyReject)
+
+		assert.Equal(t, ErrHasTransactions, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete spender cascades to transactions", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStmt).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(deleteTxnStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteSpenderStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := NewRepository(db).Delete(1, PolicyCascade)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete spender archives transactions", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStmt).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(archiveTxnStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteTxnStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(deleteSpenderStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := NewRepository(db).Delete(1, PolicyArchive)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete spender failed when not found", func(t *testing.T) {
+		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockStmt).WithArgs(9).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := NewRepository(db).Delete(9, PolicyReject)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
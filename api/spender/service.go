@@ -0,0 +1,123 @@
+package spender
+
+import (
+	"strings"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+)
+
+type service struct {
+	repository Repository
+}
+
+type Service interface {
+	GetAll() ([]Spender, error)
+	Get(id int) (Spender, error)
+	Create(sp Spender) (Spender, error)
+	Update(sp Spender) (Spender, error)
+	Patch(id int, patch PatchSpender) (Spender, error)
+	Delete(id int, policy string) error
+}
+
+func NewService(repository Repository) Service {
+	return service{repository: repository}
+}
+
+func (s service) GetAll() ([]Spender, error) {
+	return s.repository.GetAll()
+}
+
+func (s service) Get(id int) (Spender, error) {
+	return s.repository.Get(id)
+}
+
+func (s service) Create(sp Spender) (Spender, error) {
+	if err := validate(&sp); err != nil {
+		return Spender{}, err
+	}
+
+	return s.repository.Create(sp)
+}
+
+// Update replaces name, email, time zone and base currency.
+func (s service) Update(sp Spender) (Spender, error) {
+	if err := validate(&sp); err != nil {
+		return Spender{}, err
+	}
+
+	return s.repository.Update(sp)
+}
+
+// Patch changes only the fields present in patch.
+func (s service) Patch(id int, patch PatchSpender) (Spender, error) {
+	if patch.Name != nil {
+		name := strings.TrimSpace(*patch.Name)
+		if name == "" {
+			return Spender{}, ErrInvalidName
+		}
+		patch.Name = &name
+	}
+	if patch.Email != nil {
+		email := strings.TrimSpace(*patch.Email)
+		if !ValidEmail(email) {
+			return Spender{}, ErrInvalidEmail
+		}
+		patch.Email = &email
+	}
+	if patch.TimeZone != nil {
+		tz := strings.TrimSpace(*patch.TimeZone)
+		if !ValidTimeZone(tz) {
+			return Spender{}, ErrInvalidTimeZone
+		}
+		patch.TimeZone = &tz
+	}
+	if patch.BaseCurrency != nil {
+		code := currency.Normalize(*patch.BaseCurrency)
+		if !currency.Valid(code) {
+			return Spender{}, ErrInvalidCurrency
+		}
+		patch.BaseCurrency = &code
+	}
+
+	return s.repository.Patch(id, patch)
+}
+
+// Delete removes the spender. What happens to its transactions is chosen by
+// policy: reject (the default) refuses while any exist, cascade deletes them
+// and archive moves them to transaction_archive first.
+func (s service) Delete(id int, policy string) error {
+	if policy == "" {
+		policy = PolicyReject
+	}
+	if policy != PolicyReject && policy != PolicyCascade && policy != PolicyArchive {
+		return ErrInvalidPolicy
+	}
+
+	return s.repository.Delete(id, policy)
+}
+
+func validate(sp *Spender) error {
+	sp.Name = strings.TrimSpace(sp.Name)
+	sp.Email = strings.TrimSpace(sp.Email)
+	if sp.Name == "" {
+		return ErrInvalidName
+	}
+	if !ValidEmail(sp.Email) {
+		return ErrInvalidEmail
+	}
+	sp.TimeZone = strings.TrimSpace(sp.TimeZone)
+	if sp.TimeZone == "" {
+		sp.TimeZone = DefaultTimeZone
+	}
+	if !ValidTimeZone(sp.TimeZone) {
+		return ErrInvalidTimeZone
+	}
+	sp.BaseCurrency = currency.Normalize(sp.BaseCurrency)
+	if sp.BaseCurrency == "" {
+		sp.BaseCurrency = DefaultBaseCurrency
+	}
+	if !currency.Valid(sp.BaseCurrency) {
+		return ErrInvalidCurrency
+	}
+	return nil
+}
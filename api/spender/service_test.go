@@ -0,0 +1,200 @@
+package spender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTransactions struct {
+	counts   map[int]int
+	removed  []int
+	archived []int
+}
+
+func (s *stubTransactions) CountBySpender(spenderID int) int {
+	return s.counts[spenderID]
+}
+
+func (s *stubTransactions) RemoveBySpender(spenderID int, archive bool) {
+	s.removed = append(s.removed, spenderID)
+	if archive {
+		s.archived = append(s.archived, spenderID)
+	}
+}
+
+func TestServiceCreate(t *testing.T) {
+	t.Run("create spender trims and stores", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+
+		sp, err := s.Create(Spender{Name: " HongJot ", Email: " hong@jot.ok "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "HongJot", Email: "hong@jot.ok", TimeZone: DefaultTimeZone, BaseCurrency: DefaultBaseCurrency}, sp)
+	})
+
+	t.Run("create spender validates input", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			spender  Spender
+			expected error
+		}{
+			{"missing name", Spender{Email: "hong@jot.ok"}, ErrInvalidName},
+			{"invalid email", Spender{Name: "HongJot", Email: "hong-at-jot"}, ErrInvalidEmail},
+			{"unknown time zone", Spender{Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Asia/Atlantis"}, ErrInvalidTimeZone},
+			{"server time zone", Spender{Name: "HongJot", Email: "hong@jot.ok", TimeZone: "Local"}, ErrInvalidTimeZone},
+			{"invalid base currency", Spender{Name: "HongJot", Email: "hong@jot.ok", BaseCurrency: "baht"}, ErrInvalidCurrency},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := NewService(NewMemoryRepository(nil))
+
+				_, err := s.Create(tt.spender)
+
+				assert.Equal(t, tt.expected, err)
+			})
+		}
+	})
+
+	t.Run("create spender rejects email that differs only in case", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+
+		_, err := s.Create(Spender{Name: "HongJot", Email: "Hong@Jot.OK"})
+
+		assert.Equal(t, ErrEmailTaken, err)
+	})
+}
+
+func TestServiceUpdate(t *testing.T) {
+	t.Run("update spender replaces fields", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+
+		sp, err := s.Update(Spender{ID: 1, Name: "JotHong", Email: "jot@jot.ok", TimeZone: "Europe/London"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "JotHong", Email: "jot@jot.ok", TimeZone: "Europe/London", BaseCurrency: DefaultBaseCurrency}, sp)
+	})
+
+	t.Run("update spender keeps its own email", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+
+		_, err := s.Update(Spender{ID: 1, Name: "JotHong", Email: "HONG@jot.ok"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("update spender failed when not found", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+
+		_, err := s.Update(Spender{ID: 9, Name: "JotHong", Email: "jot@jot.ok"})
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestServicePatch(t *testing.T) {
+	t.Run("patch spender changes only given fields", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+		email := "jot@jot.ok"
+
+		sp, err := s.Patch(1, PatchSpender{Email: &email})
+
+		assert.NoError(t, err)
+		assert.Equal(t, Spender{ID: 1, Name: "HongJot", Email: "jot@jot.ok", TimeZone: DefaultTimeZone, BaseCurrency: DefaultBaseCurrency}, sp)
+	})
+
+	t.Run("patch spender changes time zone", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+		tz := " Asia/Tokyo "
+
+		sp, err := s.Patch(1, PatchSpender{TimeZone: &tz})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", sp.TimeZone)
+	})
+
+	t.Run("patch spender changes base currency", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+		code := " usd "
+
+		sp, err := s.Patch(1, PatchSpender{BaseCurrency: &code})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "USD", sp.BaseCurrency)
+	})
+
+	t.Run("patch spender validates given fields", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+		blank := " "
+
+		_, err := s.Patch(1, PatchSpender{Name: &blank})
+
+		assert.Equal(t, ErrInvalidName, err)
+
+		_, err = s.Patch(1, PatchSpender{TimeZone: &blank})
+
+		assert.Equal(t, ErrInvalidTimeZone, err)
+
+		_, err = s.Patch(1, PatchSpender{BaseCurrency: &blank})
+
+		assert.Equal(t, ErrInvalidCurrency, err)
+	})
+
+	t.Run("patch spender failed when email belongs to another spender", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+		_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+		_, _ = s.Create(Spender{Name: "JotHong", Email: "jot@jot.ok"})
+		email := "hong@jot.ok"
+
+		_, err := s.Patch(2, PatchSpender{Email: &email})
+
+		assert.Equal(t, ErrEmailTaken, err)
+	})
+}
+
+func TestServiceDelete(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           string
+		counts           map[int]int
+		expected         error
+		expectedRemoved  []int
+		expectedArchived []int
+	}{
+		{"reject by default without transactions", "", nil, nil, nil, nil},
+		{"reject by default with transactions", "", map[int]int{1: 2}, ErrHasTransactions, nil, nil},
+		{"cascade", PolicyCascade, map[int]int{1: 2}, nil, []int{1}, nil},
+		{"archive", PolicyArchive, map[int]int{1: 2}, nil, []int{1}, []int{1}},
+		{"unknown policy", "orphan", nil, ErrInvalidPolicy, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txns := &stubTransactions{counts: tt.counts}
+			s := NewService(NewMemoryRepository(txns))
+			_, _ = s.Create(Spender{Name: "HongJot", Email: "hong@jot.ok"})
+
+			err := s.Delete(1, tt.policy)
+
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.expectedRemoved, txns.removed)
+			assert.Equal(t, tt.expectedArchived, txns.archived)
+		})
+	}
+
+	t.Run("delete spender failed when not found", func(t *testing.T) {
+		s := NewService(NewMemoryRepository(nil))
+
+		err := s.Delete(9, PolicyCascade)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
@@ -1,81 +1,61 @@
 package spender
 
 import (
-	"database/sql"
-	"net/http"
+	"errors"
+	"time"
 
-	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
-	"github.com/kkgo-software-engineering/workshop/mlog"
-	"github.com/labstack/echo/v4"
-	"go.uber.org/zap"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+
+	// Embedded so time zones resolve on hosts without a zoneinfo database.
+	_ "time/tzdata"
 )
 
-type Spender struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-}
+// DefaultTimeZone is the time zone of a spender that did not choose one.
+// Reports bucket transactions by the spender's local calendar.
+const DefaultTimeZone = "Asia/Bangkok"
 
-type handler struct {
-	flag config.FeatureFlag
-	db   *sql.DB
+// DefaultBaseCurrency is the base currency of a spender that did not choose
+// one. Reports convert every transaction to the spender's base currency.
+const DefaultBaseCurrency = currency.Default
+
+type Spender struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	TimeZone     string `json:"time_zone"`
+	BaseCurrency string `json:"base_currency"`
 }
 
-func New(cfg config.FeatureFlag, db *sql.DB) *handler {
-	return &handler{cfg, db}
+// PatchSpender holds the fields of a partial update; nil means unchanged.
+type PatchSpender struct {
+	Name         *string `json:"name"`
+	Email        *string `json:"email"`
+	TimeZone     *string `json:"time_zone"`
+	BaseCurrency *string `json:"base_currency"`
 }
 
+// Delete policies decide what happens to a spender's transactions.
 const (
-	cStmt = `INSERT INTO spender (name, email) VALUES ($1, $2) RETURNING id;`
+	PolicyReject  = "reject"
+	PolicyCascade = "cascade"
+	PolicyArchive = "archive"
 )
 
-func (h handler) Create(c echo.Context) error {
-	if !h.flag.EnableCreateSpender {
-		return c.JSON(http.StatusForbidden, "create new spender feature is disabled")
-	}
-
-	logger := mlog.L(c)
-	ctx := c.Request().Context()
-	var sp Spender
-	err := c.Bind(&sp)
-	if err != nil {
-		logger.Error("bad request body", zap.Error(err))
-		return c.JSON(http.StatusBadRequest, err.Error())
-	}
-
-	var lastInsertId int64
-	err = h.db.QueryRowContext(ctx, cStmt, sp.Name, sp.Email).Scan(&lastInsertId)
-	if err != nil {
-		logger.Error("query row error", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, err.Error())
-	}
-
-	logger.Info("create successfully", zap.Int64("id", lastInsertId))
-	sp.ID = lastInsertId
-	return c.JSON(http.StatusCreated, sp)
-}
-
-func (h handler) GetAll(c echo.Context) error {
-	logger := mlog.L(c)
-	ctx := c.Request().Context()
-
-	rows, err := h.db.QueryContext(ctx, `SELECT id, name, email FROM spender`)
-	if err != nil {
-		logger.Error("query error", zap.Error(err))
-		return c.JSON(http.StatusInternalServerError, err.Error())
-	}
-	defer rows.Close()
+var (
+	ErrNotFound        = errors.New("spender not found")
+	ErrInvalidName     = errors.New("name is required")
+	ErrInvalidPolicy   = errors.New("policy must be one of reject, cascade or archive")
+	ErrInvalidTimeZone = errors.New("time_zone must be an IANA time zone such as Asia/Bangkok")
+	ErrInvalidCurrency = errors.New("base_currency must be a three-letter ISO 4217 code")
+	ErrHasTransactions = errors.New("spender still has transactions, delete with policy=cascade or policy=archive")
+)
 
-	var sps []Spender
-	for rows.Next() {
-		var sp Spender
-		err := rows.Scan(&sp.ID, &sp.Name, &sp.Email)
-		if err != nil {
-			logger.Error("scan error", zap.Error(err))
-			return c.JSON(http.StatusInternalServerError, err.Error())
-		}
-		sps = append(sps, sp)
+// ValidTimeZone accepts IANA names such as "Asia/Bangkok" or "UTC". "Local"
+// is rejected since it depends on the server's settings.
+func ValidTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
 	}
-
-	return c.JSON(http.StatusOK, sps)
+	_, err := time.LoadLocation(name)
+	return err == nil
 }
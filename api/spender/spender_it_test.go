@@ -25,7 +25,7 @@ func TestCreateSpenderIT(t *testing.T) {
 		migration.ApplyMigrations(sql)
 		defer migration.RollbackMigrations(sql)
 
-		h := New(config.FeatureFlag{EnableCreateSpender: true}, sql)
+		h := NewHandler(config.FeatureFlag{EnableCreateSpender: true}, NewService(NewRepository(sql)))
 		e := echo.New()
 		defer e.Close()
 
@@ -52,7 +52,7 @@ func TestGetAllSpenderIT(t *testing.T) {
 		migration.ApplyMigrations(sql)
 		defer migration.RollbackMigrations(sql)
 
-		h := New(config.FeatureFlag{}, sql)
+		h := NewHandler(config.FeatureFlag{}, NewService(NewRepository(sql)))
 		e := echo.New()
 		defer e.Close()
 
@@ -69,7 +69,7 @@ func TestGetAllSpenderIT(t *testing.T) {
 }
 
 func getTestDatabaseFromConfig() (*sql.DB, error) {
-	cfg := config.Parse("DOCKER")
+	cfg := config.Parse("DOCKER", config.StoragePostgres)
 	sql, err := sql.Open("postgres", cfg.PostgresURI())
 	if err != nil {
 		return nil, err
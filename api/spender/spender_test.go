@@ -1,145 +0,0 @@
-package spender
-
-import (
-	"net/http"
-	"net/http/httptest"
-	"strings"
-	"testing"
-
-	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
-	"github.com/labstack/echo/v4"
-	"github.com/stretchr/testify/assert"
-)
-
-func TestCreateSpender(t *testing.T) {
-
-	t.Run("create spender succesfully when feature toggle is enable", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "HongJot", "email": "hong@jot.ok"}`))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-		defer db.Close()
-
-		row := sqlmock.NewRows([]string{"id"}).AddRow(1)
-		mock.ExpectQuery(cStmt).WithArgs("HongJot", "hong@jot.ok").WillReturnRows(row)
-		cfg := config.FeatureFlag{EnableCreateSpender: true}
-
-		h := New(cfg, db)
-		err := h.Create(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusCreated, rec.Code)
-		assert.JSONEq(t, `{"id": 1, "name": "HongJot", "email": "hong@jot.ok"}`, rec.Body.String())
-	})
-
-	t.Run("create spender failed when feature toggle is disable", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "HongJot", "email": "hong@jot.ok"}`))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		cfg := config.FeatureFlag{EnableCreateSpender: false}
-
-		h := New(cfg, nil)
-		err := h.Create(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusForbidden, rec.Code)
-	})
-
-	t.Run("create spender failed when bad request body", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{ bad request body }`))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-		cfg := config.FeatureFlag{EnableCreateSpender: true}
-
-		h := New(cfg, nil)
-		err := h.Create(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), "invalid character")
-	})
-
-	t.Run("create spender failed on database (feature toggle is enable) ", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "HongJot", "email": "hong@jot.ok"}`))
-		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-		defer db.Close()
-
-		mock.ExpectQuery(cStmt).WithArgs("HongJot", "hong@jot.ok").WillReturnError(assert.AnError)
-		cfg := config.FeatureFlag{EnableCreateSpender: true}
-
-		h := New(cfg, db)
-		err := h.Create(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	})
-}
-
-func TestGetAllSpender(t *testing.T) {
-	t.Run("get all spender succesfully", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-		defer db.Close()
-
-		rows := sqlmock.NewRows([]string{"id", "name", "email"}).
-			AddRow(1, "HongJot", "hong@jot.ok").
-			AddRow(2, "JotHong", "jot@jot.ok")
-		mock.ExpectQuery(`SELECT id, name, email FROM spender`).WillReturnRows(rows)
-
-		h := New(config.FeatureFlag{}, db)
-		err := h.GetAll(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusOK, rec.Code)
-		assert.JSONEq(t, `[{"id": 1, "name": "HongJot", "email": "hong@jot.ok"},
-		{"id": 2, "name": "JotHong", "email": "jot@jot.ok"}]`, rec.Body.String())
-	})
-
-	t.Run("get all spender failed on database", func(t *testing.T) {
-		e := echo.New()
-		defer e.Close()
-
-		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		db, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-		defer db.Close()
-
-		mock.ExpectQuery(`SELECT id, name, email FROM spender`).WillReturnError(assert.AnError)
-
-		h := New(config.FeatureFlag{}, db)
-		err := h.GetAll(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-	})
-}
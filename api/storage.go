@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/account"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/apikey"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/budget"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/eslip"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/health"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/job"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/recurring"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/session"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/transaction"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/user"
+)
+
+// Storage holds the repositories the API is served from, so the same routes
+// can run against Postgres or entirely in memory.
+type Storage struct {
+	DB           health.Pinger
+	Spenders     spender.Repository
+	Transactions transaction.Repository
+	Categories   category.Repository
+	Accounts     account.Repository
+	Rates        currency.Repository
+	Budgets      budget.Repository
+	Recurring    recurring.Repository
+	Users        user.Repository
+	Sessions     session.Repository
+	APIKeys      apikey.Repository
+	Slips        eslip.Repository
+	Jobs         job.Queue
+}
+
+func NewPostgresStorage(db *sql.DB) Storage {
+	return Storage{
+		DB:           db,
+		Spenders:     spender.NewRepository(db),
+		Transactions: transaction.NewRepository(db),
+		Categories:   category.NewRepository(db),
+		Accounts:     account.NewRepository(db),
+		Rates:        currency.NewRepository(db),
+		Budgets:      budget.NewRepository(db),
+		Recurring:    recurring.NewRepository(db),
+		Users:        user.NewRepository(db),
+		Sessions:     session.NewRepository(db),
+		APIKeys:      apikey.NewRepository(db),
+		Slips:        eslip.NewRepository(db),
+		Jobs:         job.NewQueue(db),
+	}
+}
+
+// NewMemoryStorage keeps everything in process memory. Data is lost on
+// restart; it is meant for running the API locally without Postgres.
+func NewMemoryStorage() Storage {
+	transactions := transaction.NewMemoryRepository()
+	spenders := spender.NewMemoryRepository(transactions)
+	categories := category.NewMemoryRepository()
+	accounts := account.NewMemoryRepository(transactions)
+	rates := currency.NewMemoryRepository()
+	sessions := session.NewMemoryRepository()
+	transactions.UseTimeZones(spenderSettings{spenders: spenders})
+	transactions.UseCategories(categories)
+	transactions.UseAccounts(accounts)
+	transactions.UseCurrencies(spenderSettings{spenders: spenders}, rates)
+
+	return Storage{
+		DB:           memoryDB{},
+		Spenders:     spenders,
+		Transactions: transactions,
+		Categories:   categories,
+		Accounts:     accounts,
+		Rates:        rates,
+		Budgets:      budget.NewMemoryRepository(),
+		Recurring:    recurring.NewMemoryRepository(transactions),
+		Users:        user.NewMemoryRepository(spenders, sessions),
+		Sessions:     sessions,
+		APIKeys:      apikey.NewMemoryRepository(),
+		Slips:        eslip.NewMemoryRepository(transactions),
+		Jobs:         job.NewMemoryQueue(),
+	}
+}
+
+// spenderSettings gives the in-memory transactions the time zone and base
+// currency of their spender, as the SQL repository joins them from the
+// spender table. Budgets and recurring transactions use the time zone with
+// either storage.
+type spenderSettings struct {
+	spenders spender.Repository
+}
+
+func (s spenderSettings) TimeZone(spenderID int) string {
+	sp, err := s.spenders.Get(spenderID)
+	if err != nil || sp.TimeZone == "" {
+		return spender.DefaultTimeZone
+	}
+	return sp.TimeZone
+}
+
+func (s spenderSettings) BaseCurrency(spenderID int) string {
+	sp, err := s.spenders.Get(spenderID)
+	if err != nil || sp.BaseCurrency == "" {
+		return spender.DefaultBaseCurrency
+	}
+	return sp.BaseCurrency
+}
+
+type memoryDB struct{}
+
+func (memoryDB) Ping() error { return nil }
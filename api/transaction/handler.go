@@ -1,9 +1,13 @@
 package transaction
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
 	"github.com/labstack/echo/v4"
 )
@@ -15,11 +19,15 @@ type handler struct {
 type Handler interface {
 	GetAll(c echo.Context) error
 	Create(c echo.Context) error
+	CreateTransfer(c echo.Context) error
 	GetExpenses(c echo.Context) error
 	GetSummary(c echo.Context) error
 	GetBalance(c echo.Context) error
+	GetCategoryReport(c echo.Context) error
 	UpdateExpense(c echo.Context) error
 	DeleteExpense(c echo.Context) error
+	Confirm(c echo.Context) error
+	Reject(c echo.Context) error
 }
 
 func NewHandler(service Service) Handler {
@@ -29,17 +37,38 @@ func NewHandler(service Service) Handler {
 }
 
 func (h handler) GetAll(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
 	filter, ok := c.Get("filter").(Filter)
 	if !ok {
 		filter = Filter{}
 	}
 
+	if filter.Status != "" && !ValidStatus(filter.Status) {
+		return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidStatus))
+	}
+
 	pagination, ok := c.Get("pagination").(Pagination)
 	if !ok {
 		pagination = Pagination{}
 	}
 
-	result, err := h.service.GetAll(filter, pagination)
+	spenderId := caller.SpenderID
+	if caller.Can(auth.PermTransactionsReadAny) {
+		spenderId = AnySpender
+		if param := c.QueryParam("spender_id"); param != "" {
+			id, err := strconv.Atoi(param)
+			if err != nil || id <= 0 {
+				return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid spender ID"})
+			}
+			spenderId = id
+		}
+	}
+
+	result, err := h.service.GetAll(spenderId, filter, pagination)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, err.Error())
 	}
@@ -48,61 +77,204 @@ func (h handler) GetAll(c echo.Context) error {
 }
 
 func (h handler) Create(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
 	request := CreateTransactionRequest{}
 	if err := c.Bind(&request); err != nil {
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
+	request.SpenderId = caller.SpenderID
 
 	result, err := h.service.Create(request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		if errors.Is(err, ErrInvalidCategory) || errors.Is(err, ErrInvalidAccount) || errors.Is(err, ErrInvalidTxnType) || errors.Is(err, ErrInvalidAmount) ||
+			errors.Is(err, currency.ErrInvalidCurrency) {
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
 	}
 
 	return c.JSON(http.StatusOK, result)
 }
 
-func (h handler) GetExpenses(c echo.Context) error {
-	return nil
+// CreateTransfer moves money between two of the caller's accounts.
+func (h handler) CreateTransfer(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	var request TransferRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	request.SpenderId = caller.SpenderID
+
+	result, err := h.service.CreateTransfer(request)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTransfer), errors.Is(err, ErrInvalidAccount), errors.Is(err, ErrCurrencyMismatch):
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusCreated, result)
 }
 
-func (h handler) GetSummary(c echo.Context) error {
-	spenderId, err := strconv.Atoi(c.QueryParam("spender_id"))
+// GetExpenses lists the caller's expenses with their slip image link,
+// using the filter, pagination and sort set by the middleware.
+func (h handler) GetExpenses(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	filter, _ := c.Get("filter").(Filter)
+	if filter.Status != "" && !ValidStatus(filter.Status) {
+		return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidStatus))
+	}
+	pagination, ok := c.Get("pagination").(Pagination)
+	if !ok {
+		pagination = Pagination{ItemPerPage: 5, Page: 1}
+	}
+	sort, ok := c.Get("sort").(Sort)
+	if !ok {
+		sort = Sort{By: SortByDate, Order: OrderDesc}
+	}
 
+	expenses, err := h.service.GetExpenses(caller.SpenderID, filter, pagination, sort)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errs.Build(err))
+		if errors.Is(err, ErrInvalidSort) {
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
 	}
 
-	txnType := c.QueryParam("txn_type")
+	return c.JSON(http.StatusOK, expenses)
+}
+
+func (h handler) GetSummary(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
 
-	summary, err := h.service.GetSummary(spenderId, txnType)
+	query := SummaryQuery{
+		TxnType: c.QueryParam("txn_type"),
+		Average: c.QueryParam("average"),
+	}
+	var err error
+	if query.From, err = queryDate(c, "from"); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	if query.To, err = queryDate(c, "to"); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
 
+	summary, err := h.service.GetSummary(caller.SpenderID, query)
 	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTxnType), errors.Is(err, ErrInvalidAverage), errors.Is(err, ErrInvalidRange):
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		case errors.Is(err, currency.ErrMissingRate):
+			return c.JSON(http.StatusUnprocessableEntity, errs.Build(err))
+		}
 		return c.JSON(http.StatusInternalServerError, errs.Build(err))
 	}
 
 	return c.JSON(http.StatusOK, summary)
 }
 
+// GetBalance reports earned, spent and saved per day, week, month or year
+// of the spender's time zone, with the running balance.
 func (h handler) GetBalance(c echo.Context) error {
-	spenderId := c.QueryParam("spender_id")
-	if spenderId == "" {
-		c.JSON(http.StatusBadRequest, "spender_id is required")
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
 	}
 
-	id, err := strconv.Atoi(spenderId)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, "can't convert spender_id to number")
+	query := BalanceQuery{Granularity: c.QueryParam("granularity")}
+	var err error
+	if query.From, err = queryDate(c, "from"); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	if query.To, err = queryDate(c, "to"); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
 	}
 
-	result, err := h.service.GetBalance(id)
+	result, err := h.service.GetBalance(caller.SpenderID, query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, err)
+		if errors.Is(err, ErrInvalidGranularity) || errors.Is(err, ErrInvalidRange) {
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		}
+		if errors.Is(err, currency.ErrMissingRate) {
+			return c.JSON(http.StatusUnprocessableEntity, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
 	}
 
 	return c.JSON(http.StatusOK, result)
 }
 
+// GetCategoryReport covers from to to, by default the current month up to
+// today. The category and amount filters narrow it; top sets how many notes
+// are listed per transaction type.
+func (h handler) GetCategoryReport(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	from, err := queryDate(c, "from")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	to, err := queryDate(c, "to")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+	if to == nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to = &today
+	}
+	if from == nil {
+		first := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
+		from = &first
+	}
+
+	query := CategoryQuery{From: *from, To: *to}
+	query.Filter, _ = c.Get("filter").(Filter)
+	if param := c.QueryParam("top"); param != "" {
+		if query.Top, err = strconv.Atoi(param); err != nil || query.Top < 1 {
+			return c.JSON(http.StatusBadRequest, errs.Build(ErrInvalidTop))
+		}
+	}
+
+	report, err := h.service.GetCategoryReport(caller.SpenderID, query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTop) || errors.Is(err, ErrInvalidRange) {
+			return c.JSON(http.StatusBadRequest, errs.Build(err))
+		}
+		if errors.Is(err, currency.ErrMissingRate) {
+			return c.JSON(http.StatusUnprocessableEntity, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
 func (h handler) UpdateExpense(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
@@ -114,9 +286,15 @@ func (h handler) UpdateExpense(c echo.Context) error {
 	}
 
 	transaction.ID = id
-	h.service.UpdateExpense(transaction)
+	transaction.SpenderId = caller.SpenderID
 
-	if err := h.service.UpdateExpense(transaction); err != nil {
+	if err := h.service.UpdateExpense(caller.SpenderID, transaction); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		if errors.Is(err, ErrInvalidCategory) || errors.Is(err, ErrInvalidAccount) || errors.Is(err, ErrInvalidAmount) || errors.Is(err, currency.ErrInvalidCurrency) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 	}
 
@@ -124,21 +302,70 @@ func (h handler) UpdateExpense(c echo.Context) error {
 }
 
 func (h handler) DeleteExpense(c echo.Context) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid expense ID"})
 	}
-	var transaction Transaction
-	if err := c.Bind(&transaction); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"message": err.Error()})
-	}
-
-	transaction.ID = id
 
-	if err := h.service.DeleteExpense(id); err != nil {
+	if err := h.service.DeleteExpense(caller.SpenderID, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Expense delete successfully"})
 }
+
+func (h handler) Confirm(c echo.Context) error {
+	return h.review(c, h.service.Confirm, "Transaction confirmed successfully")
+}
+
+func (h handler) Reject(c echo.Context) error {
+	return h.review(c, h.service.Reject, "Transaction rejected successfully")
+}
+
+func (h handler) review(c echo.Context, review func(spenderId int, id int) error, message string) error {
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid transaction ID"})
+	}
+
+	if err := review(caller.SpenderID, id); err != nil {
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return c.JSON(http.StatusNotFound, errs.Build(err))
+		case errors.Is(err, ErrAlreadyReviewed):
+			return c.JSON(http.StatusConflict, errs.Build(err))
+		}
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": message})
+}
+
+// queryDate reads an optional YYYY-MM-DD query parameter in local time,
+// like the list filters.
+func queryDate(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", value, time.Now().Location())
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	return &date, nil
+}
@@ -2,11 +2,16 @@ package transaction
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
 	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -18,8 +23,8 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) GetAll(filter Filter, paginate Pagination) ([]Transaction, error) {
-	args := m.Called(filter, paginate)
+func (m *MockService) GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error) {
+	args := m.Called(spenderId, filter, paginate)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -29,31 +34,60 @@ func (m *MockService) GetAll(filter Filter, paginate Pagination) ([]Transaction,
 func (m *MockService) Create(request CreateTransactionRequest) (CreateTransactionResponse, error) {
 	return CreateTransactionResponse{}, nil
 }
-func (m *MockService) GetExpenses(spenderId int) ([]Transaction, error) {
-	return nil, nil
+func (m *MockService) CreateTransfer(request TransferRequest) (TransferResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(TransferResponse), args.Error(1)
 }
-func (m *MockService) GetSummary(spenderId int, txnType string) (SummaryResponse, error) {
-	args := m.Called(spenderId, txnType)
+func (m *MockService) GetExpenses(spenderId int, filter Filter, pagination Pagination, sort Sort) ([]GetTransactionResponse, error) {
+	args := m.Called(spenderId, filter, pagination, sort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]GetTransactionResponse), args.Error(1)
+}
+func (m *MockService) GetSummary(spenderId int, query SummaryQuery) (SummaryResponse, error) {
+	args := m.Called(spenderId, query)
 	if args.Get(0) == nil {
 		return SummaryResponse{}, args.Error(1)
 	}
 	return args.Get(0).(SummaryResponse), args.Error(1)
 }
-func (m *MockService) GetBalance(spenderId int) (BalanceResponse, error) {
-	return BalanceResponse{}, nil
+func (m *MockService) GetBalance(spenderId int, query BalanceQuery) (BalanceResponse, error) {
+	args := m.Called(spenderId, query)
+	return args.Get(0).(BalanceResponse), args.Error(1)
+}
+func (m *MockService) GetCategoryReport(spenderId int, query CategoryQuery) (CategoryReport, error) {
+	args := m.Called(spenderId, query)
+	return args.Get(0).(CategoryReport), args.Error(1)
+}
+func (m *MockService) UpdateExpense(spenderId int, transaction Transaction) error {
+	args := m.Called(spenderId, transaction)
+	return args.Error(0)
+}
+func (m *MockService) DeleteExpense(spenderId int, id int) error {
+	args := m.Called(spenderId, id)
+	return args.Error(0)
+}
+func (m *MockService) Confirm(spenderId int, id int) error {
+	args := m.Called(spenderId, id)
+	return args.Error(0)
 }
-func (m *MockService) UpdateExpense(transaction Transaction) error {
-	return nil
+func (m *MockService) Reject(spenderId int, id int) error {
+	args := m.Called(spenderId, id)
+	return args.Error(0)
 }
-func (m *MockService) DeleteExpense(id int) error {
-	return nil
+
+func newAuthenticatedContext(e *echo.Echo, req *http.Request, rec *httptest.ResponseRecorder, spenderId int) echo.Context {
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{Username: "user", SpenderID: spenderId})
+	return c
 }
 
 func TestHandler_GetAll(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
 	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	c := newAuthenticatedContext(e, req, rec, 1)
 
 	c.Set("filter", Filter{})
 	c.Set("paginate", Pagination{})
@@ -61,7 +95,7 @@ func TestHandler_GetAll(t *testing.T) {
 	expected := []Transaction{
 		{
 			ID:        1,
-			Amount:    2000.0,
+			Amount:    2000_00,
 			Date:      nil,
 			Category:  "food",
 			ImageUrl:  "http://img.png",
@@ -71,15 +105,97 @@ func TestHandler_GetAll(t *testing.T) {
 	}
 
 	mockService := new(MockService)
-	mockService.On("GetAll", mock.Anything, mock.Anything).Return(expected, nil).Once()
+	mockService.On("GetAll", 1, mock.Anything, mock.Anything).Return(expected, nil).Once()
+	h := NewHandler(mockService)
+
+	err := h.GetAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_GetAll_ShouldListEverySpender_WhenAdmin(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		expectedSpender int
+		expectedStatus  int
+	}{
+		{"all spenders", "", AnySpender, http.StatusOK},
+		{"filtered by spender", "?spender_id=4", 4, http.StatusOK},
+		{"invalid spender", "?spender_id=abc", 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/transactions"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			auth.SetIdentity(c, auth.Identity{Username: "user", SpenderID: 1, Role: auth.RoleAdmin})
+
+			mockService := new(MockService)
+			mockService.On("GetAll", tt.expectedSpender, mock.Anything, mock.Anything).Return([]Transaction{}, nil)
+			h := NewHandler(mockService)
+
+			err := h.GetAll(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_GetAll_ShouldIgnoreSpenderParam_WhenNotAdmin(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/transactions?spender_id=4", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	auth.SetIdentity(c, auth.Identity{UserID: 7, SpenderID: 3, Role: auth.RoleSpender})
+
+	mockService := new(MockService)
+	mockService.On("GetAll", 3, mock.Anything, mock.Anything).Return([]Transaction{}, nil).Once()
 	h := NewHandler(mockService)
 
 	err := h.GetAll(c)
+
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockService.AssertExpectations(t)
 }
 
+func TestHandler_GetAll_ShouldRejectUnknownStatus(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/transactions?status=pending", nil)
+	rec := httptest.NewRecorder()
+	c := newAuthenticatedContext(e, req, rec, 1)
+	c.Set("filter", Filter{Status: "pending"})
+
+	mockService := new(MockService)
+	h := NewHandler(mockService)
+
+	err := h.GetAll(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandler_GetAll_ShouldReturnUnauthorized_WhenNoIdentity(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/expenses", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockService := new(MockService)
+	h := NewHandler(mockService)
+
+	err := h.GetAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestHandler_Create(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
@@ -93,9 +209,71 @@ func TestHandler_Create(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHandler_Create_ShouldRejectAmountWithMoreThanTwoDecimals(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(`{"amount": 19.999, "transaction_type": "expense"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := newAuthenticatedContext(e, req, rec, 1)
+
+	mockService := new(MockService)
+	h := NewHandler(mockService)
+	err := h.Create(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Create", mock.Anything)
+}
+
 func TestHandler_GetExpenses(t *testing.T) {
+	expenses := []GetTransactionResponse{
+		{ID: 2, Amount: 120_00, Category: "food", ImageUrl: "/api/v1/slips/a.png", SpenderId: 1, TxnType: "expense", Status: StatusConfirmed},
+	}
+
+	tests := []struct {
+		name           string
+		filter         Filter
+		sort           Sort
+		mockResult     []GetTransactionResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", Filter{Category: "food"}, Sort{By: SortByAmount, Order: OrderAsc}, expenses, nil, http.StatusOK},
+		{"invalid status", Filter{Status: "pending"}, Sort{By: SortByDate, Order: OrderDesc}, nil, nil, http.StatusBadRequest},
+		{"invalid sort", Filter{}, Sort{By: "note", Order: OrderDesc}, nil, ErrInvalidSort, http.StatusBadRequest},
+		{"internal error", Filter{}, Sort{By: SortByDate, Order: OrderDesc}, nil, errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/transactions/expense/detail", nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+			c.Set("filter", tt.filter)
+			c.Set("pagination", Pagination{ItemPerPage: 10, Page: 1})
+			c.Set("sort", tt.sort)
+
+			mockService := new(MockService)
+			mockService.On("GetExpenses", 1, tt.filter, Pagination{ItemPerPage: 10, Page: 1}, tt.sort).Return(tt.mockResult, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.GetExpenses(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var got []GetTransactionResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+				assert.Equal(t, expenses, got)
+			}
+		})
+	}
+}
+
+func TestHandler_GetExpenses_ShouldReturnUnauthorized_WhenNoIdentity(t *testing.T) {
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPost, "/transactions/expense/detail", nil)
+	req := httptest.NewRequest(http.MethodGet, "/transactions/expense/detail", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
@@ -104,6 +282,7 @@ func TestHandler_GetExpenses(t *testing.T) {
 	err := h.GetExpenses(c)
 
 	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
 
 func TestHandler_GetSummary(t *testing.T) {
@@ -118,12 +297,12 @@ func TestHandler_GetSummary(t *testing.T) {
 		expectedBody   interface{}
 	}{
 		{
-			name:           "bad request exception",
+			name:           "unauthorized when caller is not authenticated",
 			spenderId:      "",
 			txnType:        "expense",
 			mockResponse:   SummaryResponse{},
 			mockError:      nil,
-			expectedStatus: http.StatusBadRequest,
+			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   errs.ErrResponse{},
 		},
 		{
@@ -131,41 +310,53 @@ func TestHandler_GetSummary(t *testing.T) {
 			spenderId: "1",
 			txnType:   "expense",
 			mockResponse: SummaryResponse{
-				TotalAmount:     400,
-				AvgAmountPerDay: 200.0,
+				TotalAmount:     400_00,
+				AvgAmountPerDay: 200_00,
 				Total:           2,
 			},
 			mockError:      nil,
 			expectedStatus: http.StatusOK,
 			expectedBody: SummaryResponse{
-				TotalAmount:     400,
-				AvgAmountPerDay: 200.0,
+				TotalAmount:     400_00,
+				AvgAmountPerDay: 200_00,
 				Total:           2,
 			},
 		},
+		{
+			name:           "unprocessable when an exchange rate is missing",
+			spenderId:      "1",
+			txnType:        "expense",
+			mockResponse:   SummaryResponse{},
+			mockError:      currency.ErrMissingRate,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   errs.ErrResponse{},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodGet, "/summary?spender_id="+tt.spenderId+"&txn_type="+tt.txnType, nil)
+			req := httptest.NewRequest(http.MethodGet, "/summary?txn_type="+tt.txnType, nil)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
+			if spenderIdInt, err := strconv.Atoi(tt.spenderId); err == nil {
+				auth.SetIdentity(c, auth.Identity{Username: "user", SpenderID: spenderIdInt})
+			}
 
 			mockService := new(MockService)
 
 			h := handler{service: mockService}
 
 			if spenderIdInt, err := strconv.Atoi(tt.spenderId); err == nil {
-				mockService.On("GetSummary", spenderIdInt, tt.txnType).Return(tt.mockResponse, tt.mockError)
+				mockService.On("GetSummary", spenderIdInt, SummaryQuery{TxnType: tt.txnType}).Return(tt.mockResponse, tt.mockError)
 			}
 
 			spenderIdInt, err := strconv.Atoi(tt.spenderId)
 
 			if err != nil {
-				mockService.On("GetSummary", spenderIdInt, tt.txnType).Return(tt.mockResponse, tt.mockError)
+				mockService.On("GetSummary", spenderIdInt, SummaryQuery{TxnType: tt.txnType}).Return(tt.mockResponse, tt.mockError)
 			} else {
-				mockService.On("GetSummary", 0, tt.txnType).Return(tt.mockResponse, tt.mockError)
+				mockService.On("GetSummary", 0, SummaryQuery{TxnType: tt.txnType}).Return(tt.mockResponse, tt.mockError)
 			}
 
 			// Act
@@ -185,6 +376,46 @@ func TestHandler_GetSummary(t *testing.T) {
 	}
 }
 
+func TestHandler_GetSummary_ShouldPassDateRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockError      error
+		expectedStatus int
+	}{
+		{"range and average", "?txn_type=income&from=2024-04-01&to=2024-04-30&average=active", nil, http.StatusOK},
+		{"invalid from", "?from=01-04-2024", nil, http.StatusBadRequest},
+		{"invalid to", "?to=tomorrow", nil, http.StatusBadRequest},
+		{"invalid query", "?txn_type=transfer", ErrInvalidTxnType, http.StatusBadRequest},
+		{"internal error", "", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/transactions/summary"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+
+			mockService := new(MockService)
+			mockService.On("GetSummary", 1, mock.Anything).Return(SummaryResponse{}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.GetSummary(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.name == "range and average" {
+				query := mockService.Calls[0].Arguments.Get(1).(SummaryQuery)
+				assert.Equal(t, "income", query.TxnType)
+				assert.Equal(t, AverageActiveDays, query.Average)
+				assert.Equal(t, "2024-04-01", query.From.Format("2006-01-02"))
+				assert.Equal(t, "2024-04-30", query.To.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
 func TestHandler_GetBalance(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodPost, "/transactions/balance", nil)
@@ -196,30 +427,235 @@ func TestHandler_GetBalance(t *testing.T) {
 	err := h.GetBalance(c)
 
 	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHandler_GetBalance_ShouldPassPeriod(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockError      error
+		expectedStatus int
+	}{
+		{"range and granularity", "?from=2024-01-01&to=2024-03-31&granularity=week", nil, http.StatusOK},
+		{"invalid from", "?from=2024/01/01", nil, http.StatusBadRequest},
+		{"invalid to", "?to=yesterday", nil, http.StatusBadRequest},
+		{"invalid granularity", "?granularity=hour", ErrInvalidGranularity, http.StatusBadRequest},
+		{"from after to", "?from=2024-02-01&to=2024-01-01", ErrInvalidRange, http.StatusBadRequest},
+		{"internal error", "", errors.New("can't get balance"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/transactions/balance"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+
+			mockService := new(MockService)
+			mockService.On("GetBalance", 1, mock.Anything).Return(BalanceResponse{}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.GetBalance(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.name == "range and granularity" {
+				query := mockService.Calls[0].Arguments.Get(1).(BalanceQuery)
+				assert.Equal(t, GranularityWeek, query.Granularity)
+				assert.Equal(t, "2024-01-01", query.From.Format("2006-01-02"))
+				assert.Equal(t, "2024-03-31", query.To.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestHandler_GetCategoryReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		mockError      error
+		expectedStatus int
+	}{
+		{"range, filter and top", "?from=2024-04-01&to=2024-04-30&category=food&top=3", nil, http.StatusOK},
+		{"defaults to this month", "", nil, http.StatusOK},
+		{"invalid from", "?from=April", nil, http.StatusBadRequest},
+		{"invalid top", "?top=many", nil, http.StatusBadRequest},
+		{"from after to", "?from=2024-05-01&to=2024-04-01", ErrInvalidRange, http.StatusBadRequest},
+		{"internal error", "", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/reports/categories"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+			c.Set("filter", NewMiddlewareService().SetFilter(req.URL.Query()))
+
+			mockService := new(MockService)
+			mockService.On("GetCategoryReport", 1, mock.Anything).Return(CategoryReport{}, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.GetCategoryReport(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			switch tt.name {
+			case "range, filter and top":
+				query := mockService.Calls[0].Arguments.Get(1).(CategoryQuery)
+				assert.Equal(t, "2024-04-01", query.From.Format("2006-01-02"))
+				assert.Equal(t, "2024-04-30", query.To.Format("2006-01-02"))
+				assert.Equal(t, "food", query.Filter.Category)
+				assert.Equal(t, 3, query.Top)
+			case "defaults to this month":
+				query := mockService.Calls[0].Arguments.Get(1).(CategoryQuery)
+				assert.Equal(t, 1, query.From.Day())
+				assert.Equal(t, time.Now().Format("2006-01-02"), query.To.Format("2006-01-02"))
+			}
+		})
+	}
 }
 
 func TestHandler_UpdateExpense(t *testing.T) {
-	e := echo.New()
-	req := httptest.NewRequest(http.MethodPost, "/transactions/1", nil)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found when row belongs to another spender", ErrNotFound, http.StatusNotFound},
+		{"negative amount", ErrInvalidAmount, http.StatusBadRequest},
+		{"internal error", errors.New("db error"), http.StatusInternalServerError},
+	}
 
-	mockService := new(MockService)
-	h := NewHandler(mockService)
-	err := h.UpdateExpense(c)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/transactions/1", strings.NewReader(`{"amount": 100, "spender_id": 2}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
 
-	assert.NoError(t, err)
+			mockService := new(MockService)
+			mockService.On("UpdateExpense", 1, Transaction{ID: 1, Amount: 100_00, SpenderId: 1}).Return(tt.mockError).Once()
+			h := NewHandler(mockService)
+			err := h.UpdateExpense(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
 }
 
 func TestHandler_DeleteExpense(t *testing.T) {
-	e := echo.New()
-	req := httptest.NewRequest(http.MethodPost, "/transactions/1", nil)
-	rec := httptest.NewRecorder()
-	c := e.NewContext(req, rec)
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found when row belongs to another spender", ErrNotFound, http.StatusNotFound},
+		{"internal error", errors.New("db error"), http.StatusInternalServerError},
+	}
 
-	mockService := new(MockService)
-	h := NewHandler(mockService)
-	err := h.DeleteExpense(c)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodDelete, "/transactions/1", nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
 
-	assert.NoError(t, err)
+			mockService := new(MockService)
+			mockService.On("DeleteExpense", 1, 1).Return(tt.mockError).Once()
+			h := NewHandler(mockService)
+			err := h.DeleteExpense(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_Review(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         string
+		id             string
+		mockError      error
+		expectedStatus int
+	}{
+		{"confirm", "Confirm", "1", nil, http.StatusOK},
+		{"reject", "Reject", "1", nil, http.StatusOK},
+		{"invalid id", "Confirm", "abc", nil, http.StatusBadRequest},
+		{"not found when row belongs to another spender", "Confirm", "1", ErrNotFound, http.StatusNotFound},
+		{"conflict when already reviewed", "Reject", "1", ErrAlreadyReviewed, http.StatusConflict},
+		{"internal error", "Reject", "1", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/transactions/"+tt.id+"/"+strings.ToLower(tt.action), nil)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			mockService := new(MockService)
+			mockService.On(tt.action, 1, 1).Return(tt.mockError)
+			h := NewHandler(mockService)
+			review := h.Confirm
+			if tt.action == "Reject" {
+				review = h.Reject
+			}
+
+			err := review(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_CreateTransfer(t *testing.T) {
+	request := TransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 500_00, Note: "pay card", SpenderId: 1}
+
+	tests := []struct {
+		name           string
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", nil, http.StatusCreated},
+		{"same account", ErrInvalidTransfer, http.StatusBadRequest},
+		{"account of another spender", ErrInvalidAccount, http.StatusBadRequest},
+		{"different currencies", ErrCurrencyMismatch, http.StatusBadRequest},
+		{"internal error", errors.New("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			body := `{"from_account_id": 1, "to_account_id": 2, "amount": 500, "note": "pay card", "spender_id": 9}`
+			req := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := newAuthenticatedContext(e, req, rec, 1)
+
+			mockService := new(MockService)
+			mockService.On("CreateTransfer", request).Return(TransferResponse{DebitID: 3, CreditID: 4}, tt.mockError)
+			h := NewHandler(mockService)
+			err := h.CreateTransfer(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
 }
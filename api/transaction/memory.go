@@ -0,0 +1,815 @@
+package transaction
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/account"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// TimeZones tells the in-memory repository which time zone a spender's
+// reports are in; the SQL repository reads it from the spender table.
+type TimeZones interface {
+	TimeZone(spenderID int) string
+}
+
+// BaseCurrencies tells the in-memory repository which currency a spender's
+// reports are in; the SQL repository reads it from the spender table.
+type BaseCurrencies interface {
+	BaseCurrency(spenderID int) string
+}
+
+type memoryRepository struct {
+	mu             sync.Mutex
+	transactions   map[int]GetTransactionResponse
+	archived       []GetTransactionResponse
+	nextID         int
+	timeZones      TimeZones
+	categories     category.Repository
+	accounts       account.Repository
+	baseCurrencies BaseCurrencies
+	rates          currency.Converter
+}
+
+// MemoryRepository is the in-memory Repository. Besides the Repository
+// methods it lets the in-memory spender repository apply delete policies
+// and the in-memory account repository work out balances, and takes the
+// spenders' time zones and base currencies, the category catalog, the
+// accounts and the exchange rates once they exist.
+type MemoryRepository interface {
+	Repository
+	CountBySpender(spenderID int) int
+	RemoveBySpender(spenderID int, archive bool)
+	NetByAccount(spenderID int, currencies map[int]string) (map[int]money.Amount, error)
+	CountByAccount(accountID int) int
+	UseTimeZones(timeZones TimeZones)
+	UseCategories(categories category.Repository)
+	UseAccounts(accounts account.Repository)
+	UseCurrencies(baseCurrencies BaseCurrencies, rates currency.Converter)
+}
+
+// NewMemoryRepository keeps transactions in process memory for the
+// --storage=memory server mode.
+func NewMemoryRepository() MemoryRepository {
+	return &memoryRepository{
+		transactions: map[int]GetTransactionResponse{},
+		nextID:       1,
+	}
+}
+
+func (r *memoryRepository) GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filter = r.expandCategories(spenderId, filter)
+	expenses := []Transaction{}
+	for _, t := range r.sorted() {
+		if spenderId != AnySpender && t.SpenderId != spenderId {
+			continue
+		}
+		if !matches(t, filter) {
+			continue
+		}
+		expenses = append(expenses, toTransaction(t))
+	}
+
+	return page(expenses, paginate), nil
+}
+
+func (r *memoryRepository) Get(spenderId int, id int) (Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.transactions[id]
+	if !ok || t.SpenderId != spenderId {
+		return Transaction{}, ErrNotFound
+	}
+
+	return toTransaction(t), nil
+}
+
+func (r *memoryRepository) Create(request CreateTransactionRequest) (CreateTransactionResponse, error) {
+	code := request.Currency
+	if request.AccountID != nil {
+		a, err := r.account(request.SpenderId, *request.AccountID)
+		if err != nil {
+			return CreateTransactionResponse{}, err
+		}
+		if code == "" {
+			code = a.Currency
+		}
+	}
+	if code == "" {
+		code = r.baseCurrency(request.SpenderId)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := request.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+	categoryID, categoryName, err := r.resolveCategory(request.SpenderId, request.CategoryID, request.Category)
+	if err != nil {
+		return CreateTransactionResponse{}, err
+	}
+
+	id := r.nextID
+	r.nextID++
+	r.transactions[id] = GetTransactionResponse{
+		ID:         id,
+		Date:       request.Date,
+		Amount:     request.Amount,
+		Category:   categoryName,
+		CategoryID: categoryID,
+		AccountID:  request.AccountID,
+		Currency:   code,
+		ImageUrl:   request.ImageUrl,
+		Note:       request.Note,
+		SpenderId:  request.SpenderId,
+		TxnType:    request.TxnType,
+		Status:     status,
+	}
+
+	return CreateTransactionResponse{ID: id}, nil
+}
+
+func (r *memoryRepository) CreateTransfer(request TransferRequest) (TransferResponse, error) {
+	from, err := r.account(request.SpenderId, request.FromAccountID)
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	to, err := r.account(request.SpenderId, request.ToAccountID)
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	if from.Currency != to.Currency {
+		return TransferResponse{}, ErrCurrencyMismatch
+	}
+	code := from.Currency
+	if code == "" {
+		code = r.baseCurrency(request.SpenderId)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	response := TransferResponse{DebitID: r.nextID, CreditID: r.nextID + 1}
+	r.nextID += 2
+	legs := []struct {
+		id, accountID, counterpart int
+		entry                      string
+	}{
+		{response.DebitID, from.ID, response.CreditID, EntryDebit},
+		{response.CreditID, to.ID, response.DebitID, EntryCredit},
+	}
+	for _, leg := range legs {
+		accountID, counterpart := leg.accountID, leg.counterpart
+		r.transactions[leg.id] = GetTransactionResponse{
+			ID:         leg.id,
+			Date:       request.Date,
+			Amount:     request.Amount,
+			AccountID:  &accountID,
+			Currency:   code,
+			Note:       request.Note,
+			SpenderId:  request.SpenderId,
+			TxnType:    TxnTypeTransfer,
+			Status:     StatusConfirmed,
+			Entry:      leg.entry,
+			TransferID: &counterpart,
+		}
+	}
+
+	return response, nil
+}
+
+func (r *memoryRepository) GetExpenses(spenderId int, filter Filter, paginate Pagination, by Sort) ([]GetTransactionResponse, error) {
+	if !by.Valid() {
+		return nil, ErrInvalidSort
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filter = r.expandCategories(spenderId, filter)
+	expenses := []GetTransactionResponse{}
+	for _, t := range r.sorted() {
+		if t.SpenderId != spenderId || t.TxnType != "expense" || !matches(t, filter) {
+			continue
+		}
+		expenses = append(expenses, t)
+	}
+
+	sort.SliceStable(expenses, func(i, j int) bool {
+		a, b := expenses[i], expenses[j]
+		if by.Order == OrderDesc {
+			a, b = b, a
+		}
+		if by.By == SortByAmount {
+			return a.Amount < b.Amount
+		}
+		// Undated rows sort like NULLS FIRST ascending, NULLS LAST descending.
+		if a.Date == nil || b.Date == nil {
+			return a.Date == nil && b.Date != nil
+		}
+		return a.Date.Before(*b.Date)
+	})
+
+	return page(expenses, paginate), nil
+}
+
+// Summarize counts active days in UTC until UseTimeZones is called.
+func (r *memoryRepository) Summarize(spenderId int, query SummaryQuery) (Aggregate, error) {
+	loc := r.location(spenderId)
+	base := r.baseCurrency(spenderId)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filter := r.expandCategories(spenderId, Filter{CategoryIDs: query.CategoryIDs})
+	var a Aggregate
+	days := map[time.Time]bool{}
+	for _, t := range r.sorted() {
+		if t.SpenderId != spenderId || t.Status != StatusConfirmed || !matches(t, filter) {
+			continue
+		}
+		if query.TxnType != "" && t.TxnType != query.TxnType {
+			continue
+		}
+		if query.TxnType == "" && t.TxnType != "expense" && t.TxnType != "income" {
+			continue
+		}
+		if t.Date == nil {
+			if query.From != nil || query.To != nil {
+				continue
+			}
+		} else {
+			if query.From != nil && t.Date.Before(*query.From) {
+				continue
+			}
+			if query.To != nil && !t.Date.Before(query.To.AddDate(0, 0, 1)) {
+				continue
+			}
+			days[civilDate(t.Date.In(loc))] = true
+			if a.First == nil || t.Date.Before(*a.First) {
+				a.First = t.Date
+			}
+			if a.Last == nil || t.Date.After(*a.Last) {
+				a.Last = t.Date
+			}
+		}
+		amount, err := r.inBase(t, base)
+		if err != nil {
+			return Aggregate{}, err
+		}
+		a.TotalAmount += amount
+		a.Count++
+	}
+	a.ActiveDays = len(days)
+
+	return a, nil
+}
+
+// CashFlow reports in UTC until UseTimeZones is called.
+func (r *memoryRepository) CashFlow(spenderId int, query BalanceQuery) (CashFlow, error) {
+	// The time zone is looked up before locking, as the spender repository
+	// calls into this one while holding its own lock.
+	loc := r.location(spenderId)
+	base := r.baseCurrency(spenderId)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var from, to time.Time
+	if query.From != nil {
+		from = civilDate(*query.From)
+	}
+	if query.To != nil {
+		to = civilDate(*query.To)
+	}
+
+	flow := CashFlow{Buckets: []BalanceBucket{}}
+	buckets := map[time.Time]*BalanceBucket{}
+	var starts []time.Time
+	for _, t := range r.sorted() {
+		if t.SpenderId != spenderId || t.Status != StatusConfirmed || t.Date == nil {
+			continue
+		}
+		day := civilDate(t.Date.In(loc))
+		if query.To != nil && day.After(to) {
+			continue
+		}
+		if t.TxnType != "expense" && t.TxnType != "income" {
+			continue
+		}
+		amount, err := r.inBase(t, base)
+		if err != nil {
+			return CashFlow{}, err
+		}
+		if t.TxnType == "expense" {
+			amount = -amount
+		}
+		if query.From != nil && day.Before(from) {
+			flow.Opening += amount
+			continue
+		}
+
+		start := truncate(day, query.Granularity)
+		b, ok := buckets[start]
+		if !ok {
+			b = &BalanceBucket{Start: start.Format("2006-01-02")}
+			buckets[start] = b
+			starts = append(starts, start)
+		}
+		if amount > 0 {
+			b.Earned += amount
+		} else {
+			b.Spent -= amount
+		}
+		b.Saved += amount
+	}
+
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	balance := flow.Opening
+	for _, start := range starts {
+		b := buckets[start]
+		balance += b.Saved
+		b.Balance = balance
+		flow.Buckets = append(flow.Buckets, *b)
+	}
+
+	return flow, nil
+}
+
+func (r *memoryRepository) CategoryTotals(spenderId int, query CategoryQuery) ([]CategoryTotal, error) {
+	base := r.baseCurrency(spenderId)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, _ := query.Previous()
+	totals := map[[2]string]*CategoryTotal{}
+	for _, t := range r.inReport(spenderId, query, previous) {
+		key := [2]string{t.TxnType, t.Category}
+		c, ok := totals[key]
+		if !ok {
+			c = &CategoryTotal{TxnType: t.TxnType, Category: t.Category}
+			totals[key] = c
+		}
+		amount, err := r.inBase(t, base)
+		if err != nil {
+			return nil, err
+		}
+		if t.Date.Before(query.From) {
+			c.PreviousTotal += amount
+			continue
+		}
+		c.Total += amount
+		c.Count++
+	}
+
+	result := []CategoryTotal{}
+	for _, c := range totals {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TxnType != result[j].TxnType {
+			return result[i].TxnType < result[j].TxnType
+		}
+		return result[i].Category < result[j].Category
+	})
+	return result, nil
+}
+
+func (r *memoryRepository) TopNotes(spenderId int, query CategoryQuery) ([]NoteTotal, error) {
+	base := r.baseCurrency(spenderId)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := map[[2]string]*NoteTotal{}
+	for _, t := range r.inReport(spenderId, query, query.From) {
+		if t.Note == "" {
+			continue
+		}
+		key := [2]string{t.TxnType, t.Note}
+		n, ok := notes[key]
+		if !ok {
+			n = &NoteTotal{TxnType: t.TxnType, Note: t.Note}
+			notes[key] = n
+		}
+		amount, err := r.inBase(t, base)
+		if err != nil {
+			return nil, err
+		}
+		n.Total += amount
+		n.Count++
+	}
+
+	result := []NoteTotal{}
+	for _, n := range notes {
+		result = append(result, *n)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.TxnType != b.TxnType {
+			return a.TxnType < b.TxnType
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Note < b.Note
+	})
+
+	top := []NoteTotal{}
+	ranks := map[string]int{}
+	for _, n := range result {
+		if ranks[n.TxnType] < query.Top {
+			ranks[n.TxnType]++
+			top = append(top, n)
+		}
+	}
+	return top, nil
+}
+
+// inReport returns the spender's confirmed, dated expenses and incomes from
+// since to the end of the query that match its filter.
+func (r *memoryRepository) inReport(spenderId int, query CategoryQuery, since time.Time) []GetTransactionResponse {
+	until := query.To.AddDate(0, 0, 1)
+	filter := r.expandCategories(spenderId, query.Filter)
+	var result []GetTransactionResponse
+	for _, t := range r.sorted() {
+		if t.SpenderId != spenderId || t.Status != StatusConfirmed || t.Date == nil {
+			continue
+		}
+		if t.TxnType != "expense" && t.TxnType != "income" {
+			continue
+		}
+		if t.Date.Before(since) || !t.Date.Before(until) || !matches(t, filter) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func (r *memoryRepository) UpdateExpense(spenderId int, transaction Transaction) error {
+	if err := r.checkAccount(spenderId, transaction.AccountID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.transactions[transaction.ID]
+	if !ok || t.SpenderId != spenderId || t.TxnType == TxnTypeTransfer {
+		return ErrNotFound
+	}
+	categoryID, categoryName, err := r.resolveCategory(spenderId, transaction.CategoryID, transaction.Category)
+	if err != nil {
+		return err
+	}
+	t.Date = transaction.Date
+	t.Amount = transaction.Amount
+	t.Category = categoryName
+	t.CategoryID = categoryID
+	t.AccountID = transaction.AccountID
+	if transaction.Currency != "" {
+		t.Currency = transaction.Currency
+	}
+	t.ImageUrl = transaction.ImageUrl
+	t.Note = transaction.Note
+	r.transactions[t.ID] = t
+
+	return nil
+}
+
+func (r *memoryRepository) DeleteExpense(spenderId int, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.transactions[id]
+	if !ok || t.SpenderId != spenderId {
+		return ErrNotFound
+	}
+	delete(r.transactions, id)
+	if t.TransferID != nil {
+		delete(r.transactions, *t.TransferID)
+	}
+
+	return nil
+}
+
+func (r *memoryRepository) Review(spenderId int, id int, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.transactions[id]
+	if !ok || t.SpenderId != spenderId {
+		return ErrNotFound
+	}
+	if t.Status != StatusDraft && t.Status != status {
+		return ErrAlreadyReviewed
+	}
+	t.Status = status
+	r.transactions[id] = t
+
+	return nil
+}
+
+func (r *memoryRepository) CountBySpender(spenderID int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, t := range r.transactions {
+		if t.SpenderId == spenderID {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *memoryRepository) RemoveBySpender(spenderID int, archive bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, t := range r.transactions {
+		if t.SpenderId != spenderID {
+			continue
+		}
+		if archive {
+			r.archived = append(r.archived, t)
+		}
+		delete(r.transactions, id)
+	}
+}
+
+// NetByAccount adds up the spender's confirmed transactions per account:
+// incomes and incoming transfers less expenses and outgoing transfers, each
+// in the account's currency as given by currencies.
+func (r *memoryRepository) NetByAccount(spenderID int, currencies map[int]string) (map[int]money.Amount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	net := map[int]money.Amount{}
+	for _, t := range r.transactions {
+		if t.SpenderId != spenderID || t.Status != StatusConfirmed || t.AccountID == nil {
+			continue
+		}
+		amount, err := r.convert(t.Amount, t.Currency, currencies[*t.AccountID], t.Date)
+		if err != nil {
+			return nil, err
+		}
+		if t.TxnType == "income" || t.Entry == EntryCredit {
+			net[*t.AccountID] += amount
+		} else {
+			net[*t.AccountID] -= amount
+		}
+	}
+	return net, nil
+}
+
+func (r *memoryRepository) CountByAccount(accountID int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, t := range r.transactions {
+		if t.AccountID != nil && *t.AccountID == accountID {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *memoryRepository) UseTimeZones(timeZones TimeZones) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timeZones = timeZones
+}
+
+func (r *memoryRepository) UseCategories(categories category.Repository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.categories = categories
+}
+
+func (r *memoryRepository) UseAccounts(accounts account.Repository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.accounts = accounts
+}
+
+func (r *memoryRepository) UseCurrencies(baseCurrencies BaseCurrencies, rates currency.Converter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.baseCurrencies = baseCurrencies
+	r.rates = rates
+}
+
+// account finds one of the spender's accounts. It is looked up before
+// locking, as the account repository calls into this one while holding its
+// own lock. Without accounts every id is accepted as is.
+func (r *memoryRepository) account(spenderId int, id int) (account.Account, error) {
+	r.mu.Lock()
+	accounts := r.accounts
+	r.mu.Unlock()
+
+	if accounts == nil {
+		return account.Account{ID: id, SpenderID: spenderId}, nil
+	}
+	a, err := accounts.Get(spenderId, id)
+	if errors.Is(err, account.ErrNotFound) {
+		return account.Account{}, ErrInvalidAccount
+	}
+	return a, err
+}
+
+func (r *memoryRepository) checkAccount(spenderId int, id *int) error {
+	if id == nil {
+		return nil
+	}
+	_, err := r.account(spenderId, *id)
+	return err
+}
+
+// resolveCategory mirrors the SQL repository's. Without a catalog every
+// category id is accepted as is.
+func (r *memoryRepository) resolveCategory(spenderId int, id *int, name string) (*int, string, error) {
+	if r.categories == nil || (id == nil && strings.TrimSpace(name) == "") {
+		return id, name, nil
+	}
+	visible, err := r.categories.List(spenderId)
+	if err != nil {
+		return nil, "", err
+	}
+	// The spender's own categories take precedence over system ones.
+	sort.SliceStable(visible, func(i, j int) bool { return visible[i].SpenderID != nil && visible[j].SpenderID == nil })
+
+	for _, c := range visible {
+		if (id != nil && c.ID == *id) || (id == nil && c.Named(name)) {
+			if name == "" {
+				name = c.NameEN
+			}
+			found := c.ID
+			return &found, name, nil
+		}
+	}
+	if id != nil {
+		return nil, "", ErrInvalidCategory
+	}
+	return nil, name, nil
+}
+
+// expandCategories adds the subcategories of the filter's categories, as the
+// SQL filter does with a recursive query.
+func (r *memoryRepository) expandCategories(spenderId int, filter Filter) Filter {
+	if r.categories == nil || len(filter.CategoryIDs) == 0 {
+		return filter
+	}
+	visible, err := r.categories.List(spenderId)
+	if err != nil {
+		return filter
+	}
+	filter.CategoryIDs = category.Subtree(visible, filter.CategoryIDs)
+	return filter
+}
+
+func (r *memoryRepository) location(spenderID int) *time.Location {
+	r.mu.Lock()
+	timeZones := r.timeZones
+	r.mu.Unlock()
+
+	if timeZones == nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timeZones.TimeZone(spenderID))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// baseCurrency is looked up before locking, like the time zone. Without base
+// currencies every spender reports in currency.Default.
+func (r *memoryRepository) baseCurrency(spenderID int) string {
+	r.mu.Lock()
+	baseCurrencies := r.baseCurrencies
+	r.mu.Unlock()
+
+	if baseCurrencies == nil {
+		return currency.Default
+	}
+	return baseCurrencies.BaseCurrency(spenderID)
+}
+
+func (r *memoryRepository) inBase(t GetTransactionResponse, base string) (money.Amount, error) {
+	return r.convert(t.Amount, t.Currency, base, t.Date)
+}
+
+// convert mirrors the SQL function convert_currency, rounding to the satang
+// and taking an undated transaction at the current rate. Without rates
+// amounts are taken as is.
+func (r *memoryRepository) convert(amount money.Amount, from, to string, date *time.Time) (money.Amount, error) {
+	if r.rates == nil || from == "" || to == "" || from == to {
+		return amount, nil
+	}
+	at := time.Now()
+	if date != nil {
+		at = *date
+	}
+	return r.rates.Convert(amount, from, to, at)
+}
+
+// sorted returns the transactions in id order, like the SQL queries do in
+// practice for an append-only table.
+func (r *memoryRepository) sorted() []GetTransactionResponse {
+	all := make([]GetTransactionResponse, 0, len(r.transactions))
+	for _, t := range r.transactions {
+		all = append(all, t)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+func matches(t GetTransactionResponse, filter Filter) bool {
+	if filter.Date != nil && (t.Date == nil || !t.Date.Equal(*filter.Date)) {
+		return false
+	}
+	if filter.Amount != 0 && t.Amount != filter.Amount {
+		return false
+	}
+	if filter.Category != "" && t.Category != filter.Category {
+		return false
+	}
+	if len(filter.CategoryIDs) > 0 && (t.CategoryID == nil || !slices.Contains(filter.CategoryIDs, *t.CategoryID)) {
+		return false
+	}
+	if filter.AccountID != nil && (t.AccountID == nil || *t.AccountID != *filter.AccountID) {
+		return false
+	}
+	if filter.Status != "" && t.Status != filter.Status {
+		return false
+	}
+	return true
+}
+
+// page applies LIMIT and OFFSET the way the SQL queries do.
+func page[T any](items []T, paginate Pagination) []T {
+	offset := (paginate.Page - 1) * paginate.ItemPerPage
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if paginate.ItemPerPage >= 0 && paginate.ItemPerPage < len(items) {
+		items = items[:paginate.ItemPerPage]
+	}
+	return items
+}
+
+// truncate is date_trunc on a civil date; weeks start on Monday.
+func truncate(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case GranularityYear:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func toTransaction(t GetTransactionResponse) Transaction {
+	return Transaction{
+		ID:         t.ID,
+		Date:       t.Date,
+		Amount:     t.Amount,
+		Category:   t.Category,
+		CategoryID: t.CategoryID,
+		AccountID:  t.AccountID,
+		Currency:   t.Currency,
+		ImageUrl:   t.ImageUrl,
+		Note:       t.Note,
+		SpenderId:  t.SpenderId,
+		Status:     t.Status,
+		Entry:      t.Entry,
+		TransferID: t.TransferID,
+	}
+}
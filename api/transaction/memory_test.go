@@ -0,0 +1,338 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/account"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/category"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	newRepo := func() MemoryRepository {
+		repo := NewMemoryRepository()
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 100_00, Category: "food", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 500_00, Category: "salary", SpenderId: 1, TxnType: "income"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 200_00, Category: "food", SpenderId: 2, TxnType: "expense"})
+		return repo
+	}
+
+	t.Run("GetAll scopes to spender, filters and paginates", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+
+		// Act
+		own, _ := repo.GetAll(1, Filter{}, Pagination{ItemPerPage: 10, Page: 1})
+		food, _ := repo.GetAll(AnySpender, Filter{Category: "food"}, Pagination{ItemPerPage: 10, Page: 1})
+		second, _ := repo.GetAll(AnySpender, Filter{}, Pagination{ItemPerPage: 2, Page: 2})
+
+		// Assert
+		assert.Len(t, own, 2)
+		assert.Len(t, food, 2)
+		assert.Equal(t, []Transaction{{ID: 3, Amount: 200_00, Category: "food", Currency: "THB", SpenderId: 2, Status: StatusConfirmed}}, second)
+	})
+
+	t.Run("drafts are listed by status and left out of summaries until confirmed", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+		draft, _ := repo.Create(CreateTransactionRequest{Amount: 50_00, SpenderId: 1, TxnType: "expense", Status: StatusDraft})
+
+		// Act
+		drafts, _ := repo.GetAll(1, Filter{Status: StatusDraft}, Pagination{ItemPerPage: 10, Page: 1})
+		before, _ := repo.Summarize(1, SummaryQuery{TxnType: "expense"})
+		otherErr := repo.Review(2, draft.ID, StatusConfirmed)
+		confirmErr := repo.Review(1, draft.ID, StatusConfirmed)
+		againErr := repo.Review(1, draft.ID, StatusConfirmed)
+		rejectErr := repo.Review(1, draft.ID, StatusRejected)
+		after, _ := repo.Summarize(1, SummaryQuery{TxnType: "expense"})
+
+		// Assert
+		assert.Len(t, drafts, 1)
+		assert.Equal(t, 1, before.Count)
+		assert.Equal(t, ErrNotFound, otherErr)
+		assert.NoError(t, confirmErr)
+		assert.NoError(t, againErr)
+		assert.Equal(t, ErrAlreadyReviewed, rejectErr)
+		assert.Equal(t, 2, after.Count)
+	})
+
+	t.Run("Summarize adds up confirmed transactions in range", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		day := func(d int) *time.Time {
+			date := time.Date(2024, time.April, d, 12, 0, 0, 0, time.UTC)
+			return &date
+		}
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(1), Amount: 100_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(1), Amount: 50_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(3), Amount: 200_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(3), Amount: 999_00, SpenderId: 1, TxnType: "expense", Status: StatusDraft})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(9), Amount: 300_00, SpenderId: 1, TxnType: "expense"})
+		from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, time.April, 3, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		aggregate, _ := repo.Summarize(1, SummaryQuery{TxnType: "expense", From: &from, To: &to})
+
+		// Assert
+		assert.Equal(t, Aggregate{TotalAmount: 350_00, Count: 3, ActiveDays: 2, First: day(1), Last: day(3)}, aggregate)
+	})
+
+	t.Run("Summarize counts active days on the spender's calendar", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		repo.UseTimeZones(fixedTimeZone("Asia/Bangkok"))
+		// 1 May 10:00 and 20:00 UTC are 1 and 2 May in Bangkok.
+		morning := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		evening := time.Date(2024, time.May, 1, 20, 0, 0, 0, time.UTC)
+		_, _ = repo.Create(CreateTransactionRequest{Date: &morning, Amount: 100_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: &evening, Amount: 100_00, SpenderId: 1, TxnType: "expense"})
+
+		// Act
+		aggregate, _ := repo.Summarize(1, SummaryQuery{TxnType: "expense"})
+
+		// Assert
+		assert.Equal(t, 2, aggregate.ActiveDays)
+	})
+
+	t.Run("CashFlow buckets by the spender's calendar with a running balance", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		repo.UseTimeZones(fixedTimeZone("Asia/Bangkok"))
+		at := func(month time.Month, d, hour int) *time.Time {
+			date := time.Date(2024, month, d, hour, 0, 0, 0, time.UTC)
+			return &date
+		}
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.January, 20, 0), Amount: 100_00, SpenderId: 1, TxnType: "income"})
+		// 31 Jan 20:00 UTC is 1 Feb in Bangkok.
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.January, 31, 20), Amount: 1000_00, SpenderId: 1, TxnType: "income"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.February, 10, 0), Amount: 300_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.February, 11, 0), Amount: 999_00, SpenderId: 1, TxnType: "expense", Status: StatusDraft})
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.March, 5, 0), Amount: 200_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.April, 5, 0), Amount: 50_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: at(time.March, 1, 0), Amount: 70_00, SpenderId: 2, TxnType: "expense"})
+		from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		flow, _ := repo.CashFlow(1, BalanceQuery{From: &from, To: &to, Granularity: GranularityMonth})
+
+		// Assert
+		assert.Equal(t, CashFlow{
+			Opening: 100_00,
+			Buckets: []BalanceBucket{
+				{Start: "2024-02-01", Earned: 1000_00, Spent: 300_00, Saved: 700_00, Balance: 800_00},
+				{Start: "2024-03-01", Spent: 200_00, Saved: -200_00, Balance: 600_00},
+			},
+		}, flow)
+	})
+
+	t.Run("CashFlow weeks start on Monday", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		sunday := time.Date(2024, time.May, 19, 12, 0, 0, 0, time.UTC)
+		monday := sunday.AddDate(0, 0, 1)
+		_, _ = repo.Create(CreateTransactionRequest{Date: &sunday, Amount: 10_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: &monday, Amount: 20_00, SpenderId: 1, TxnType: "expense"})
+
+		// Act
+		flow, _ := repo.CashFlow(1, BalanceQuery{Granularity: GranularityWeek})
+
+		// Assert
+		assert.Equal(t, []BalanceBucket{
+			{Start: "2024-05-13", Spent: 10_00, Saved: -10_00, Balance: -10_00},
+			{Start: "2024-05-20", Spent: 20_00, Saved: -20_00, Balance: -30_00},
+		}, flow.Buckets)
+	})
+
+	t.Run("CategoryTotals and TopNotes cover the period and the previous one", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		day := func(month time.Month, d int) *time.Time {
+			date := time.Date(2024, month, d, 12, 0, 0, 0, time.UTC)
+			return &date
+		}
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.March, 10), Amount: 200_00, Category: "food", Note: "7-Eleven", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.April, 2), Amount: 120_00, Category: "food", Note: "7-Eleven", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.April, 3), Amount: 180_00, Category: "food", Note: "Lotus", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.April, 4), Amount: 150_00, Category: "food", Note: "7-Eleven", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.April, 5), Amount: 999_00, Category: "food", SpenderId: 1, TxnType: "expense", Status: StatusDraft})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.April, 6), Amount: 3000_00, Category: "salary", SpenderId: 1, TxnType: "income"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(time.May, 1), Amount: 50_00, Category: "food", SpenderId: 1, TxnType: "expense"})
+		query := CategoryQuery{
+			From: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC),
+			Top:  1,
+		}
+
+		// Act
+		totals, _ := repo.CategoryTotals(1, query)
+		notes, _ := repo.TopNotes(1, query)
+
+		// Assert
+		assert.Equal(t, []CategoryTotal{
+			{TxnType: "expense", Category: "food", Total: 450_00, Count: 3, PreviousTotal: 200_00},
+			{TxnType: "income", Category: "salary", Total: 3000_00, Count: 1},
+		}, totals)
+		assert.Equal(t, []NoteTotal{{TxnType: "expense", Note: "7-Eleven", Total: 270_00, Count: 2}}, notes)
+	})
+
+	t.Run("categories resolve by id or name and filter with subcategories", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		repo.UseCategories(category.NewMemoryRepository())
+		groceries, dining, transport, unknown := 2, 3, 5, 999
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 100_00, CategoryID: &groceries, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 200_00, Category: "dining OUT", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 300_00, Category: "อาหาร", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 400_00, CategoryID: &transport, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 500_00, Category: "snacks", SpenderId: 1, TxnType: "expense"})
+
+		// Act
+		_, invalidErr := repo.Create(CreateTransactionRequest{Amount: 1_00, CategoryID: &unknown, SpenderId: 1, TxnType: "expense"})
+		food, _ := repo.GetAll(1, Filter{CategoryIDs: []int{1}}, Pagination{ItemPerPage: 10, Page: 1})
+		dined, _ := repo.GetExpenses(1, Filter{CategoryIDs: []int{dining}}, Pagination{ItemPerPage: 10, Page: 1}, Sort{By: SortByDate, Order: OrderAsc})
+		all, _ := repo.GetAll(1, Filter{}, Pagination{ItemPerPage: 10, Page: 1})
+
+		// Assert
+		assert.Equal(t, ErrInvalidCategory, invalidErr)
+		assert.Len(t, food, 3)
+		assert.Len(t, dined, 1)
+		assert.Equal(t, "Groceries", all[0].Category)
+		assert.Equal(t, "dining OUT", all[1].Category)
+		assert.Equal(t, dining, *all[1].CategoryID)
+		assert.Equal(t, 1, *all[2].CategoryID)
+		assert.Nil(t, all[4].CategoryID)
+	})
+
+	t.Run("GetExpenses lists only the spender's expenses in sort order", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 300_00, Category: "rent", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 50_00, Category: "food", SpenderId: 1, TxnType: "expense"})
+
+		// Act
+		byAmount, _ := repo.GetExpenses(1, Filter{}, Pagination{ItemPerPage: 10, Page: 1}, Sort{By: SortByAmount, Order: OrderDesc})
+		food, _ := repo.GetExpenses(1, Filter{Category: "food"}, Pagination{ItemPerPage: 1, Page: 2}, Sort{By: SortByAmount, Order: OrderAsc})
+
+		// Assert
+		amounts := []money.Amount{}
+		for _, e := range byAmount {
+			amounts = append(amounts, e.Amount)
+		}
+		assert.Equal(t, []money.Amount{300_00, 100_00, 50_00}, amounts)
+		assert.Len(t, food, 1)
+		assert.Equal(t, money.Amount(100_00), food[0].Amount)
+	})
+
+	t.Run("UpdateExpense and DeleteExpense do not touch other spenders", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+
+		// Act
+		updateErr := repo.UpdateExpense(2, Transaction{ID: 1, Amount: 1_00})
+		deleteErr := repo.DeleteExpense(2, 1)
+
+		// Assert
+		assert.Equal(t, ErrNotFound, updateErr)
+		assert.Equal(t, ErrNotFound, deleteErr)
+		assert.Equal(t, 2, repo.CountBySpender(1))
+	})
+
+	t.Run("transfers move money between accounts without counting as spent or earned", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+		accounts := account.NewMemoryRepository(repo)
+		repo.UseAccounts(accounts)
+		bank, _ := accounts.Create(account.Account{SpenderID: 1, Name: "Bank", Type: account.TypeBank, Currency: "THB", OpeningBalance: 5000_00})
+		card, _ := accounts.Create(account.Account{SpenderID: 1, Name: "Card", Type: account.TypeCreditCard, Currency: "THB"})
+		dollars, _ := accounts.Create(account.Account{SpenderID: 1, Name: "Dollars", Type: account.TypeCash, Currency: "USD"})
+		other, _ := accounts.Create(account.Account{SpenderID: 2, Name: "Bank", Type: account.TypeBank, Currency: "THB"})
+		_, _ = repo.Create(CreateTransactionRequest{Amount: 800_00, SpenderId: 1, TxnType: "expense", AccountID: &card.ID})
+
+		// Act
+		transfer, transferErr := repo.CreateTransfer(TransferRequest{FromAccountID: bank.ID, ToAccountID: card.ID, Amount: 800_00, SpenderId: 1})
+		_, foreignErr := repo.CreateTransfer(TransferRequest{FromAccountID: bank.ID, ToAccountID: other.ID, Amount: 1_00, SpenderId: 1})
+		_, currencyErr := repo.CreateTransfer(TransferRequest{FromAccountID: bank.ID, ToAccountID: dollars.ID, Amount: 1_00, SpenderId: 1})
+		_, createErr := repo.Create(CreateTransactionRequest{Amount: 1_00, SpenderId: 1, TxnType: "expense", AccountID: &other.ID})
+		updateErr := repo.UpdateExpense(1, Transaction{ID: transfer.DebitID, Amount: 1_00})
+		summary, _ := repo.Summarize(1, SummaryQuery{})
+		balances, _ := accounts.List(1)
+		cardOnly, _ := repo.GetAll(1, Filter{AccountID: &card.ID}, Pagination{ItemPerPage: 10, Page: 1})
+		inUseErr := accounts.Delete(1, bank.ID)
+		deleteErr := repo.DeleteExpense(1, transfer.CreditID)
+		unusedErr := accounts.Delete(1, bank.ID)
+
+		// Assert
+		assert.NoError(t, transferErr)
+		assert.Equal(t, ErrInvalidAccount, foreignErr)
+		assert.Equal(t, ErrCurrencyMismatch, currencyErr)
+		assert.Equal(t, ErrInvalidAccount, createErr)
+		assert.Equal(t, ErrNotFound, updateErr)
+		assert.Equal(t, money.Amount(1400_00), summary.TotalAmount)
+		assert.Equal(t, 3, summary.Count)
+		assert.Equal(t, money.Amount(4200_00), balances[0].Balance)
+		assert.Equal(t, money.Amount(0), balances[1].Balance)
+		assert.Len(t, cardOnly, 2)
+		assert.Equal(t, EntryCredit, cardOnly[1].Entry)
+		assert.Equal(t, transfer.DebitID, *cardOnly[1].TransferID)
+		assert.Equal(t, account.ErrHasTransactions, inUseErr)
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, unusedErr)
+		assert.Equal(t, 3, repo.CountBySpender(1))
+	})
+
+	t.Run("amounts in other currencies convert to the base currency on the transaction date", func(t *testing.T) {
+		// Arrange
+		repo := NewMemoryRepository()
+		rates := currency.NewMemoryRepository()
+		_ = rates.Save([]currency.Rate{{Currency: "USD", Date: "2024-04-01", Rate: 36}, {Currency: "USD", Date: "2024-04-05", Rate: 35}})
+		repo.UseCurrencies(fixedBaseCurrency("THB"), rates)
+		day := func(d int) *time.Time {
+			date := time.Date(2024, time.April, d, 12, 0, 0, 0, time.UTC)
+			return &date
+		}
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(2), Amount: 100_00, SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(2), Amount: 10_00, Currency: "USD", SpenderId: 1, TxnType: "expense"})
+		_, _ = repo.Create(CreateTransactionRequest{Date: day(6), Amount: 10_00, Currency: "USD", SpenderId: 1, TxnType: "expense"})
+		april := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+		march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		_, _ = repo.Create(CreateTransactionRequest{Date: &march, Amount: 10_00, Currency: "USD", SpenderId: 2, TxnType: "expense"})
+
+		// Act
+		aggregate, err := repo.Summarize(1, SummaryQuery{TxnType: "expense", From: &april})
+		all, _ := repo.GetAll(1, Filter{}, Pagination{ItemPerPage: 10, Page: 1})
+		_, missingErr := repo.Summarize(2, SummaryQuery{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, money.Amount(810_00), aggregate.TotalAmount)
+		assert.Equal(t, "THB", all[0].Currency)
+		assert.Equal(t, "USD", all[1].Currency)
+		assert.Equal(t, money.Amount(10_00), all[1].Amount)
+		assert.Equal(t, currency.ErrMissingRate, missingErr)
+	})
+
+	t.Run("RemoveBySpender removes only that spender", func(t *testing.T) {
+		// Arrange
+		repo := newRepo()
+
+		// Act
+		repo.RemoveBySpender(1, true)
+
+		// Assert
+		assert.Equal(t, 0, repo.CountBySpender(1))
+		assert.Equal(t, 1, repo.CountBySpender(2))
+	})
+}
+
+type fixedTimeZone string
+
+func (tz fixedTimeZone) TimeZone(int) string { return string(tz) }
+
+type fixedBaseCurrency string
+
+func (c fixedBaseCurrency) BaseCurrency(int) string { return string(c) }
@@ -11,6 +11,7 @@ type middleware struct {
 type Middleware interface {
 	SetFilterExpense(next echo.HandlerFunc) echo.HandlerFunc
 	SetPagination(next echo.HandlerFunc) echo.HandlerFunc
+	SetSort(next echo.HandlerFunc) echo.HandlerFunc
 }
 
 func NewMiddleware(middlewareService MiddlewareService) Middleware {
@@ -46,3 +47,12 @@ func (m middleware) SetPagination(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+func (m middleware) SetSort(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		result := m.middlewareService.SetSort(c.QueryParams())
+		c.Set("sort", result)
+
+		return next(c)
+	}
+}
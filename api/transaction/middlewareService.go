@@ -2,7 +2,10 @@ package transaction
 
 import (
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
 )
 
 type middlewareService struct{}
@@ -10,6 +13,7 @@ type middlewareService struct{}
 type MiddlewareService interface {
 	SetFilter(queryParams map[string][]string) Filter
 	SetPagination(queryParams map[string][]string) Pagination
+	SetSort(queryParams map[string][]string) Sort
 }
 
 func NewMiddlewareService() MiddlewareService {
@@ -28,12 +32,21 @@ func (m middlewareService) SetFilter(queryParams map[string][]string) Filter {
 				filter.Date = &parseDate
 			}
 		case "amount":
-			amount, err := strconv.ParseFloat(value, 32)
-			if err == nil {
+			amount, err := money.Parse(value)
+			if err == nil && amount > 0 {
 				filter.Amount = amount
 			}
 		case "category":
 			filter.Category = value
+		case "category_id":
+			filter.CategoryIDs = categoryIDs(values)
+		case "account_id":
+			id, err := strconv.Atoi(value)
+			if err == nil && id > 0 {
+				filter.AccountID = &id
+			}
+		case "status":
+			filter.Status = value
 		}
 	}
 
@@ -65,3 +78,39 @@ func (m middlewareService) SetPagination(queryParams map[string][]string) Pagina
 
 	return pagination
 }
+
+// SetSort defaults to the newest transactions first.
+func (m middlewareService) SetSort(queryParams map[string][]string) Sort {
+	sort := Sort{
+		By:    SortByDate,
+		Order: OrderDesc,
+	}
+
+	for key, values := range queryParams {
+		value := values[0]
+
+		switch key {
+		case "sort_by":
+			sort.By = value
+		case "order":
+			sort.Order = value
+		}
+	}
+
+	return sort
+}
+
+// categoryIDs reads category_id=1&category_id=2 as well as category_id=1,2,
+// skipping values that are not ids like the other filters do.
+func categoryIDs(values []string) []int {
+	var ids []int
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err == nil && id > 0 {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"testing"
 	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
 )
 
 func TestSetFilter(t *testing.T) {
@@ -13,8 +15,8 @@ func TestSetFilter(t *testing.T) {
 	date := "2023-05-18"
 	expectedDate, _ := time.ParseInLocation("2006-01-02", date, time.Now().Location())
 
-	amount := "2000"
-	expectedAmount, _ := strconv.ParseFloat(amount, 32)
+	amount := "19.99"
+	expectedAmount := money.Amount(19_99)
 
 	expectedCategory := "food"
 
@@ -39,6 +41,18 @@ func TestSetFilter(t *testing.T) {
 			expected: Filter{
 				Amount: expectedAmount,
 			},
+		}, {
+			test: "amount with more than two decimals is ignored",
+			queryParams: map[string][]string{
+				"amount": {"19.999"},
+			},
+			expected: Filter{},
+		}, {
+			test: "negative amount is ignored",
+			queryParams: map[string][]string{
+				"amount": {"-5"},
+			},
+			expected: Filter{},
 		}, {
 			test: "category is set in query params",
 			queryParams: map[string][]string{
@@ -47,6 +61,22 @@ func TestSetFilter(t *testing.T) {
 			expected: Filter{
 				Category: expectedCategory,
 			},
+		}, {
+			test: "category ids are set in query params",
+			queryParams: map[string][]string{
+				"category_id": {"1,5", "x", "9"},
+			},
+			expected: Filter{
+				CategoryIDs: []int{1, 5, 9},
+			},
+		}, {
+			test: "status is set in query params",
+			queryParams: map[string][]string{
+				"status": {StatusDraft},
+			},
+			expected: Filter{
+				Status: StatusDraft,
+			},
 		}, {
 			test: "date and category is set in query params",
 			queryParams: map[string][]string{
@@ -141,3 +171,36 @@ func TestSetPagination(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSort(t *testing.T) {
+	service := NewMiddlewareService()
+
+	tests := []struct {
+		test        string
+		queryParams map[string][]string
+		expected    Sort
+	}{
+		{
+			test:        "newest first by default",
+			queryParams: map[string][]string{},
+			expected:    Sort{By: SortByDate, Order: OrderDesc},
+		},
+		{
+			test: "sort by and order are set in query params",
+			queryParams: map[string][]string{
+				"sort_by": {SortByAmount},
+				"order":   {OrderAsc},
+			},
+			expected: Sort{By: SortByAmount, Order: OrderAsc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			sort := service.SetSort(tt.queryParams)
+			if !reflect.DeepEqual(sort, tt.expected) {
+				t.Errorf("expected: %v, got: %v", tt.expected, sort)
+			}
+		})
+	}
+}
@@ -5,15 +5,36 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound        = errors.New("transaction not found")
+	ErrAlreadyReviewed = errors.New("transaction has already been reviewed")
 )
 
+// AnySpender lifts the spender scope in GetAll. Only callers allowed to read
+// every spender's transactions may pass it.
+const AnySpender = -1
+
+// Repository scopes every query to a single spender so a caller can never
+// read or modify rows owned by someone else.
 type Repository interface {
-	GetAll(filter Filter, paginate Pagination) ([]Transaction, error)
+	GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error)
+	Get(spenderId int, id int) (Transaction, error)
 	Create(request CreateTransactionRequest) (CreateTransactionResponse, error)
-	GetExpenses(spenderId int) ([]Transaction, error)
-	GetSummary(spenderId int, txnTypes []string) ([]GetTransactionResponse, error)
-	UpdateExpense(transaction Transaction) error
-	DeleteExpense(id int) error
+	CreateTransfer(request TransferRequest) (TransferResponse, error)
+	GetExpenses(spenderId int, filter Filter, paginate Pagination, sort Sort) ([]GetTransactionResponse, error)
+	Summarize(spenderId int, query SummaryQuery) (Aggregate, error)
+	CashFlow(spenderId int, query BalanceQuery) (CashFlow, error)
+	CategoryTotals(spenderId int, query CategoryQuery) ([]CategoryTotal, error)
+	TopNotes(spenderId int, query CategoryQuery) ([]NoteTotal, error)
+	UpdateExpense(spenderId int, transaction Transaction) error
+	DeleteExpense(spenderId int, id int) error
+	Review(spenderId int, id int, status string) error
 }
 
 type repository struct {
@@ -24,27 +45,29 @@ func NewRepository(db *sql.DB) Repository {
 	return repository{db: db}
 }
 
-func (r repository) GetAll(filter Filter, paginate Pagination) ([]Transaction, error) {
+// baseAmount is a transaction's amount in the base currency of the spender
+// bound to $1, at the exchange rate effective on its date.
+const baseAmount = "convert_currency(amount, currency, (SELECT base_currency FROM spender WHERE id = $1), date)"
+
+// localDay is the day of a transaction on the spender's calendar, as CashFlow
+// buckets it.
+const localDay = "(date AT TIME ZONE (SELECT time_zone FROM spender WHERE id = $1))::date"
+
+const selectTransactionQuery = "SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, status, COALESCE(entry, ''), transfer_id FROM transaction"
+
+func (r repository) GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error) {
 	expenses := []Transaction{}
-	query := "SELECT id, date, amount, category, image_url, note, spender_id FROM transaction"
+	query := selectTransactionQuery
 	conditions := []string{}
 	args := []interface{}{}
 
-	if filter.Date != nil {
-		conditions = append(conditions, fmt.Sprintf("date = $%d", len(args)+1))
-		args = append(args, filter.Date)
+	if spenderId != AnySpender {
+		conditions = append(conditions, "spender_id = $1")
+		args = append(args, spenderId)
 	}
 
-	if filter.Amount != 0 {
-		conditions = append(conditions, fmt.Sprintf("amount = $%d", len(args)+1))
-		args = append(args, filter.Amount)
-	}
-	if filter.Category != "" {
-		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)+1))
-		args = append(args, filter.Category)
-	}
+	conditions, args = filterConditions(filter, conditions, args)
 
-	// Add WHERE clause if there are conditions
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
@@ -73,7 +96,7 @@ func (r repository) GetAll(filter Filter, paginate Pagination) ([]Transaction, e
 
 	for rows.Next() {
 		expense := Transaction{}
-		err = rows.Scan(&expense.ID, &expense.Date, &expense.Amount, &expense.Category, &expense.ImageUrl, &expense.Note, &expense.SpenderId)
+		err = rows.Scan(&expense.ID, &expense.Date, &expense.Amount, &expense.Category, &expense.CategoryID, &expense.AccountID, &expense.Currency, &expense.ImageUrl, &expense.Note, &expense.SpenderId, &expense.Status, &expense.Entry, &expense.TransferID)
 		if err != nil {
 			return nil, err
 		}
@@ -84,12 +107,43 @@ func (r repository) GetAll(filter Filter, paginate Pagination) ([]Transaction, e
 	return expenses, nil
 }
 
+// Get returns the spender's transaction with the id, or ErrNotFound.
+func (r repository) Get(spenderId int, id int) (Transaction, error) {
+	var t Transaction
+	err := r.db.QueryRow(selectTransactionQuery+" WHERE id = $1 AND spender_id = $2", id, spenderId).
+		Scan(&t.ID, &t.Date, &t.Amount, &t.Category, &t.CategoryID, &t.AccountID, &t.Currency, &t.ImageUrl, &t.Note, &t.SpenderId, &t.Status, &t.Entry, &t.TransferID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Transaction{}, ErrNotFound
+	}
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return t, nil
+}
+
+// Create records the transaction in request.Currency, or else in the
+// currency of its account or the spender's base currency.
 func (r repository) Create(request CreateTransactionRequest) (CreateTransactionResponse, error) {
+	status := request.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+
+	categoryID, category, err := r.resolveCategory(request.SpenderId, request.CategoryID, request.Category)
+	if err != nil {
+		return CreateTransactionResponse{}, err
+	}
+	if err := r.checkAccount(request.SpenderId, request.AccountID); err != nil {
+		return CreateTransactionResponse{}, err
+	}
+
 	var lastInsertId int
-	err := r.db.QueryRow(`
-		INSERT INTO transaction(date, amount, category, transaction_type, note, image_url, spender_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;
+	err = r.db.QueryRow(`
+		INSERT INTO transaction(date, amount, category, category_id, transaction_type, note, image_url, spender_id, status, account_id, currency) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		COALESCE(NULLIF($11, ''), (SELECT currency FROM account WHERE id = $10), (SELECT base_currency FROM spender WHERE id = $8))) RETURNING id;
 		`,
-		request.Date, request.Amount, request.Category, request.TxnType, request.Note, request.ImageUrl, request.SpenderId).Scan(&lastInsertId)
+		request.Date, request.Amount, category, categoryID, request.TxnType, request.Note, request.ImageUrl, request.SpenderId, status, request.AccountID, request.Currency).Scan(&lastInsertId)
 	if err != nil {
 
 		return CreateTransactionResponse{}, err
@@ -100,66 +154,420 @@ func (r repository) Create(request CreateTransactionRequest) (CreateTransactionR
 	}, nil
 }
 
-func (r repository) GetExpenses(spenderId int) ([]Transaction, error) {
-	return nil, nil
+// CreateTransfer inserts the debit and the credit of a transfer and links
+// them in one database transaction. Both accounts are locked against
+// deletion until it commits.
+func (r repository) CreateTransfer(request TransferRequest) (TransferResponse, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, currency FROM account WHERE spender_id = $1 AND id IN ($2, $3) FOR SHARE`,
+		request.SpenderId, request.FromAccountID, request.ToAccountID)
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	currencies := map[int]string{}
+	for rows.Next() {
+		var id int
+		var currency string
+		if err := rows.Scan(&id, &currency); err != nil {
+			rows.Close()
+			return TransferResponse{}, err
+		}
+		currencies[id] = currency
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return TransferResponse{}, err
+	}
+	if len(currencies) != 2 {
+		return TransferResponse{}, ErrInvalidAccount
+	}
+	if currencies[request.FromAccountID] != currencies[request.ToAccountID] {
+		return TransferResponse{}, ErrCurrencyMismatch
+	}
+
+	insert := `INSERT INTO transaction(date, amount, category, transaction_type, note, image_url, spender_id, status, account_id, entry, transfer_id, currency) ` +
+		`VALUES ($1, $2, '', 'transfer', $3, '', $4, 'confirmed', $5, $6, $7, $8) RETURNING id`
+	code := currencies[request.FromAccountID]
+	var response TransferResponse
+	err = tx.QueryRow(insert, request.Date, request.Amount, request.Note, request.SpenderId, request.FromAccountID, EntryDebit, nil, code).Scan(&response.DebitID)
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	err = tx.QueryRow(insert, request.Date, request.Amount, request.Note, request.SpenderId, request.ToAccountID, EntryCredit, response.DebitID, code).Scan(&response.CreditID)
+	if err != nil {
+		return TransferResponse{}, err
+	}
+	if _, err := tx.Exec(`UPDATE transaction SET transfer_id = $1 WHERE id = $2`, response.CreditID, response.DebitID); err != nil {
+		return TransferResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return TransferResponse{}, err
+	}
+	return response, nil
 }
 
-func (r repository) GetSummary(spenderId int, txnTypes []string) ([]GetTransactionResponse, error) {
-	query := `SELECT id, date, amount, category, image_url, note, spender_id, transaction_type FROM transaction WHERE spender_id = $1`
+// sortColumns whitelists the columns a Sort may order by.
+var sortColumns = map[string]string{
+	SortByDate:   "date",
+	SortByAmount: "amount",
+}
 
-	if len(txnTypes) < 2 {
-		for _, v := range txnTypes {
-			switch v {
-			case "income":
-				query = query + ` AND transaction_type = "income"`
-			case "expense":
-				query = query + ` AND transaction_type = "expense"`
-			}
+// GetExpenses lists the spender's expenses with every column, ordered by
+// sort with id as a tie-breaker so pages are stable.
+func (r repository) GetExpenses(spenderId int, filter Filter, paginate Pagination, sort Sort) ([]GetTransactionResponse, error) {
+	column, ok := sortColumns[sort.By]
+	if !ok || !sort.Valid() {
+		return nil, ErrInvalidSort
+	}
+	order := "ASC NULLS FIRST"
+	if sort.Order == OrderDesc {
+		order = "DESC NULLS LAST"
+	}
+
+	conditions, args := filterConditions(filter, []string{"spender_id = $1", "transaction_type = 'expense'"}, []interface{}{spenderId})
+	query := `SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, transaction_type, status FROM transaction WHERE ` +
+		strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d", column, order, strings.ToUpper(sort.Order), len(args)+1, len(args)+2)
+	args = append(args, paginate.ItemPerPage, (paginate.Page-1)*paginate.ItemPerPage)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expenses := []GetTransactionResponse{}
+	for rows.Next() {
+		var e GetTransactionResponse
+		err := rows.Scan(&e.ID, &e.Date, &e.Amount, &e.Category, &e.CategoryID, &e.AccountID, &e.Currency, &e.ImageUrl, &e.Note, &e.SpenderId, &e.TxnType, &e.Status)
+		if err != nil {
+			return nil, err
 		}
+		expenses = append(expenses, e)
 	}
 
-	stmt, err := r.db.Prepare(query)
+	return expenses, rows.Err()
+}
+
+// Summarize adds up the spender's confirmed transactions in the database,
+// in the spender's base currency, counting active days on the spender's
+// calendar.
+func (r repository) Summarize(spenderId int, query SummaryQuery) (Aggregate, error) {
+	conditions := []string{"spender_id = $1", "status = 'confirmed'"}
+	args := []interface{}{spenderId}
+
+	if query.TxnType != "" {
+		args = append(args, query.TxnType)
+		conditions = append(conditions, fmt.Sprintf("transaction_type = $%d", len(args)))
+	} else {
+		conditions = append(conditions, "transaction_type IN ('expense', 'income')")
+	}
+	if query.From != nil {
+		args = append(args, *query.From)
+		conditions = append(conditions, fmt.Sprintf("date >= $%d", len(args)))
+	}
+	if query.To != nil {
+		args = append(args, query.To.AddDate(0, 0, 1))
+		conditions = append(conditions, fmt.Sprintf("date < $%d", len(args)))
+	}
+	conditions, args = filterConditions(Filter{CategoryIDs: query.CategoryIDs}, conditions, args)
+
+	sqlQuery := `SELECT COALESCE(SUM(` + baseAmount + `), 0), COUNT(*), COUNT(DISTINCT ` + localDay + `), MIN(date), MAX(date) FROM transaction WHERE ` +
+		strings.Join(conditions, " AND ")
+
+	var a Aggregate
+	err := r.db.QueryRow(sqlQuery, args...).Scan(&a.TotalAmount, &a.Count, &a.ActiveDays, &a.First, &a.Last)
 	if err != nil {
-		return nil, errors.New("can't prepare statement")
+		return Aggregate{}, rateError(err)
 	}
 
-	rows, err := stmt.Query(spenderId)
+	return a, nil
+}
+
+// CashFlow buckets the spender's confirmed transactions, in the spender's
+// base currency, by the start of their period on the spender's calendar.
+// Transactions before query.From fall into a NULL bucket that sorts first,
+// so the running balance of the window starts from the opening balance.
+func (r repository) CashFlow(spenderId int, query BalanceQuery) (CashFlow, error) {
+	args := []interface{}{spenderId, query.Granularity}
+	bucket := "date_trunc($2, local)"
+	if query.From != nil {
+		args = append(args, query.From.Format("2006-01-02"))
+		bucket = fmt.Sprintf("CASE WHEN local < $%d::date THEN NULL ELSE %s END", len(args), bucket)
+	}
+	until := ""
+	if query.To != nil {
+		args = append(args, query.To.Format("2006-01-02"))
+		until = fmt.Sprintf(" AND t.date AT TIME ZONE s.time_zone < $%d::date + 1", len(args))
+	}
+
+	sqlQuery := `SELECT bucket, earned, spent, earned - spent, SUM(earned - spent) OVER (ORDER BY bucket NULLS FIRST) FROM (` +
+		`SELECT ` + bucket + ` AS bucket, ` +
+		`COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'income'), 0) AS earned, ` +
+		`COALESCE(SUM(amount) FILTER (WHERE transaction_type = 'expense'), 0) AS spent ` +
+		`FROM (SELECT t.date AT TIME ZONE s.time_zone AS local, convert_currency(t.amount, t.currency, s.base_currency, t.date) AS amount, t.transaction_type ` +
+		`FROM transaction t JOIN spender s ON s.id = t.spender_id ` +
+		`WHERE t.spender_id = $1 AND t.status = 'confirmed' AND t.date IS NOT NULL` + until + `) t ` +
+		`GROUP BY 1) b ORDER BY bucket NULLS FIRST`
+
+	rows, err := r.db.Query(sqlQuery, args...)
 	if err != nil {
-		return nil, errors.New("can't get transaction")
+		return CashFlow{}, rateError(err)
 	}
+	defer rows.Close()
 
-	responses := []GetTransactionResponse{}
+	flow := CashFlow{Buckets: []BalanceBucket{}}
 	for rows.Next() {
-		var response GetTransactionResponse
-		err := rows.Scan(
-			&response.ID, &response.Date, &response.Amount, &response.Category, &response.ImageUrl, &response.Note, &response.SpenderId, &response.TxnType,
-		)
-		if err != nil {
-			return nil, errors.New("can't scan rows")
+		var start *time.Time
+		var b BalanceBucket
+		if err := rows.Scan(&start, &b.Earned, &b.Spent, &b.Saved, &b.Balance); err != nil {
+			return CashFlow{}, rateError(err)
+		}
+		if start == nil {
+			flow.Opening = b.Balance
+			continue
+		}
+		b.Start = start.Format("2006-01-02")
+		flow.Buckets = append(flow.Buckets, b)
+	}
+
+	return flow, rateError(rows.Err())
+}
+
+// CategoryTotals adds up the spender's confirmed expenses and incomes per
+// category over the query's period and the previous one in a single pass.
+func (r repository) CategoryTotals(spenderId int, query CategoryQuery) ([]CategoryTotal, error) {
+	previous, _ := query.Previous()
+	conditions, args := reportConditions(spenderId, query, previous)
+	args = append(args, query.From)
+	current := len(args)
+
+	sqlQuery := fmt.Sprintf(`SELECT transaction_type, category, `+
+		`COALESCE(SUM(%[3]s) FILTER (WHERE date >= $%[1]d), 0), COUNT(*) FILTER (WHERE date >= $%[1]d), `+
+		`COALESCE(SUM(%[3]s) FILTER (WHERE date < $%[1]d), 0) FROM transaction WHERE %[2]s `+
+		`GROUP BY transaction_type, category ORDER BY transaction_type, category`, current, strings.Join(conditions, " AND "), baseAmount)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, rateError(err)
+	}
+	defer rows.Close()
+
+	totals := []CategoryTotal{}
+	for rows.Next() {
+		var c CategoryTotal
+		if err := rows.Scan(&c.TxnType, &c.Category, &c.Total, &c.Count, &c.PreviousTotal); err != nil {
+			return nil, rateError(err)
 		}
+		totals = append(totals, c)
+	}
+
+	return totals, rateError(rows.Err())
+}
+
+// TopNotes ranks the notes of the query's period by total, keeping the
+// first query.Top of each transaction type.
+func (r repository) TopNotes(spenderId int, query CategoryQuery) ([]NoteTotal, error) {
+	conditions, args := reportConditions(spenderId, query, query.From)
+	conditions = append(conditions, "note <> ''")
+	args = append(args, query.Top)
+
+	sqlQuery := fmt.Sprintf(`SELECT transaction_type, note, total, count FROM (`+
+		`SELECT transaction_type, note, SUM(%[3]s) AS total, COUNT(*) AS count, `+
+		`ROW_NUMBER() OVER (PARTITION BY transaction_type ORDER BY SUM(%[3]s) DESC, note) AS rank `+
+		`FROM transaction WHERE %[1]s GROUP BY transaction_type, note) n WHERE rank <= $%[2]d ORDER BY transaction_type, rank`,
+		strings.Join(conditions, " AND "), len(args), baseAmount)
 
-		responses = append(responses, response)
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, rateError(err)
 	}
-	return responses, nil
+	defer rows.Close()
+
+	notes := []NoteTotal{}
+	for rows.Next() {
+		var n NoteTotal
+		if err := rows.Scan(&n.TxnType, &n.Note, &n.Total, &n.Count); err != nil {
+			return nil, rateError(err)
+		}
+		notes = append(notes, n)
+	}
+
+	return notes, rateError(rows.Err())
 }
 
-func (r repository) UpdateExpense(transaction Transaction) error {
+// reportConditions scopes a category report to the spender's confirmed
+// expenses and incomes from since to the end of the query.
+func reportConditions(spenderId int, query CategoryQuery, since time.Time) ([]string, []interface{}) {
+	conditions := []string{
+		"spender_id = $1", "status = 'confirmed'", "transaction_type IN ('expense', 'income')",
+		"date >= $2", "date < $3",
+	}
+	args := []interface{}{spenderId, since, query.To.AddDate(0, 0, 1)}
+	return filterConditions(query.Filter, conditions, args)
+}
 
-	query := `UPDATE transaction SET date = $1, amount = $2, category = $3, image_url = $4, note = $5, spender_id = $6 WHERE id = $7`
-	_, err := r.db.Exec(query, transaction.Date, transaction.Amount, transaction.Category, transaction.ImageUrl, transaction.Note, transaction.SpenderId, transaction.ID)
+// UpdateExpense leaves transfers alone, reporting ErrNotFound for them, as
+// changing one leg would unbalance the other. An empty Currency keeps the
+// one recorded.
+func (r repository) UpdateExpense(spenderId int, transaction Transaction) error {
+	categoryID, category, err := r.resolveCategory(spenderId, transaction.CategoryID, transaction.Category)
 	if err != nil {
 		return err
 	}
+	if err := r.checkAccount(spenderId, transaction.AccountID); err != nil {
+		return err
+	}
 
-	return nil
+	query := `UPDATE transaction SET date = $1, amount = $2, category = $3, category_id = $4, image_url = $5, note = $6, account_id = $7, ` +
+		`currency = COALESCE(NULLIF($8, ''), currency) WHERE id = $9 AND spender_id = $10 AND transaction_type IS DISTINCT FROM 'transfer'`
+	result, err := r.db.Exec(query, transaction.Date, transaction.Amount, category, categoryID, transaction.ImageUrl, transaction.Note, transaction.AccountID, transaction.Currency, transaction.ID, spenderId)
+	if err != nil {
+		return err
+	}
+
+	return affectedOne(result)
 }
 
-func (r repository) DeleteExpense(id int) error {
-	query := `DELETE FROM transaction  WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+// DeleteExpense deletes both legs of a transfer, as the database cascades
+// through transfer_id.
+func (r repository) DeleteExpense(spenderId int, id int) error {
+	query := `DELETE FROM transaction WHERE id = $1 AND spender_id = $2`
+	result, err := r.db.Exec(query, id, spenderId)
+	if err != nil {
+		return err
+	}
+
+	return affectedOne(result)
+}
+
+// Review moves a draft to status. Reviewing it again with the same status
+// changes nothing; a transaction that was reviewed otherwise, or was not
+// created as a draft, reports ErrAlreadyReviewed.
+func (r repository) Review(spenderId int, id int, status string) error {
+	query := `UPDATE transaction SET status = $1 WHERE id = $2 AND spender_id = $3 AND status IN ('draft', $1)`
+	result, err := r.db.Exec(query, status, id, spenderId)
+	if err != nil {
+		return err
+	}
+	if err := affectedOne(result); err != ErrNotFound {
+		return err
+	}
+
+	var current string
+	err = r.db.QueryRow(`SELECT status FROM transaction WHERE id = $1 AND spender_id = $2`, id, spenderId).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
 	if err != nil {
 		return err
 	}
+	return ErrAlreadyReviewed
+}
+
+// resolveCategory finds the category a transaction is saved under: id when
+// the spender may use it, otherwise the category named name, the spender's
+// own before the system one. The returned name keeps the free text given
+// and falls back to the category's English name.
+func (r repository) resolveCategory(spenderId int, id *int, name string) (*int, string, error) {
+	if id == nil && strings.TrimSpace(name) == "" {
+		return nil, name, nil
+	}
+
+	var found int
+	var nameEN string
+	err := r.db.QueryRow(`SELECT id, name_en FROM category WHERE (spender_id IS NULL OR spender_id = $1) `+
+		`AND (id = $2 OR ($2 IS NULL AND (LOWER(name_en) = LOWER($3) OR name_th = $3))) ORDER BY spender_id NULLS LAST, id LIMIT 1`,
+		spenderId, id, strings.TrimSpace(name)).Scan(&found, &nameEN)
+	if errors.Is(err, sql.ErrNoRows) {
+		if id != nil {
+			return nil, "", ErrInvalidCategory
+		}
+		return nil, name, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if name == "" {
+		name = nameEN
+	}
+	return &found, name, nil
+}
+
+// checkAccount reports ErrInvalidAccount unless id is nil or one of the
+// spender's accounts.
+func (r repository) checkAccount(spenderId int, id *int) error {
+	if id == nil {
+		return nil
+	}
+
+	var found int
+	err := r.db.QueryRow(`SELECT id FROM account WHERE id = $1 AND spender_id = $2`, *id, spenderId).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidAccount
+	}
+	return err
+}
+
+// filterConditions adds the WHERE conditions of filter, numbering the
+// placeholders after args.
+func filterConditions(filter Filter, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if filter.Date != nil {
+		conditions = append(conditions, fmt.Sprintf("date = $%d", len(args)+1))
+		args = append(args, filter.Date)
+	}
+	if filter.Amount != 0 {
+		conditions = append(conditions, fmt.Sprintf("amount = $%d", len(args)+1))
+		args = append(args, filter.Amount)
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)+1))
+		args = append(args, filter.Category)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`category_id IN (WITH RECURSIVE tree AS (`+
+			`SELECT id FROM category WHERE id = ANY($%d) UNION ALL SELECT c.id FROM category c JOIN tree ON c.parent_id = tree.id`+
+			`) SELECT id FROM tree)`, len(args)+1))
+		args = append(args, pq.Array(filter.CategoryIDs))
+	}
+	if filter.AccountID != nil {
+		conditions = append(conditions, fmt.Sprintf("account_id = $%d", len(args)+1))
+		args = append(args, *filter.AccountID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)+1))
+		args = append(args, filter.Status)
+	}
+
+	return conditions, args
+}
+
+// rateError reports currency.ErrMissingRate for the error the database raises
+// when a conversion finds no exchange rate.
+func rateError(err error) error {
+	if currency.IsMissingRate(err) {
+		return currency.ErrMissingRate
+	}
+	return err
+}
+
+// affectedOne reports ErrNotFound when a statement scoped to a spender matched
+// no row, which covers both missing ids and rows owned by another spender.
+func affectedOne(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
 
 	return nil
 }
@@ -1,14 +1,24 @@
 package transaction
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
+// inBase matches an amount converted to the base currency of spender $1.
+const inBase = `convert_currency\(amount, currency, \(SELECT base_currency FROM spender WHERE id = \$1\), date\)`
+
+const onLocalDay = `\(date AT TIME ZONE \(SELECT time_zone FROM spender WHERE id = \$1\)\)::date`
+
 func TestGetAll_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
 	// Arrange
 	db, mock, err := sqlmock.New()
@@ -18,7 +28,7 @@ func TestGetAll_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
 	}
 
 	repo := NewRepository(db)
-	mock.ExpectPrepare(`SELECT id, date, amount, category, image_url, note, spender_id FROM transaction LIMIT \$1 OFFSET \$2`).WillReturnError(errors.New("error on prepare"))
+	mock.ExpectPrepare(`SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, status, COALESCE\(entry, ''\), transfer_id FROM transaction WHERE spender_id = \$1 LIMIT \$2 OFFSET \$3`).WillReturnError(errors.New("error on prepare"))
 	mockFilter := Filter{}
 
 	mockPaginate := Pagination{
@@ -26,7 +36,7 @@ func TestGetAll_ShouldReturnError_WhenErrorOnPrepare(t *testing.T) {
 		Page:        1,
 	}
 	// Act
-	_, err = repo.GetAll(mockFilter, mockPaginate)
+	_, err = repo.GetAll(1, mockFilter, mockPaginate)
 
 	// Assert
 	assert.Error(t, err)
@@ -41,7 +51,7 @@ func TestGetAll_ShouldReturnError_WhenErrorOnScan(t *testing.T) {
 	}
 
 	repo := NewRepository(db)
-	mock.ExpectPrepare(`SELECT id, date, amount, category, image_url, note, spender_id FROM transaction LIMIT \$1 OFFSET \$2`).ExpectQuery().WillReturnError(errors.New("error on scan"))
+	mock.ExpectPrepare(`SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, status, COALESCE\(entry, ''\), transfer_id FROM transaction WHERE spender_id = \$1 LIMIT \$2 OFFSET \$3`).ExpectQuery().WillReturnError(errors.New("error on scan"))
 	mockFilter := Filter{}
 
 	mockPaginate := Pagination{
@@ -49,7 +59,7 @@ func TestGetAll_ShouldReturnError_WhenErrorOnScan(t *testing.T) {
 		Page:        1,
 	}
 	// Act
-	_, err = repo.GetAll(mockFilter, mockPaginate)
+	_, err = repo.GetAll(1, mockFilter, mockPaginate)
 
 	// Assert
 	assert.Error(t, err)
@@ -64,14 +74,14 @@ func TestGetAll_ShouldReturnData_WhenCorrectInput(t *testing.T) {
 	}
 
 	repo := NewRepository(db)
-	mockRows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "image_url", "note", "spender_id"}).
-		AddRow("1", nil, "200.2", "category1", "urlOne", "note", "1").AddRow("2", nil, "400", "category2", "urlTwo", "note", "1")
-	mock.ExpectPrepare(`SELECT id, date, amount, category, image_url, note, spender_id FROM transaction WHERE date = \$1 AND amount = \$2 AND category = \$3 LIMIT \$4 OFFSET \$5`).ExpectQuery().WillReturnRows(mockRows)
+	mockRows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "status", "entry", "transfer_id"}).
+		AddRow("1", nil, "200.2", "category1", nil, nil, "THB", "urlOne", "note", "1", "confirmed", "", nil).AddRow("2", nil, "400", "category2", "3", nil, "THB", "urlTwo", "note", "1", "draft", "", nil)
+	mock.ExpectPrepare(`SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, status, COALESCE\(entry, ''\), transfer_id FROM transaction WHERE spender_id = \$1 AND date = \$2 AND amount = \$3 AND category = \$4 LIMIT \$5 OFFSET \$6`).ExpectQuery().WithArgs(1, sqlmock.AnyArg(), "10.50", "mock category", 1, 0).WillReturnRows(mockRows)
 
 	mockDate := time.Date(2020, time.April,
 		11, 21, 34, 01, 0, time.UTC)
 
-	mockAmount := 10.0
+	mockAmount := money.Amount(10_50)
 	mockCategory := "mock category"
 
 	mockFilter := Filter{
@@ -85,28 +95,34 @@ func TestGetAll_ShouldReturnData_WhenCorrectInput(t *testing.T) {
 		Page:        1,
 	}
 
+	categoryID := 3
 	expecteds := []Transaction{
 		{
 			ID:        1,
 			Date:      nil,
-			Amount:    200.2,
+			Amount:    200_20,
 			Category:  "category1",
+			Currency:  "THB",
 			ImageUrl:  "urlOne",
 			Note:      "note",
 			SpenderId: 1,
+			Status:    "confirmed",
 		},
 		{
-			ID:        2,
-			Date:      nil,
-			Amount:    400,
-			Category:  "category2",
-			ImageUrl:  "urlTwo",
-			Note:      "note",
-			SpenderId: 1,
+			ID:         2,
+			Date:       nil,
+			Amount:     400_00,
+			Category:   "category2",
+			CategoryID: &categoryID,
+			Currency:   "THB",
+			ImageUrl:   "urlTwo",
+			Note:       "note",
+			SpenderId:  1,
+			Status:     "draft",
 		},
 	}
 	// Act
-	expenses, err := repo.GetAll(mockFilter, mockPaginate)
+	expenses, err := repo.GetAll(1, mockFilter, mockPaginate)
 
 	// Assert
 	assert.NoError(t, err)
@@ -116,3 +132,569 @@ func TestGetAll_ShouldReturnData_WhenCorrectInput(t *testing.T) {
 		assert.Equal(t, expected, expenses[i])
 	}
 }
+
+func TestUpdateExpense_ShouldScopeToSpender(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{"updated own row", 1, nil},
+		{"not found when row belongs to another spender", 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+
+			repo := NewRepository(db)
+			mock.ExpectQuery(`SELECT id, name_en FROM category`).WithArgs(1, nil, "food").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name_en"}).AddRow(1, "Food"))
+			mock.ExpectExec(`UPDATE transaction SET date = \$1, amount = \$2, category = \$3, category_id = \$4, image_url = \$5, note = \$6, account_id = \$7, `+
+				`currency = COALESCE\(NULLIF\(\$8, ''\), currency\) WHERE id = \$9 AND spender_id = \$10 AND transaction_type IS DISTINCT FROM 'transfer'`).
+				WithArgs(nil, "100.00", "food", 1, "", "", nil, "", 5, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			// Act
+			err = repo.UpdateExpense(1, Transaction{ID: 5, Amount: 100_00, Category: "food", SpenderId: 2})
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreate_ShouldResolveCategory(t *testing.T) {
+	resolve := `SELECT id, name_en FROM category WHERE \(spender_id IS NULL OR spender_id = \$1\) ` +
+		`AND \(id = \$2 OR \(\$2 IS NULL AND \(LOWER\(name_en\) = LOWER\(\$3\) OR name_th = \$3\)\)\)`
+	insert := `INSERT INTO transaction\(date, amount, category, category_id, transaction_type, note, image_url, spender_id, status, account_id, currency\)`
+	categoryID := 3
+
+	tests := []struct {
+		name        string
+		request     CreateTransactionRequest
+		resolveArgs []driver.Value
+		resolved    *sqlmock.Rows
+		insertArgs  []driver.Value
+		expectedErr error
+	}{
+		{
+			name:        "free text in Thai",
+			request:     CreateTransactionRequest{Amount: 60_00, Category: "อาหาร", SpenderId: 1, TxnType: "expense"},
+			resolveArgs: []driver.Value{1, nil, "อาหาร"},
+			resolved:    sqlmock.NewRows([]string{"id", "name_en"}).AddRow(1, "Food"),
+			insertArgs:  []driver.Value{nil, "60.00", "อาหาร", 1, "expense", "", "", 1, StatusConfirmed, nil, ""},
+		},
+		{
+			name:        "category id fills in the name",
+			request:     CreateTransactionRequest{Amount: 60_00, CategoryID: &categoryID, SpenderId: 1, TxnType: "expense"},
+			resolveArgs: []driver.Value{1, 3, ""},
+			resolved:    sqlmock.NewRows([]string{"id", "name_en"}).AddRow(3, "Dining out"),
+			insertArgs:  []driver.Value{nil, "60.00", "Dining out", 3, "expense", "", "", 1, StatusConfirmed, nil, ""},
+		},
+		{
+			name:        "unknown free text stays uncategorised",
+			request:     CreateTransactionRequest{Amount: 60_00, Category: "snacks", SpenderId: 1, TxnType: "expense"},
+			resolveArgs: []driver.Value{1, nil, "snacks"},
+			resolved:    sqlmock.NewRows([]string{"id", "name_en"}),
+			insertArgs:  []driver.Value{nil, "60.00", "snacks", nil, "expense", "", "", 1, StatusConfirmed, nil, ""},
+		},
+		{
+			name:        "amount in another currency",
+			request:     CreateTransactionRequest{Amount: 12_50, Category: "Food", Currency: "USD", SpenderId: 1, TxnType: "expense"},
+			resolveArgs: []driver.Value{1, nil, "Food"},
+			resolved:    sqlmock.NewRows([]string{"id", "name_en"}).AddRow(1, "Food"),
+			insertArgs:  []driver.Value{nil, "12.50", "Food", 1, "expense", "", "", 1, StatusConfirmed, nil, "USD"},
+		},
+		{
+			name:        "category of another spender",
+			request:     CreateTransactionRequest{Amount: 60_00, CategoryID: &categoryID, SpenderId: 1, TxnType: "expense"},
+			resolveArgs: []driver.Value{1, 3, ""},
+			resolved:    sqlmock.NewRows([]string{"id", "name_en"}),
+			expectedErr: ErrInvalidCategory,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+			repo := NewRepository(db)
+			mock.ExpectQuery(resolve).WithArgs(tt.resolveArgs...).WillReturnRows(tt.resolved)
+			if tt.insertArgs != nil {
+				mock.ExpectQuery(insert).WithArgs(tt.insertArgs...).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+			}
+
+			// Act
+			_, err = repo.Create(tt.request)
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteExpense_ShouldScopeToSpender(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		expectedErr  error
+	}{
+		{"deleted own row", 1, nil},
+		{"not found when row belongs to another spender", 0, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+
+			repo := NewRepository(db)
+			mock.ExpectExec(`DELETE FROM transaction WHERE id = \$1 AND spender_id = \$2`).
+				WithArgs(5, 1).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			// Act
+			err = repo.DeleteExpense(1, 5)
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetAll_ShouldNotScopeBySpender_WhenAnySpender(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+
+	repo := NewRepository(db)
+	mockRows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "status", "entry", "transfer_id"}).
+		AddRow("1", nil, "200", "food", nil, nil, "THB", "", "", "1", "confirmed", "", nil).AddRow("2", nil, "400", "food", nil, nil, "THB", "", "", "2", "confirmed", "", nil)
+	mock.ExpectPrepare(`SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, status, COALESCE\(entry, ''\), transfer_id FROM transaction LIMIT \$1 OFFSET \$2`).ExpectQuery().WithArgs(10, 0).WillReturnRows(mockRows)
+
+	// Act
+	expenses, err := repo.GetAll(AnySpender, Filter{}, Pagination{ItemPerPage: 10, Page: 1})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, expenses, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAll_ShouldFilterByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	mockRows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "status", "entry", "transfer_id"})
+	mock.ExpectPrepare(`FROM transaction WHERE spender_id = \$1 AND status = \$2 LIMIT \$3 OFFSET \$4`).ExpectQuery().WithArgs(1, "draft", 10, 0).WillReturnRows(mockRows)
+
+	_, err = repo.GetAll(1, Filter{Status: StatusDraft}, Pagination{ItemPerPage: 10, Page: 1})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGet(t *testing.T) {
+	t.Run("returns the spender's transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		accountID := 4
+		rows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "status", "entry", "transfer_id"}).
+			AddRow(2, nil, "120.50", "Food", 1, accountID, "THB", "", "lunch", 1, "draft", "", nil)
+		mock.ExpectQuery(`FROM transaction WHERE id = \$1 AND spender_id = \$2`).WithArgs(2, 1).WillReturnRows(rows)
+
+		got, err := repo.Get(1, 2)
+
+		foodID := 1
+		assert.NoError(t, err)
+		assert.Equal(t, Transaction{ID: 2, Amount: 120_50, Category: "Food", CategoryID: &foodID, AccountID: &accountID,
+			Currency: "THB", Note: "lunch", SpenderId: 1, Status: StatusDraft}, got)
+	})
+
+	t.Run("not found when the row belongs to another spender", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectQuery(`FROM transaction WHERE id = \$1 AND spender_id = \$2`).WithArgs(2, 1).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.Get(1, 2)
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestReview(t *testing.T) {
+	update := `UPDATE transaction SET status = \$1 WHERE id = \$2 AND spender_id = \$3 AND status IN \('draft', \$1\)`
+	lookup := `SELECT status FROM transaction WHERE id = \$1 AND spender_id = \$2`
+
+	tests := []struct {
+		name     string
+		affected int64
+		current  []string
+		expected error
+	}{
+		{"reviews a draft", 1, nil, nil},
+		{"not found when row belongs to another spender", 0, []string{}, ErrNotFound},
+		{"conflict when already reviewed otherwise", 0, []string{"rejected"}, ErrAlreadyReviewed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+			repo := NewRepository(db)
+			mock.ExpectExec(update).WithArgs("confirmed", 2, 1).WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			if tt.current != nil {
+				rows := sqlmock.NewRows([]string{"status"})
+				for _, status := range tt.current {
+					rows.AddRow(status)
+				}
+				mock.ExpectQuery(lookup).WithArgs(2, 1).WillReturnRows(rows)
+			}
+
+			// Act
+			err = repo.Review(1, 2, StatusConfirmed)
+
+			// Assert
+			assert.Equal(t, tt.expected, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2024, time.April, 2, 9, 0, 0, 0, time.UTC)
+	last := time.Date(2024, time.April, 20, 18, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"sum", "count", "days", "min", "max"}).AddRow("800.50", 3, 2, first, last)
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(`+inBase+`\), 0\), COUNT\(\*\), COUNT\(DISTINCT `+onLocalDay+`\), MIN\(date\), MAX\(date\) FROM transaction `+
+		`WHERE spender_id = \$1 AND status = 'confirmed' AND transaction_type = \$2 AND date >= \$3 AND date < \$4`).
+		WithArgs(1, "expense", from, to.AddDate(0, 0, 1)).WillReturnRows(rows)
+
+	// Act
+	aggregate, err := repo.Summarize(1, SummaryQuery{TxnType: "expense", From: &from, To: &to})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Aggregate{TotalAmount: 800_50, Count: 3, ActiveDays: 2, First: &first, Last: &last}, aggregate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSummarize_ShouldReportMissingRate(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	mock.ExpectQuery(`SELECT COALESCE`).WithArgs(1).WillReturnError(&pq.Error{Code: "HJ001", Message: "no exchange rate for USD"})
+
+	// Act
+	_, err = repo.Summarize(1, SummaryQuery{})
+
+	// Assert
+	assert.Equal(t, currency.ErrMissingRate, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSummarize_ShouldIncludeSubcategories(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+
+	rows := sqlmock.NewRows([]string{"sum", "count", "days", "min", "max"}).AddRow(0, 0, 0, nil, nil)
+	mock.ExpectQuery(`FROM transaction WHERE spender_id = \$1 AND status = 'confirmed' AND transaction_type = \$2 AND category_id IN \(WITH RECURSIVE tree AS \(`+
+		`SELECT id FROM category WHERE id = ANY\(\$3\) (.+)\) SELECT id FROM tree\)`).
+		WithArgs(1, "expense", "{1}").WillReturnRows(rows)
+
+	// Act
+	aggregate, err := repo.Summarize(1, SummaryQuery{TxnType: "expense", CategoryIDs: []int{1}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Aggregate{}, aggregate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCashFlow(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"bucket", "earned", "spent", "saved", "balance"}).
+		AddRow(nil, 100, 0, 100, 100).
+		AddRow(february, 1000, 300, 700, 800).
+		AddRow(march, 0, 200, -200, 600)
+	mock.ExpectQuery(`SELECT bucket, earned, spent, earned - spent, SUM\(earned - spent\) OVER \(ORDER BY bucket NULLS FIRST\) FROM \(`+
+		`SELECT CASE WHEN local < \$3::date THEN NULL ELSE date_trunc\(\$2, local\) END AS bucket, (.+)`+
+		`convert_currency\(t.amount, t.currency, s.base_currency, t.date\) AS amount, t.transaction_type `+
+		`FROM transaction t JOIN spender s ON s.id = t.spender_id WHERE t.spender_id = \$1 AND t.status = 'confirmed' AND t.date IS NOT NULL `+
+		`AND t.date AT TIME ZONE s.time_zone < \$4::date \+ 1\) t GROUP BY 1\) b ORDER BY bucket NULLS FIRST`).
+		WithArgs(1, "month", "2024-02-01", "2024-03-31").WillReturnRows(rows)
+
+	// Act
+	flow, err := repo.CashFlow(1, BalanceQuery{From: &from, To: &to, Granularity: GranularityMonth})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, CashFlow{
+		Opening: 100_00,
+		Buckets: []BalanceBucket{
+			{Start: "2024-02-01", Earned: 1000_00, Spent: 300_00, Saved: 700_00, Balance: 800_00},
+			{Start: "2024-03-01", Spent: 200_00, Saved: -200_00, Balance: 600_00},
+		},
+	}, flow)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCategoryTotals(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC)
+	previous := time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"transaction_type", "category", "total", "count", "previous_total"}).
+		AddRow("expense", "food", 300, 6, 200)
+	mock.ExpectQuery(`SELECT transaction_type, category, COALESCE\(SUM\(`+inBase+`\) FILTER \(WHERE date >= \$5\), 0\), `+
+		`COUNT\(\*\) FILTER \(WHERE date >= \$5\), COALESCE\(SUM\(`+inBase+`\) FILTER \(WHERE date < \$5\), 0\) FROM transaction `+
+		`WHERE spender_id = \$1 AND status = 'confirmed' AND transaction_type IN \('expense', 'income'\) AND date >= \$2 AND date < \$3 `+
+		`AND category = \$4 GROUP BY transaction_type, category`).
+		WithArgs(1, previous, to.AddDate(0, 0, 1), "food", from).WillReturnRows(rows)
+
+	// Act
+	totals, err := repo.CategoryTotals(1, CategoryQuery{From: from, To: to, Filter: Filter{Category: "food"}, Top: 5})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []CategoryTotal{{TxnType: "expense", Category: "food", Total: 300_00, Count: 6, PreviousTotal: 200_00}}, totals)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTopNotes(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"transaction_type", "note", "total", "count"}).
+		AddRow("expense", "7-Eleven", 420, 12).
+		AddRow("income", "ACME", 30000, 1)
+	mock.ExpectQuery(`ROW_NUMBER\(\) OVER \(PARTITION BY transaction_type ORDER BY SUM\(`+inBase+`\) DESC, note\) AS rank `+
+		`FROM transaction WHERE (.+) AND date >= \$2 AND date < \$3 AND note <> '' GROUP BY transaction_type, note\) n WHERE rank <= \$4`).
+		WithArgs(1, from, to.AddDate(0, 0, 1), 3).WillReturnRows(rows)
+
+	// Act
+	notes, err := repo.TopNotes(1, CategoryQuery{From: from, To: to, Top: 3})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []NoteTotal{
+		{TxnType: "expense", Note: "7-Eleven", Total: 420_00, Count: 12},
+		{TxnType: "income", Note: "ACME", Total: 30000_00, Count: 1},
+	}, notes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpenses_ShouldIncludeSubcategories(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "transaction_type", "status"}).
+		AddRow(1, nil, 100, "Groceries", 2, nil, "THB", "", "", 1, "expense", "confirmed")
+	mock.ExpectQuery(`WHERE spender_id = \$1 AND transaction_type = 'expense' AND category_id IN \(WITH RECURSIVE tree AS \(`+
+		`SELECT id FROM category WHERE id = ANY\(\$2\) UNION ALL SELECT c.id FROM category c JOIN tree ON c.parent_id = tree.id\) SELECT id FROM tree\) ORDER BY`).
+		WithArgs(1, "{1,5}", 10, 0).WillReturnRows(rows)
+
+	// Act
+	expenses, err := repo.GetExpenses(1, Filter{CategoryIDs: []int{1, 5}}, Pagination{ItemPerPage: 10, Page: 1}, Sort{By: SortByDate, Order: OrderDesc})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, expenses, 1)
+	assert.Equal(t, 2, *expenses[0].CategoryID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpenses(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "date", "amount", "category", "category_id", "account_id", "currency", "image_url", "note", "spender_id", "transaction_type", "status"}).
+		AddRow(2, nil, 300, "food", nil, nil, "THB", "/api/v1/slips/a.png", "", 1, "expense", "confirmed").
+		AddRow(1, nil, 100, "food", nil, nil, "THB", "", "", 1, "expense", "draft")
+	mock.ExpectQuery(`SELECT id, date, amount, category, category_id, account_id, currency, image_url, note, spender_id, transaction_type, status FROM transaction `+
+		`WHERE spender_id = \$1 AND transaction_type = 'expense' AND category = \$2 ORDER BY amount DESC NULLS LAST, id DESC LIMIT \$3 OFFSET \$4`).
+		WithArgs(1, "food", 2, 2).WillReturnRows(rows)
+
+	// Act
+	expenses, err := repo.GetExpenses(1, Filter{Category: "food"}, Pagination{ItemPerPage: 2, Page: 2}, Sort{By: SortByAmount, Order: OrderDesc})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []GetTransactionResponse{
+		{ID: 2, Amount: 300_00, Category: "food", Currency: "THB", ImageUrl: "/api/v1/slips/a.png", SpenderId: 1, TxnType: "expense", Status: "confirmed"},
+		{ID: 1, Amount: 100_00, Category: "food", Currency: "THB", SpenderId: 1, TxnType: "expense", Status: "draft"},
+	}, expenses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSummarize_ShouldExcludeTransfers_WhenNoTxnType(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+
+	rows := sqlmock.NewRows([]string{"sum", "count", "days", "min", "max"}).AddRow(0, 0, 0, nil, nil)
+	mock.ExpectQuery(`FROM transaction WHERE spender_id = \$1 AND status = 'confirmed' AND transaction_type IN \('expense', 'income'\)$`).
+		WithArgs(1).WillReturnRows(rows)
+
+	// Act
+	_, err = repo.Summarize(1, SummaryQuery{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate_ShouldRejectAccountOfAnotherSpender(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	accountID := 4
+	mock.ExpectQuery(`SELECT id FROM account WHERE id = \$1 AND spender_id = \$2`).WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Act
+	_, err = repo.Create(CreateTransactionRequest{Amount: 60_00, AccountID: &accountID, SpenderId: 1, TxnType: "expense"})
+
+	// Assert
+	assert.Equal(t, ErrInvalidAccount, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTransfer(t *testing.T) {
+	accounts := `SELECT id, currency FROM account WHERE spender_id = \$1 AND id IN \(\$2, \$3\) FOR SHARE`
+	insert := `INSERT INTO transaction\(date, amount, category, transaction_type, note, image_url, spender_id, status, account_id, entry, transfer_id, currency\)`
+	request := TransferRequest{FromAccountID: 4, ToAccountID: 5, Amount: 1000_00, Note: "top up", SpenderId: 1}
+
+	t.Run("inserts linked debit and credit", func(t *testing.T) {
+		// Arrange
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectBegin()
+		mock.ExpectQuery(accounts).WithArgs(1, 4, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "currency"}).AddRow(4, "THB").AddRow(5, "THB"))
+		mock.ExpectQuery(insert).WithArgs(nil, "1000.00", "top up", 1, 4, EntryDebit, nil, "THB").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectQuery(insert).WithArgs(nil, "1000.00", "top up", 1, 5, EntryCredit, 10, "THB").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+		mock.ExpectExec(`UPDATE transaction SET transfer_id = \$1 WHERE id = \$2`).WithArgs(11, 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// Act
+		response, err := repo.CreateTransfer(request)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, TransferResponse{DebitID: 10, CreditID: 11}, response)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	tests := []struct {
+		name        string
+		rows        *sqlmock.Rows
+		expectedErr error
+	}{
+		{"account of another spender", sqlmock.NewRows([]string{"id", "currency"}).AddRow(4, "THB"), ErrInvalidAccount},
+		{"accounts in different currencies", sqlmock.NewRows([]string{"id", "currency"}).AddRow(4, "THB").AddRow(5, "USD"), ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+			}
+			repo := NewRepository(db)
+			mock.ExpectBegin()
+			mock.ExpectQuery(accounts).WithArgs(1, 4, 5).WillReturnRows(tt.rows)
+			mock.ExpectRollback()
+
+			// Act
+			_, err = repo.CreateTransfer(request)
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,6 +2,12 @@ package transaction
 
 import (
 	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/currency"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
 )
 
 type service struct {
@@ -9,21 +15,25 @@ type service struct {
 }
 
 type Service interface {
-	GetAll(filter Filter, pagination Pagination) ([]Transaction, error)
+	GetAll(spenderId int, filter Filter, pagination Pagination) ([]Transaction, error)
 	Create(request CreateTransactionRequest) (CreateTransactionResponse, error)
-	GetExpenses(spenderId int) ([]Transaction, error)
-	GetSummary(spenderId int, txnType string) (SummaryResponse, error)
-	GetBalance(spenderId int) (BalanceResponse, error)
-	UpdateExpense(transaction Transaction) error
-	DeleteExpense(id int) error
+	CreateTransfer(request TransferRequest) (TransferResponse, error)
+	GetExpenses(spenderId int, filter Filter, pagination Pagination, sort Sort) ([]GetTransactionResponse, error)
+	GetSummary(spenderId int, query SummaryQuery) (SummaryResponse, error)
+	GetBalance(spenderId int, query BalanceQuery) (BalanceResponse, error)
+	GetCategoryReport(spenderId int, query CategoryQuery) (CategoryReport, error)
+	UpdateExpense(spenderId int, transaction Transaction) error
+	DeleteExpense(spenderId int, id int) error
+	Confirm(spenderId int, id int) error
+	Reject(spenderId int, id int) error
 }
 
 func NewService(repository Repository) Service {
 	return service{repository: repository}
 }
 
-func (s service) GetAll(filter Filter, paginate Pagination) ([]Transaction, error) {
-	result, err := s.repository.GetAll(filter, paginate)
+func (s service) GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error) {
+	result, err := s.repository.GetAll(spenderId, filter, paginate)
 	if err != nil {
 		return nil, err
 	}
@@ -31,8 +41,24 @@ func (s service) GetAll(filter Filter, paginate Pagination) ([]Transaction, erro
 	return result, nil
 }
 
+// Create records a single transaction. Transfers come in pairs and are
+// only created through CreateTransfer.
 func (s service) Create(request CreateTransactionRequest) (CreateTransactionResponse, error) {
+	if request.TxnType == TxnTypeTransfer {
+		return CreateTransactionResponse{}, ErrInvalidTxnType
+	}
+	if request.Amount < 0 {
+		return CreateTransactionResponse{}, ErrInvalidAmount
+	}
+	request.Currency = currency.Normalize(request.Currency)
+	if request.Currency != "" && !currency.Valid(request.Currency) {
+		return CreateTransactionResponse{}, currency.ErrInvalidCurrency
+	}
+
 	result, err := s.repository.Create(request)
+	if errors.Is(err, ErrInvalidCategory) || errors.Is(err, ErrInvalidAccount) {
+		return CreateTransactionResponse{}, err
+	}
 	if err != nil {
 		return CreateTransactionResponse{}, errors.New("can't create transaction")
 	}
@@ -40,53 +66,244 @@ func (s service) Create(request CreateTransactionRequest) (CreateTransactionResp
 	return result, nil
 }
 
-func (s service) GetBalance(spenderId int) (BalanceResponse, error) {
-	txn, err := s.repository.GetSummary(spenderId, []string{
-		"income", "expense",
-	})
+// CreateTransfer moves money between two of the spender's accounts in the
+// same currency. It does not change what the spender earned or spent.
+func (s service) CreateTransfer(request TransferRequest) (TransferResponse, error) {
+	if request.Amount <= 0 || request.FromAccountID == request.ToAccountID {
+		return TransferResponse{}, ErrInvalidTransfer
+	}
+
+	return s.repository.CreateTransfer(request)
+}
+
+// GetBalance reports what the spender earned, spent and saved in each
+// period of the query, by month unless another granularity is asked for.
+// The totals cover the whole range.
+func (s service) GetBalance(spenderId int, query BalanceQuery) (BalanceResponse, error) {
+	if query.Granularity == "" {
+		query.Granularity = GranularityMonth
+	}
+	switch query.Granularity {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityYear:
+	default:
+		return BalanceResponse{}, ErrInvalidGranularity
+	}
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return BalanceResponse{}, ErrInvalidRange
+	}
+
+	flow, err := s.repository.CashFlow(spenderId, query)
+	if errors.Is(err, currency.ErrMissingRate) {
+		return BalanceResponse{}, err
+	}
 	if err != nil {
 		return BalanceResponse{}, errors.New("can't get balance")
 	}
 
-	totalAmountEarned := 0.0
-	totalAmountSpended := 0.0
-	for _, v := range txn {
-		if v.TxnType == "income" {
-			totalAmountEarned += v.Amount
+	balance := BalanceResponse{
+		Granularity:    query.Granularity,
+		OpeningBalance: flow.Opening,
+		Buckets:        flow.Buckets,
+	}
+	if balance.Buckets == nil {
+		balance.Buckets = []BalanceBucket{}
+	}
+	for _, b := range flow.Buckets {
+		balance.TotalAmountEarned += b.Earned
+		balance.TotalAmountSpend += b.Spent
+	}
+	balance.TotalAmountSaved = balance.TotalAmountEarned - balance.TotalAmountSpend
+	return balance, nil
+}
+
+// GetCategoryReport breaks the spender's confirmed expenses and incomes of
+// the query's period down by category, compares each with the previous
+// period of the same length and lists the notes with the largest totals.
+// The report's own range replaces any date or status filter.
+func (s service) GetCategoryReport(spenderId int, query CategoryQuery) (CategoryReport, error) {
+	if query.Top == 0 {
+		query.Top = DefaultTop
+	}
+	if query.Top < 1 || query.Top > MaxTop {
+		return CategoryReport{}, ErrInvalidTop
+	}
+	if query.From.After(query.To) {
+		return CategoryReport{}, ErrInvalidRange
+	}
+	query.Filter.Date = nil
+	query.Filter.Status = ""
+
+	totals, err := s.repository.CategoryTotals(spenderId, query)
+	if err != nil {
+		return CategoryReport{}, err
+	}
+	notes, err := s.repository.TopNotes(spenderId, query)
+	if err != nil {
+		return CategoryReport{}, err
+	}
+
+	previousFrom, previousTo := query.Previous()
+	report := CategoryReport{
+		From:         query.From.Format("2006-01-02"),
+		To:           query.To.Format("2006-01-02"),
+		PreviousFrom: previousFrom.Format("2006-01-02"),
+		PreviousTo:   previousTo.Format("2006-01-02"),
+		Expense:      CategoryBreakdown{Categories: []CategoryShare{}, TopNotes: []NoteTotal{}},
+		Income:       CategoryBreakdown{Categories: []CategoryShare{}, TopNotes: []NoteTotal{}},
+	}
+	breakdowns := map[string]*CategoryBreakdown{"expense": &report.Expense, "income": &report.Income}
+
+	for _, t := range totals {
+		b, ok := breakdowns[t.TxnType]
+		if !ok {
+			continue
+		}
+		b.Total += t.Total
+		b.Count += t.Count
+		b.PreviousTotal += t.PreviousTotal
+		b.Categories = append(b.Categories, CategoryShare{
+			Category:      t.Category,
+			Total:         t.Total,
+			Count:         t.Count,
+			PreviousTotal: t.PreviousTotal,
+			Delta:         t.Total - t.PreviousTotal,
+			Change:        change(t.Total, t.PreviousTotal),
+		})
+	}
+	for _, n := range notes {
+		if b, ok := breakdowns[n.TxnType]; ok {
+			b.TopNotes = append(b.TopNotes, n)
 		}
-		if v.TxnType == "expense" {
-			totalAmountSpended += v.Amount
+	}
+
+	for _, b := range breakdowns {
+		b.Delta = b.Total - b.PreviousTotal
+		b.Change = change(b.Total, b.PreviousTotal)
+		for i := range b.Categories {
+			if b.Total > 0 {
+				b.Categories[i].Percentage = round2(float64(b.Categories[i].Total) / float64(b.Total) * 100)
+			}
 		}
+		sort.SliceStable(b.Categories, func(i, j int) bool {
+			return b.Categories[i].Total > b.Categories[j].Total
+		})
 	}
 
-	totalBalance := totalAmountEarned - totalAmountSpended
-	return BalanceResponse{
-		TotalAmountEarned: totalAmountEarned,
-		TotalAmountSpend:  totalAmountSpended,
-		TotalAmountSaved:  totalBalance,
-	}, nil
+	return report, nil
 }
 
-func (s service) UpdateExpense(transaction Transaction) error {
-	err := s.repository.UpdateExpense(transaction)
+// change is the percentage change from previous to current, nil when there
+// is nothing to compare with.
+func change(current, previous money.Amount) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	c := round2(float64(current-previous) / float64(previous) * 100)
+	return &c
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// UpdateExpense keeps the recorded currency unless another is given.
+func (s service) UpdateExpense(spenderId int, transaction Transaction) error {
+	if transaction.Amount < 0 {
+		return ErrInvalidAmount
+	}
+	transaction.Currency = currency.Normalize(transaction.Currency)
+	if transaction.Currency != "" && !currency.Valid(transaction.Currency) {
+		return currency.ErrInvalidCurrency
+	}
+
+	err := s.repository.UpdateExpense(spenderId, transaction)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s service) DeleteExpense(id int) error {
-	err := s.repository.DeleteExpense(id)
+func (s service) DeleteExpense(spenderId int, id int) error {
+	err := s.repository.DeleteExpense(spenderId, id)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s service) GetSummary(spenderId int, txnType string) (SummaryResponse, error) {
-	return SummaryResponse{}, nil
+// Confirm makes a draft count towards the spender's summaries.
+func (s service) Confirm(spenderId int, id int) error {
+	return s.repository.Review(spenderId, id, StatusConfirmed)
+}
+
+// Reject keeps a draft out of the spender's summaries for good.
+func (s service) Reject(spenderId int, id int) error {
+	return s.repository.Review(spenderId, id, StatusRejected)
+}
+
+// GetSummary totals the spender's confirmed transactions. The average is
+// over the calendar days of the range, or over the days with a transaction
+// when query.Average is AverageActiveDays.
+func (s service) GetSummary(spenderId int, query SummaryQuery) (SummaryResponse, error) {
+	if query.TxnType != "" && query.TxnType != "expense" && query.TxnType != "income" {
+		return SummaryResponse{}, ErrInvalidTxnType
+	}
+	if query.Average == "" {
+		query.Average = AverageCalendarDays
+	}
+	if query.Average != AverageCalendarDays && query.Average != AverageActiveDays {
+		return SummaryResponse{}, ErrInvalidAverage
+	}
+	if query.From != nil && query.To != nil && query.From.After(*query.To) {
+		return SummaryResponse{}, ErrInvalidRange
+	}
+
+	aggregate, err := s.repository.Summarize(spenderId, query)
+	if err != nil {
+		return SummaryResponse{}, err
+	}
+
+	days := aggregate.ActiveDays
+	if query.Average == AverageCalendarDays {
+		days = calendarDays(query, aggregate)
+	}
+
+	summary := SummaryResponse{
+		TotalAmount: aggregate.TotalAmount,
+		Total:       aggregate.Count,
+	}
+	if days > 0 {
+		summary.AvgAmountPerDay = aggregate.TotalAmount.Div(days)
+	}
+	return summary, nil
+}
+
+// calendarDays counts the days of the query's range, taking an open end
+// from the first or last transaction.
+func calendarDays(query SummaryQuery, aggregate Aggregate) int {
+	start, end := query.From, query.To
+	if start == nil {
+		start = aggregate.First
+	}
+	if end == nil {
+		end = aggregate.Last
+	}
+	if start == nil || end == nil {
+		return 0
+	}
+
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours()/24) + 1
 }
 
-func (s service) GetExpenses(spenderId int) ([]Transaction, error) {
-	return make([]Transaction, 0), nil
+func (s service) GetExpenses(spenderId int, filter Filter, pagination Pagination, sort Sort) ([]GetTransactionResponse, error) {
+	if !sort.Valid() {
+		return nil, ErrInvalidSort
+	}
+
+	return s.repository.GetExpenses(spenderId, filter, pagination, sort)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -13,8 +14,8 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) GetAll(filter Filter, paginate Pagination) ([]Transaction, error) {
-	args := m.Called(filter, paginate)
+func (m *MockRepository) GetAll(spenderId int, filter Filter, paginate Pagination) ([]Transaction, error) {
+	args := m.Called(spenderId, filter, paginate)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -24,22 +25,53 @@ func (m *MockRepository) GetAll(filter Filter, paginate Pagination) ([]Transacti
 func (m *MockRepository) Create(request CreateTransactionRequest) (CreateTransactionResponse, error) {
 	return CreateTransactionResponse{}, nil
 }
-func (m *MockRepository) GetExpenses(spenderId int) ([]Transaction, error) {
-	return nil, nil
+func (m *MockRepository) CreateTransfer(request TransferRequest) (TransferResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(TransferResponse), args.Error(1)
 }
-func (m *MockRepository) GetSummary(spenderId int, txnTypes []string) ([]GetTransactionResponse, error) {
-	args := m.Called(spenderId, txnTypes)
+func (m *MockRepository) GetExpenses(spenderId int, filter Filter, paginate Pagination, sort Sort) ([]GetTransactionResponse, error) {
+	args := m.Called(spenderId, filter, paginate, sort)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]GetTransactionResponse), args.Error(1)
 }
-func (m *MockRepository) UpdateExpense(transaction Transaction) error {
+func (m *MockRepository) Summarize(spenderId int, query SummaryQuery) (Aggregate, error) {
+	args := m.Called(spenderId, query)
+	return args.Get(0).(Aggregate), args.Error(1)
+}
+func (m *MockRepository) CashFlow(spenderId int, query BalanceQuery) (CashFlow, error) {
+	args := m.Called(spenderId, query)
+	return args.Get(0).(CashFlow), args.Error(1)
+}
+func (m *MockRepository) CategoryTotals(spenderId int, query CategoryQuery) ([]CategoryTotal, error) {
+	args := m.Called(spenderId, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CategoryTotal), args.Error(1)
+}
+func (m *MockRepository) TopNotes(spenderId int, query CategoryQuery) ([]NoteTotal, error) {
+	args := m.Called(spenderId, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]NoteTotal), args.Error(1)
+}
+func (m *MockRepository) Get(spenderId int, id int) (Transaction, error) {
+	args := m.Called(spenderId, id)
+	return args.Get(0).(Transaction), args.Error(1)
+}
+func (m *MockRepository) UpdateExpense(spenderId int, transaction Transaction) error {
 	return nil
 }
-func (m *MockRepository) DeleteExpense(id int) error {
+func (m *MockRepository) DeleteExpense(spenderId int, id int) error {
 	return nil
 }
+func (m *MockRepository) Review(spenderId int, id int, status string) error {
+	args := m.Called(spenderId, id, status)
+	return args.Error(0)
+}
 
 func TestService_GetAll_ShouldReturnError_WhenRepositoryReturnsError(t *testing.T) {
 	// Arrange
@@ -47,7 +79,7 @@ func TestService_GetAll_ShouldReturnError_WhenRepositoryReturnsError(t *testing.
 	service := NewService(mockRepo)
 
 	mockDate := time.Date(2020, time.April, 11, 21, 34, 01, 0, time.UTC)
-	mockAmount := 200.2
+	mockAmount := money.Amount(200_20)
 	mockCategory := "category1"
 
 	mockFilter := Filter{
@@ -62,10 +94,10 @@ func TestService_GetAll_ShouldReturnError_WhenRepositoryReturnsError(t *testing.
 	}
 
 	expectedError := errors.New("repository error")
-	mockRepo.On("GetAll", mockFilter, mockPaginate).Return(nil, expectedError)
+	mockRepo.On("GetAll", 1, mockFilter, mockPaginate).Return(nil, expectedError)
 
 	// Act
-	expenses, err := service.GetAll(mockFilter, mockPaginate)
+	expenses, err := service.GetAll(1, mockFilter, mockPaginate)
 
 	// Assert
 	assert.Error(t, err)
@@ -80,7 +112,7 @@ func TestService_GetAll_ShouldReturnData_WhenRepositoryReturnsData(t *testing.T)
 	service := NewService(mockRepo)
 
 	mockDate := time.Date(2020, time.April, 11, 21, 34, 01, 0, time.UTC)
-	mockAmount := 200.2
+	mockAmount := money.Amount(200_20)
 	mockCategory := "category1"
 
 	mockFilter := Filter{
@@ -99,10 +131,10 @@ func TestService_GetAll_ShouldReturnData_WhenRepositoryReturnsData(t *testing.T)
 		{ID: 2, Date: &mockDate, Amount: mockAmount, Category: mockCategory, ImageUrl: "urlOne", Note: "note", SpenderId: 1},
 	}
 
-	mockRepo.On("GetAll", mockFilter, mockPaginate).Return(expectedExpenses, nil)
+	mockRepo.On("GetAll", 1, mockFilter, mockPaginate).Return(expectedExpenses, nil)
 
 	// Act
-	expenses, err := service.GetAll(mockFilter, mockPaginate)
+	expenses, err := service.GetAll(1, mockFilter, mockPaginate)
 
 	// Assert
 	assert.NoError(t, err)
@@ -110,69 +142,99 @@ func TestService_GetAll_ShouldReturnData_WhenRepositoryReturnsData(t *testing.T)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestService_Review_ShouldSetStatus(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+	mockRepo.On("Review", 1, 2, StatusConfirmed).Return(nil)
+	mockRepo.On("Review", 1, 3, StatusRejected).Return(ErrAlreadyReviewed)
+
+	// Act
+	confirmErr := service.Confirm(1, 2)
+	rejectErr := service.Reject(1, 3)
+
+	// Assert
+	assert.NoError(t, confirmErr)
+	assert.Equal(t, ErrAlreadyReviewed, rejectErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_GetExpenses(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+	filter := Filter{Category: "food"}
+	paginate := Pagination{ItemPerPage: 10, Page: 1}
+	sort := Sort{By: SortByAmount, Order: OrderDesc}
+	expected := []GetTransactionResponse{{ID: 1, Amount: 100_00, TxnType: "expense"}}
+	mockRepo.On("GetExpenses", 1, filter, paginate, sort).Return(expected, nil)
+
+	// Act
+	expenses, err := service.GetExpenses(1, filter, paginate, sort)
+	_, invalidErr := service.GetExpenses(1, filter, paginate, Sort{By: "id", Order: OrderAsc})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, expected, expenses)
+	assert.Equal(t, ErrInvalidSort, invalidErr)
+	mockRepo.AssertExpectations(t)
+}
+
 // TestGetSummary
 func TestService_GetSummary_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 	// Arrange
 	date1 := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.Now().Location())
-	date2 := time.Date(2024, time.April, 2, 0, 0, 0, 0, time.Now().Location())
 	date3 := time.Date(2024, time.April, 3, 0, 0, 0, 0, time.Now().Location())
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.Now().Location())
+	to := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.Now().Location())
 
 	tests := []struct {
 		name           string
 		spenderId      int
-		txnType        string
-		transactions   []GetTransactionResponse
+		query          SummaryQuery
+		aggregate      Aggregate
 		expectedResult SummaryResponse
 		expectedError  error
 	}{
 		{
 			name:      "case multiple txn",
 			spenderId: 1,
-			txnType:   "expense",
-			transactions: []GetTransactionResponse{
-				{
-					ID:        1,
-					Date:      &date1,
-					Amount:    200,
-					Category:  "",
-					ImageUrl:  "",
-					Note:      "",
-					SpenderId: 1,
-					TxnType:   "expense",
-				},
-				{
-					ID:        2,
-					Date:      &date2,
-					Amount:    200,
-					Category:  "",
-					ImageUrl:  "",
-					Note:      "",
-					SpenderId: 1,
-					TxnType:   "expense",
-				},
-				{
-					ID:        3,
-					Date:      &date3,
-					Amount:    400,
-					Category:  "",
-					ImageUrl:  "",
-					Note:      "",
-					SpenderId: 1,
-					TxnType:   "expense",
-				},
-			},
+			query:     SummaryQuery{TxnType: "expense"},
+			aggregate: Aggregate{TotalAmount: 800_00, Count: 3, ActiveDays: 3, First: &date1, Last: &date3},
 			expectedResult: SummaryResponse{
-				TotalAmount:     800,
-				AvgAmountPerDay: 266.6666666666667,
+				TotalAmount:     800_00,
+				AvgAmountPerDay: 266_67,
 				Total:           3,
 			},
 			expectedError: nil, // Assuming no error for no summaries
 		},
+		{
+			name:      "average over calendar days in range",
+			spenderId: 1,
+			query:     SummaryQuery{TxnType: "expense", From: &from, To: &to},
+			aggregate: Aggregate{TotalAmount: 800_00, Count: 3, ActiveDays: 3, First: &date1, Last: &date3},
+			expectedResult: SummaryResponse{
+				TotalAmount:     800_00,
+				AvgAmountPerDay: 80_00,
+				Total:           3,
+			},
+		},
+		{
+			name:      "average over days with activity",
+			spenderId: 1,
+			query:     SummaryQuery{TxnType: "income", From: &from, To: &to, Average: AverageActiveDays},
+			aggregate: Aggregate{TotalAmount: 900_00, Count: 3, ActiveDays: 2, First: &date1, Last: &date3},
+			expectedResult: SummaryResponse{
+				TotalAmount:     900_00,
+				AvgAmountPerDay: 450_00,
+				Total:           3,
+			},
+		},
 		{
 			name:           "empty tnx",
 			spenderId:      1,
-			txnType:        "expense",
-			transactions:   []GetTransactionResponse{},
+			query:          SummaryQuery{TxnType: "expense"},
+			aggregate:      Aggregate{},
 			expectedResult: SummaryResponse{},
 			expectedError:  nil, // Assuming no error for no summaries
 		},
@@ -184,9 +246,13 @@ func TestService_GetSummary_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 			mockRepo := new(MockRepository)
 			service := NewService(mockRepo)
 
-			mockRepo.On("GetSummary", tt.spenderId, []string{tt.txnType}).Return(tt.transactions, nil)
+			expectedQuery := tt.query
+			if expectedQuery.Average == "" {
+				expectedQuery.Average = AverageCalendarDays
+			}
+			mockRepo.On("Summarize", tt.spenderId, expectedQuery).Return(tt.aggregate, nil)
 
-			result, err := service.GetSummary(tt.spenderId, tt.txnType)
+			result, err := service.GetSummary(tt.spenderId, tt.query)
 
 			// Assert
 			assert.Equal(t, tt.expectedError, err)
@@ -195,16 +261,235 @@ func TestService_GetSummary_ShouldSuccess_WhenCorrectInput(t *testing.T) {
 	}
 }
 
+func TestService_GetSummary_ShouldReturnError_WhenInvalidQuery(t *testing.T) {
+	from := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    SummaryQuery
+		expected error
+	}{
+		{"unknown txn type", SummaryQuery{TxnType: "transfer"}, ErrInvalidTxnType},
+		{"unknown average", SummaryQuery{Average: "weekly"}, ErrInvalidAverage},
+		{"from after to", SummaryQuery{From: &from, To: &to}, ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockRepository)
+			service := NewService(mockRepo)
+
+			// Act
+			_, err := service.GetSummary(1, tt.query)
+
+			// Assert
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "Summarize", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestService_PassCoverage(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewService(mockRepo)
 
+	mockRepo.On("CashFlow", 0, BalanceQuery{Granularity: GranularityMonth}).Return(CashFlow{}, nil)
+
 	createRes, _ := service.Create(CreateTransactionRequest{})
-	_, _ = service.GetExpenses(0)
-	balRes, _ := service.GetBalance(0)
-	_ = service.UpdateExpense(Transaction{})
-	_ = service.DeleteExpense(0)
+	balRes, _ := service.GetBalance(0, BalanceQuery{})
+	_ = service.UpdateExpense(0, Transaction{})
+	_ = service.DeleteExpense(0, 0)
 
 	assert.Equal(t, createRes, CreateTransactionResponse{})
-	assert.Equal(t, balRes, BalanceResponse{})
+	assert.Equal(t, balRes, BalanceResponse{Granularity: GranularityMonth, Buckets: []BalanceBucket{}})
+}
+
+func TestService_GetBalance_ShouldTotalBuckets(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	query := BalanceQuery{From: &from, Granularity: GranularityWeek}
+	mockRepo.On("CashFlow", 1, query).Return(CashFlow{
+		Opening: 100_00,
+		Buckets: []BalanceBucket{
+			{Start: "2024-01-01", Earned: 1000_00, Spent: 250_00, Saved: 750_00, Balance: 850_00},
+			{Start: "2024-01-15", Earned: 0_00, Spent: 900_00, Saved: -900_00, Balance: -50_00},
+		},
+	}, nil)
+
+	// Act
+	result, err := service.GetBalance(1, query)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount(1000_00), result.TotalAmountEarned)
+	assert.Equal(t, money.Amount(1150_00), result.TotalAmountSpend)
+	assert.Equal(t, money.Amount(-150_00), result.TotalAmountSaved)
+	assert.Equal(t, money.Amount(100_00), result.OpeningBalance)
+	assert.Equal(t, GranularityWeek, result.Granularity)
+	assert.Len(t, result.Buckets, 2)
+}
+
+func TestService_GetBalance_ShouldReturnError_WhenInvalidQuery(t *testing.T) {
+	from := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    BalanceQuery
+		expected error
+	}{
+		{"unknown granularity", BalanceQuery{Granularity: "quarter"}, ErrInvalidGranularity},
+		{"from after to", BalanceQuery{From: &from, To: &to}, ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockRepository)
+			service := NewService(mockRepo)
+
+			// Act
+			_, err := service.GetBalance(1, tt.query)
+
+			// Assert
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "CashFlow", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_GetCategoryReport_ShouldBreakDownByCategory(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 30, 0, 0, 0, 0, time.UTC)
+	date := from
+	query := CategoryQuery{From: from, To: to, Filter: Filter{Date: &date, Status: StatusDraft}}
+	expected := CategoryQuery{From: from, To: to, Top: DefaultTop}
+	mockRepo.On("CategoryTotals", 1, expected).Return([]CategoryTotal{
+		{TxnType: "expense", Category: "food", Total: 300_00, Count: 6, PreviousTotal: 200_00},
+		{TxnType: "expense", Category: "rent", Total: 900_00, Count: 1, PreviousTotal: 900_00},
+		{TxnType: "expense", Category: "travel", PreviousTotal: 500_00},
+		{TxnType: "income", Category: "salary", Total: 3000_00, Count: 1},
+	}, nil)
+	mockRepo.On("TopNotes", 1, expected).Return([]NoteTotal{
+		{TxnType: "expense", Note: "landlord", Total: 900_00, Count: 1},
+		{TxnType: "income", Note: "ACME", Total: 3000_00, Count: 1},
+	}, nil)
+
+	// Act
+	report, err := service.GetCategoryReport(1, query)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-02", report.PreviousFrom)
+	assert.Equal(t, "2024-03-31", report.PreviousTo)
+	assert.Equal(t, money.Amount(1200_00), report.Expense.Total)
+	assert.Equal(t, 7, report.Expense.Count)
+	assert.Equal(t, money.Amount(-400_00), report.Expense.Delta)
+	assert.Equal(t, -25.0, *report.Expense.Change)
+	assert.Equal(t, []string{"rent", "food", "travel"}, []string{
+		report.Expense.Categories[0].Category, report.Expense.Categories[1].Category, report.Expense.Categories[2].Category,
+	})
+	assert.Equal(t, 75.0, report.Expense.Categories[0].Percentage)
+	assert.Equal(t, 25.0, report.Expense.Categories[1].Percentage)
+	assert.Equal(t, money.Amount(100_00), report.Expense.Categories[1].Delta)
+	assert.Equal(t, 50.0, *report.Expense.Categories[1].Change)
+	assert.Equal(t, -100.0, *report.Expense.Categories[2].Change)
+	assert.Nil(t, report.Income.Categories[0].Change)
+	assert.Equal(t, 100.0, report.Income.Categories[0].Percentage)
+	assert.Equal(t, []NoteTotal{{TxnType: "expense", Note: "landlord", Total: 900_00, Count: 1}}, report.Expense.TopNotes)
+}
+
+func TestService_GetCategoryReport_ShouldReturnError_WhenInvalidQuery(t *testing.T) {
+	from := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    CategoryQuery
+		expected error
+	}{
+		{"top too large", CategoryQuery{From: to, To: from, Top: MaxTop + 1}, ErrInvalidTop},
+		{"from after to", CategoryQuery{From: from, To: to}, ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockRepository)
+			service := NewService(mockRepo)
+
+			// Act
+			_, err := service.GetCategoryReport(1, tt.query)
+
+			// Assert
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "CategoryTotals", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_Create_ShouldRejectTransferType(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+
+	// Act
+	_, err := service.Create(CreateTransactionRequest{Amount: 100_00, SpenderId: 1, TxnType: TxnTypeTransfer})
+
+	// Assert
+	assert.Equal(t, ErrInvalidTxnType, err)
+}
+
+func TestService_ShouldRejectNegativeAmount(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRepository)
+	service := NewService(mockRepo)
+
+	// Act
+	_, createErr := service.Create(CreateTransactionRequest{Amount: -1, SpenderId: 1, TxnType: "expense"})
+	updateErr := service.UpdateExpense(1, Transaction{ID: 5, Amount: -19_99})
+
+	// Assert
+	assert.Equal(t, ErrInvalidAmount, createErr)
+	assert.Equal(t, ErrInvalidAmount, updateErr)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateExpense", mock.Anything, mock.Anything)
+}
+
+func TestService_CreateTransfer(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     TransferRequest
+		expectedErr error
+	}{
+		{"valid", TransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: 500_00, SpenderId: 1}, nil},
+		{"same account", TransferRequest{FromAccountID: 1, ToAccountID: 1, Amount: 500_00, SpenderId: 1}, ErrInvalidTransfer},
+		{"zero amount", TransferRequest{FromAccountID: 1, ToAccountID: 2, SpenderId: 1}, ErrInvalidTransfer},
+		{"negative amount", TransferRequest{FromAccountID: 1, ToAccountID: 2, Amount: -5_00, SpenderId: 1}, ErrInvalidTransfer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockRepository)
+			service := NewService(mockRepo)
+			if tt.expectedErr == nil {
+				mockRepo.On("CreateTransfer", tt.request).Return(TransferResponse{DebitID: 1, CreditID: 2}, nil)
+			}
+
+			// Act
+			_, err := service.CreateTransfer(tt.request)
+
+			// Assert
+			assert.Equal(t, tt.expectedErr, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
 }
@@ -1,11 +1,73 @@
 package transaction
 
-import "time"
+import (
+	"errors"
+	"time"
 
+	"github.com/KKGo-Software-engineering/workshop-summer/api/money"
+)
+
+// Transactions created from a slip start as drafts until the spender
+// confirms or rejects them; everything else is confirmed. Only confirmed
+// transactions count towards summaries and balances.
+const (
+	StatusDraft     = "draft"
+	StatusConfirmed = "confirmed"
+	StatusRejected  = "rejected"
+)
+
+var ErrInvalidStatus = errors.New("status must be draft, confirmed or rejected")
+
+func ValidStatus(status string) bool {
+	return status == StatusDraft || status == StatusConfirmed || status == StatusRejected
+}
+
+// A transfer moves money between two of a spender's accounts. It is stored
+// as two transactions of TxnTypeTransfer, a debit from one account and a
+// credit to the other, each with the other's id as TransferID. Transfers are
+// neither spent nor earned, so they only count towards account balances.
+const (
+	TxnTypeTransfer = "transfer"
+	EntryDebit      = "debit"
+	EntryCredit     = "credit"
+)
+
+// Filter narrows a listing. CategoryIDs matches transactions in any of the
+// categories or their subcategories; AccountID those of one account.
 type Filter struct {
-	Date     *time.Time `json:"date"`
-	Amount   float64    `json:"amount"`
-	Category string     `json:"category"`
+	Date        *time.Time   `json:"date"`
+	Amount      money.Amount `json:"amount"`
+	Category    string       `json:"category"`
+	CategoryIDs []int        `json:"category_ids"`
+	AccountID   *int         `json:"account_id"`
+	Status      string       `json:"status"`
+}
+
+var (
+	ErrInvalidCategory  = errors.New("category_id must be a system category or one of your own")
+	ErrInvalidAccount   = errors.New("account_id must be one of your accounts")
+	ErrInvalidTransfer  = errors.New("a transfer needs two different accounts and an amount above zero")
+	ErrInvalidAmount    = errors.New("amount must not be negative")
+	ErrCurrencyMismatch = errors.New("the accounts of a transfer must be in the same currency")
+)
+
+// Sortable columns and orders of Sort.
+const (
+	SortByDate   = "date"
+	SortByAmount = "amount"
+	OrderAsc     = "asc"
+	OrderDesc    = "desc"
+)
+
+var ErrInvalidSort = errors.New("sort_by must be date or amount and order asc or desc")
+
+type Sort struct {
+	By    string `json:"sortBy"`
+	Order string `json:"order"`
+}
+
+func (s Sort) Valid() bool {
+	return (s.By == SortByDate || s.By == SortByAmount) && (s.Order == OrderAsc || s.Order == OrderDesc)
 }
 
 type Pagination struct {
@@ -13,49 +75,250 @@ type Pagination struct {
 	Page        int `json:"page"`
 }
 
+// Transaction amounts are in Currency, the currency the transaction was made
+// in, and are kept as recorded. Summaries, balances and reports convert them
+// to the spender's base currency at the exchange rate effective on the
+// transaction's date, and report currency.ErrMissingRate when there is none.
 type Transaction struct {
-	ID        int        `json:"id"`
-	Date      *time.Time `json:"date"`
-	Amount    float64    `json:"amount"`
-	Category  string     `json:"category"`
-	ImageUrl  string     `json:"image_url"`
-	Note      string     `json:"note"`
-	SpenderId int        `json:"spender_id"`
+	ID         int          `json:"id"`
+	Date       *time.Time   `json:"date"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	AccountID  *int         `json:"account_id"`
+	Currency   string       `json:"currency"`
+	ImageUrl   string       `json:"image_url"`
+	Note       string       `json:"note"`
+	SpenderId  int          `json:"spender_id"`
+	Status     string       `json:"status"`
+	Entry      string       `json:"entry,omitempty"`
+	TransferID *int         `json:"transfer_id,omitempty"`
 }
 
+// CreateTransactionRequest takes the category by CategoryID or, for clients
+// that still send free text, by the English or Thai name in Category.
+// AccountID is optional and must be one of the spender's accounts. Currency
+// defaults to the account's currency, or to the spender's base currency.
 type CreateTransactionRequest struct {
-	Date      *time.Time `json:"date"`
-	Amount    float64    `json:"amount"`
-	Category  string     `json:"category"`
-	ImageUrl  string     `json:"image_url"`
-	Note      string     `json:"note"`
-	SpenderId int        `json:"spender_id"`
-	TxnType   string     `json:"transaction_type"`
+	Date       *time.Time   `json:"date"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	AccountID  *int         `json:"account_id"`
+	Currency   string       `json:"currency"`
+	ImageUrl   string       `json:"image_url"`
+	Note       string       `json:"note"`
+	SpenderId  int          `json:"spender_id"`
+	TxnType    string       `json:"transaction_type"`
+	Status     string       `json:"-"`
 }
 
 type CreateTransactionResponse struct {
 	ID int `json:"id"`
 }
 
+// TransferRequest moves Amount from one of the spender's accounts to another
+// in the same currency, which is the currency of the transfer.
+type TransferRequest struct {
+	FromAccountID int          `json:"from_account_id"`
+	ToAccountID   int          `json:"to_account_id"`
+	Amount        money.Amount `json:"amount"`
+	Date          *time.Time   `json:"date"`
+	Note          string       `json:"note"`
+	SpenderId     int          `json:"-"`
+}
+
+// TransferResponse holds the ids of the two transactions of a transfer.
+type TransferResponse struct {
+	DebitID  int `json:"debit_id"`
+	CreditID int `json:"credit_id"`
+}
+
+// Bases of SummaryQuery.Average: every calendar day of the range, or only
+// the days with a transaction.
+const (
+	AverageCalendarDays = "calendar"
+	AverageActiveDays   = "active"
+)
+
+var (
+	ErrInvalidTxnType = errors.New("txn_type must be expense or income")
+	ErrInvalidAverage = errors.New("average must be calendar or active")
+	ErrInvalidDate    = errors.New("dates must be formatted as YYYY-MM-DD")
+	ErrInvalidRange   = errors.New("from must not be after to")
+)
+
+// SummaryQuery selects the transactions a summary covers. From and To are
+// inclusive dates and either may be nil; an empty TxnType covers expenses
+// and incomes, never transfers. Without a range, calendar days run from
+// the first to the last transaction. CategoryIDs, when set, keeps the
+// transactions of those categories and their subcategories.
+type SummaryQuery struct {
+	TxnType     string
+	From        *time.Time
+	To          *time.Time
+	Average     string
+	CategoryIDs []int
+}
+
+// Aggregate is what the repository adds up for a summary. First and Last
+// are the dates of the earliest and latest transaction, nil when Count is
+// zero.
+type Aggregate struct {
+	TotalAmount money.Amount
+	Count       int
+	ActiveDays  int
+	First       *time.Time
+	Last        *time.Time
+}
+
 type SummaryResponse struct {
-	TotalAmount     float64 `json:"total_amount"`
-	AvgAmountPerDay float64 `json:"avg_amount_per_day"`
-	Total           int     `json:"total"`
+	TotalAmount     money.Amount `json:"total_amount"`
+	AvgAmountPerDay money.Amount `json:"avg_amount_per_day"`
+	Total           int          `json:"total"`
+}
+
+// Granularities of BalanceQuery, the periods a balance report is bucketed
+// by. Weeks start on Monday.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+	GranularityYear  = "year"
+)
+
+var ErrInvalidGranularity = errors.New("granularity must be day, week, month or year")
+
+// BalanceQuery selects the periods of a balance report. From and To are
+// inclusive dates on the spender's calendar and either may be nil.
+type BalanceQuery struct {
+	From        *time.Time
+	To          *time.Time
+	Granularity string
+}
+
+// BalanceBucket is one period of a balance report. Start is the first day of
+// the period in the spender's time zone and Balance the running balance at
+// its end, counting every confirmed transaction since the first.
+type BalanceBucket struct {
+	Start   string       `json:"start"`
+	Earned  money.Amount `json:"earned"`
+	Spent   money.Amount `json:"spent"`
+	Saved   money.Amount `json:"saved"`
+	Balance money.Amount `json:"balance"`
+}
+
+// CashFlow is what the repository buckets for a balance report. Opening is
+// the balance carried in from before BalanceQuery.From; buckets without a
+// transaction are left out.
+type CashFlow struct {
+	Opening money.Amount
+	Buckets []BalanceBucket
 }
 
 type BalanceResponse struct {
-	TotalAmountEarned float64 `json:"total_amount_earned"`
-	TotalAmountSpend  float64 `json:"total_amount_spend"`
-	TotalAmountSaved  float64 `json:"total_amount_saved"`
+	TotalAmountEarned money.Amount    `json:"total_amount_earned"`
+	TotalAmountSpend  money.Amount    `json:"total_amount_spend"`
+	TotalAmountSaved  money.Amount    `json:"total_amount_saved"`
+	Granularity       string          `json:"granularity"`
+	OpeningBalance    money.Amount    `json:"opening_balance"`
+	Buckets           []BalanceBucket `json:"buckets"`
+}
+
+// DefaultTop and MaxTop bound CategoryQuery.Top.
+const (
+	DefaultTop = 5
+	MaxTop     = 50
+)
+
+var ErrInvalidTop = errors.New("top must be between 1 and 50")
+
+// CategoryQuery selects the confirmed transactions of a category report.
+// From and To are inclusive dates; Filter narrows it by category or amount.
+type CategoryQuery struct {
+	From   time.Time
+	To     time.Time
+	Filter Filter
+	Top    int
+}
+
+// Previous is the period of the same number of days that ends the day
+// before From.
+func (q CategoryQuery) Previous() (from, to time.Time) {
+	days := int(civilDate(q.To).Sub(civilDate(q.From)).Hours()/24) + 1
+	return q.From.AddDate(0, 0, -days), q.From.AddDate(0, 0, -1)
+}
+
+// civilDate is the calendar day of t, at midnight UTC so days compare and
+// add without daylight saving gaps.
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CategoryTotal is what the repository adds up for one category of one
+// transaction type, in the query's period and the previous one.
+type CategoryTotal struct {
+	TxnType       string
+	Category      string
+	Total         money.Amount
+	Count         int
+	PreviousTotal money.Amount
+}
+
+// NoteTotal is a note of the query's period with what was spent or earned
+// under it; notes name the merchant or payer of most transactions.
+type NoteTotal struct {
+	TxnType string       `json:"-"`
+	Note    string       `json:"note"`
+	Total   money.Amount `json:"total"`
+	Count   int          `json:"count"`
+}
+
+// CategoryShare is one category of a CategoryBreakdown. Change is the
+// percentage change from the previous period, nil when nothing was recorded
+// under the category then.
+type CategoryShare struct {
+	Category      string       `json:"category"`
+	Total         money.Amount `json:"total"`
+	Count         int          `json:"count"`
+	Percentage    float64      `json:"percentage"`
+	PreviousTotal money.Amount `json:"previous_total"`
+	Delta         money.Amount `json:"delta"`
+	Change        *float64     `json:"change_percentage"`
+}
+
+type CategoryBreakdown struct {
+	Total         money.Amount    `json:"total"`
+	Count         int             `json:"count"`
+	PreviousTotal money.Amount    `json:"previous_total"`
+	Delta         money.Amount    `json:"delta"`
+	Change        *float64        `json:"change_percentage"`
+	Categories    []CategoryShare `json:"categories"`
+	TopNotes      []NoteTotal     `json:"top_notes"`
+}
+
+type CategoryReport struct {
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	PreviousFrom string            `json:"previous_from"`
+	PreviousTo   string            `json:"previous_to"`
+	Expense      CategoryBreakdown `json:"expense"`
+	Income       CategoryBreakdown `json:"income"`
 }
 
 type GetTransactionResponse struct {
-	ID        int        `json:"id"`
-	Date      *time.Time `json:"date"`
-	Amount    float64    `json:"amount"`
-	Category  string     `json:"category"`
-	ImageUrl  string     `json:"image_url"`
-	Note      string     `json:"note"`
-	SpenderId int        `json:"spender_id"`
-	TxnType   string     `json:"transaction_type"`
+	ID         int          `json:"id"`
+	Date       *time.Time   `json:"date"`
+	Amount     money.Amount `json:"amount"`
+	Category   string       `json:"category"`
+	CategoryID *int         `json:"category_id"`
+	AccountID  *int         `json:"account_id"`
+	Currency   string       `json:"currency"`
+	ImageUrl   string       `json:"image_url"`
+	Note       string       `json:"note"`
+	SpenderId  int          `json:"spender_id"`
+	TxnType    string       `json:"transaction_type"`
+	Status     string       `json:"status"`
+	Entry      string       `json:"entry,omitempty"`
+	TransferID *int         `json:"transfer_id,omitempty"`
 }
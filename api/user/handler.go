@@ -0,0 +1,85 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/errs"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/mlog"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+var errLegacyAccount = errors.New("the shared legacy credential has no password to change")
+
+type handler struct {
+	service Service
+}
+
+type Handler interface {
+	Register(c echo.Context) error
+	ChangePassword(c echo.Context) error
+}
+
+func NewHandler(service Service) Handler {
+	return handler{
+		service: service,
+	}
+}
+
+func (h handler) Register(c echo.Context) error {
+	logger := mlog.L(c)
+
+	var request RegisterRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	result, err := h.service.Register(request)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrUsernameTaken), errors.Is(err, spender.ErrEmailTaken):
+		return c.JSON(http.StatusConflict, errs.Build(err))
+	case errors.Is(err, ErrInvalidUsername), errors.Is(err, ErrInvalidPassword), errors.Is(err, ErrInvalidSpender), errors.Is(err, spender.ErrInvalidEmail):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	default:
+		logger.Error("register user error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	logger.Info("register successfully", zap.Int("id", result.ID))
+	return c.JSON(http.StatusCreated, result)
+}
+
+func (h handler) ChangePassword(c echo.Context) error {
+	logger := mlog.L(c)
+
+	caller, ok := auth.IdentityFrom(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, errs.Build(auth.ErrUnauthenticated))
+	}
+	if caller.UserID == 0 {
+		return c.JSON(http.StatusForbidden, errs.Build(errLegacyAccount))
+	}
+
+	var request ChangePasswordRequest
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	}
+
+	err := h.service.ChangePassword(caller.UserID, request)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrInvalidPassword):
+		return c.JSON(http.StatusBadRequest, errs.Build(err))
+	case errors.Is(err, ErrInvalidCredentials), errors.Is(err, ErrLocked):
+		return c.JSON(http.StatusForbidden, errs.Build(err))
+	default:
+		logger.Error("change password error", zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, errs.Build(err))
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Password changed successfully"})
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) Register(request RegisterRequest) (RegisterResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(RegisterResponse), args.Error(1)
+}
+
+func (m *MockService) Authenticate(username, password string) (User, error) {
+	args := m.Called(username, password)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockService) Get(id int) (User, error) {
+	args := m.Called(id)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockService) ChangePassword(userID int, request ChangePasswordRequest) error {
+	args := m.Called(userID, request)
+	return args.Error(0)
+}
+
+func TestHandler_Register(t *testing.T) {
+	body := `{"username": "hongjot", "password": "p@ssw0rd!", "name": "HongJot", "email": "hong@jot.ok"}`
+	request := RegisterRequest{Username: "hongjot", Password: "p@ssw0rd!", Name: "HongJot", Email: "hong@jot.ok"}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockResponse   RegisterResponse
+		mockError      error
+		expectedStatus int
+	}{
+		{"created", body, RegisterResponse{ID: 1, Username: "hongjot", SpenderID: 2}, nil, http.StatusCreated},
+		{"username taken", body, RegisterResponse{}, ErrUsernameTaken, http.StatusConflict},
+		{"email taken", body, RegisterResponse{}, spender.ErrEmailTaken, http.StatusConflict},
+		{"invalid password", body, RegisterResponse{}, ErrInvalidPassword, http.StatusBadRequest},
+		{"internal error", body, RegisterResponse{}, errors.New("db down"), http.StatusInternalServerError},
+		{"bad request body", `{ bad request body }`, RegisterResponse{}, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			mockService := new(MockService)
+			mockService.On("Register", request).Return(tt.mockResponse, tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.Register(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	body := `{"old_password": "p@ssw0rd!", "new_password": "n3w-p@ssw0rd"}`
+	request := ChangePasswordRequest{OldPassword: "p@ssw0rd!", NewPassword: "n3w-p@ssw0rd"}
+
+	tests := []struct {
+		name           string
+		identity       *auth.Identity
+		mockError      error
+		expectedStatus int
+	}{
+		{"changed", &auth.Identity{UserID: 7, SpenderID: 1}, nil, http.StatusOK},
+		{"wrong old password", &auth.Identity{UserID: 7, SpenderID: 1}, ErrInvalidCredentials, http.StatusForbidden},
+		{"weak new password", &auth.Identity{UserID: 7, SpenderID: 1}, ErrInvalidPassword, http.StatusBadRequest},
+		{"legacy credential", &auth.Identity{Username: "user", SpenderID: 1}, nil, http.StatusForbidden},
+		{"not authenticated", nil, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPut, "/users/me/password", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.identity != nil {
+				auth.SetIdentity(c, *tt.identity)
+			}
+
+			mockService := new(MockService)
+			mockService.On("ChangePassword", 7, request).Return(tt.mockError)
+			h := NewHandler(mockService)
+
+			err := h.ChangePassword(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+)
+
+// Sessions is what the in-memory repository needs from refresh token
+// storage to sign a user out everywhere when the password changes.
+type Sessions interface {
+	RevokeUser(userID int)
+}
+
+type memoryRepository struct {
+	mu       sync.Mutex
+	users    map[int]User
+	nextID   int
+	spenders spender.Repository
+	sessions Sessions
+}
+
+// NewMemoryRepository keeps users in process memory for the --storage=memory
+// server mode. Registering a user creates its spender in spenders, and
+// changing a password revokes the user's refresh tokens in sessions, which
+// may be nil.
+func NewMemoryRepository(spenders spender.Repository, sessions Sessions) Repository {
+	return &memoryRepository{
+		users:    map[int]User{},
+		nextID:   1,
+		spenders: spenders,
+		sessions: sessions,
+	}
+}
+
+func (r *memoryRepository) Create(request RegisterRequest, passwordHash string) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Username == request.Username {
+			return User{}, ErrUsernameTaken
+		}
+	}
+
+	sp, err := r.spenders.Create(spender.Spender{Name: request.Name, Email: request.Email, TimeZone: spender.DefaultTimeZone, BaseCurrency: spender.DefaultBaseCurrency})
+	if err != nil {
+		return User{}, err
+	}
+
+	u := User{
+		ID:           r.nextID,
+		Username:     request.Username,
+		PasswordHash: passwordHash,
+		SpenderID:    int(sp.ID),
+		Role:         auth.RoleSpender,
+	}
+	r.nextID++
+	r.users[u.ID] = u
+
+	return u, nil
+}
+
+func (r *memoryRepository) FindByUsername(username string) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return User{}, ErrNotFound
+}
+
+func (r *memoryRepository) FindByID(id int) (User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *memoryRepository) UpdatePassword(id int, passwordHash string) error {
+	err := r.update(id, func(u *User) {
+		u.PasswordHash = passwordHash
+		u.FailedAttempts = 0
+		u.LockedUntil = nil
+	})
+	if err != nil {
+		return err
+	}
+	if r.sessions != nil {
+		r.sessions.RevokeUser(id)
+	}
+	return nil
+}
+
+func (r *memoryRepository) RecordFailedAttempt(id int, maxAttempts int, lockUntil time.Time) error {
+	err := r.update(id, func(u *User) {
+		u.FailedAttempts++
+		if u.FailedAttempts >= maxAttempts {
+			u.LockedUntil = &lockUntil
+		}
+	})
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (r *memoryRepository) ResetFailedAttempts(id int) error {
+	err := r.update(id, func(u *User) {
+		u.FailedAttempts = 0
+		u.LockedUntil = nil
+	})
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (r *memoryRepository) update(id int, change func(u *User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	change(&u)
+	r.users[id] = u
+
+	return nil
+}
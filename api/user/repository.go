@@ -0,0 +1,148 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound      = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username is already taken")
+)
+
+type Repository interface {
+	Create(request RegisterRequest, passwordHash string) (User, error)
+	FindByUsername(username string) (User, error)
+	FindByID(id int) (User, error)
+	UpdatePassword(id int, passwordHash string) error
+	RecordFailedAttempt(id int, maxAttempts int, lockUntil time.Time) error
+	ResetFailedAttempts(id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return repository{db: db}
+}
+
+const (
+	createSpenderStmt = `INSERT INTO spender (name, email) VALUES ($1, $2) RETURNING id;`
+	createUserStmt    = `INSERT INTO users (username, password_hash, spender_id) VALUES ($1, $2, $3) RETURNING id, role;`
+	selectUserQuery   = `SELECT id, username, password_hash, spender_id, role, failed_attempts, locked_until FROM users`
+
+	updatePasswordStmt = `UPDATE users SET password_hash = $1, failed_attempts = 0, locked_until = NULL, updated_at = now() WHERE id = $2`
+	revokeSessionsStmt = `UPDATE refresh_token SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+)
+
+// Create registers the user together with the spender row it is bound to, so
+// an account can never exist without a spender.
+func (r repository) Create(request RegisterRequest, passwordHash string) (User, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback()
+
+	var spenderID int
+	if err := tx.QueryRow(createSpenderStmt, request.Name, request.Email).Scan(&spenderID); err != nil {
+		if spender.IsEmailTaken(err) {
+			return User{}, spender.ErrEmailTaken
+		}
+		return User{}, err
+	}
+
+	var id int
+	var role string
+	if err := tx.QueryRow(createUserStmt, request.Username, passwordHash, spenderID).Scan(&id, &role); err != nil {
+		if isUniqueViolation(err) {
+			return User{}, ErrUsernameTaken
+		}
+		return User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return User{}, err
+	}
+
+	return User{ID: id, Username: request.Username, PasswordHash: passwordHash, SpenderID: spenderID, Role: role}, nil
+}
+
+func (r repository) FindByUsername(username string) (User, error) {
+	return r.findOne(selectUserQuery+` WHERE username = $1`, username)
+}
+
+func (r repository) FindByID(id int) (User, error) {
+	return r.findOne(selectUserQuery+` WHERE id = $1`, id)
+}
+
+func (r repository) findOne(query string, arg interface{}) (User, error) {
+	var u User
+	err := r.db.QueryRow(query, arg).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.SpenderID, &u.Role, &u.FailedAttempts, &u.LockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, ErrNotFound
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	return u, nil
+}
+
+// UpdatePassword sets the password and revokes every refresh token of the
+// user in the same transaction, so a token taken before the change cannot
+// outlive it.
+func (r repository) UpdatePassword(id int, passwordHash string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(updatePasswordStmt, passwordHash, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(revokeSessionsStmt, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordFailedAttempt increments the failure counter and locks the account
+// once it reaches maxAttempts. Both happen in one statement so concurrent
+// attempts cannot slip past the limit.
+func (r repository) RecordFailedAttempt(id int, maxAttempts int, lockUntil time.Time) error {
+	query := `UPDATE users SET failed_attempts = failed_attempts + 1,
+		locked_until = CASE WHEN failed_attempts + 1 >= $2 THEN $3 ELSE locked_until END,
+		updated_at = now()
+		WHERE id = $1`
+	_, err := r.db.Exec(query, id, maxAttempts, lockUntil)
+	return err
+}
+
+func (r repository) ResetFailedAttempts(id int) error {
+	query := `UPDATE users SET failed_attempts = 0, locked_until = NULL, updated_at = now() WHERE id = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,152 @@
+package user
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate_ShouldCreateSpenderAndUserInTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(createSpenderStmt).WithArgs("HongJot", "hong@jot.ok").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(createUserStmt).WithArgs("hongjot", "hash", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(1, "spender"))
+	mock.ExpectCommit()
+
+	u, err := repo.Create(RegisterRequest{Username: "hongjot", Name: "HongJot", Email: "hong@jot.ok"}, "hash")
+
+	assert.NoError(t, err)
+	assert.Equal(t, User{ID: 1, Username: "hongjot", PasswordHash: "hash", SpenderID: 2, Role: "spender"}, u)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate_ShouldReturnUsernameTaken_WhenUniqueViolation(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(createSpenderStmt).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(createUserStmt).WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(RegisterRequest{Username: "hongjot", Name: "HongJot", Email: "hong@jot.ok"}, "hash")
+
+	assert.Equal(t, ErrUsernameTaken, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreate_ShouldReturnEmailTaken_WhenEmailIndexViolated(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(createSpenderStmt).WillReturnError(&pq.Error{Code: "23505", Constraint: "spender_email_key"})
+	mock.ExpectRollback()
+
+	_, err = repo.Create(RegisterRequest{Username: "hongjot", Name: "HongJot", Email: "hong@jot.ok"}, "hash")
+
+	assert.Equal(t, spender.ErrEmailTaken, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindByUsername(t *testing.T) {
+	t.Run("should return user", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		lockedUntil := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+		rows := sqlmock.NewRows([]string{"id", "username", "password_hash", "spender_id", "role", "failed_attempts", "locked_until"}).
+			AddRow(1, "hongjot", "hash", 2, "admin", 5, lockedUntil)
+		mock.ExpectQuery(selectUserQuery + ` WHERE username = $1`).WithArgs("hongjot").WillReturnRows(rows)
+
+		u, err := repo.FindByUsername("hongjot")
+
+		assert.NoError(t, err)
+		assert.Equal(t, User{ID: 1, Username: "hongjot", PasswordHash: "hash", SpenderID: 2, Role: "admin", FailedAttempts: 5, LockedUntil: &lockedUntil}, u)
+	})
+
+	t.Run("should return not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+		mock.ExpectQuery(selectUserQuery + ` WHERE username = $1`).WithArgs("ghost").WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindByUsername("ghost")
+
+		assert.Equal(t, ErrNotFound, err)
+	})
+}
+
+func TestRecordFailedAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+	}
+	repo := NewRepository(db)
+	lockUntil := time.Date(2024, time.May, 1, 10, 15, 0, 0, time.UTC)
+
+	mock.ExpectExec(`UPDATE users SET failed_attempts = failed_attempts \+ 1`).WithArgs(1, 5, lockUntil).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.RecordFailedAttempt(1, 5, lockUntil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePassword(t *testing.T) {
+	t.Run("should revoke every refresh token of the user in the same transaction", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updatePasswordStmt).WithArgs("hash", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(revokeSessionsStmt).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err = repo.UpdatePassword(1, "hash")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should change nothing for an unknown user", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("An error occurred while creating mock DB connection: %v", err)
+		}
+		repo := NewRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updatePasswordStmt).WithArgs("hash", 9).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = repo.UpdatePassword(9, "hash")
+
+		assert.Equal(t, ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
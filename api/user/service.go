@@ -0,0 +1,168 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignores everything past 72 bytes, so longer passwords would
+	// silently share a hash with their prefix.
+	maxPasswordLength = 72
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrLocked             = errors.New("account is locked, try again later")
+	ErrInvalidUsername    = errors.New("username is required")
+	ErrInvalidPassword    = errors.New("password must be between 8 and 72 characters")
+	ErrInvalidSpender     = errors.New("name and email are required")
+)
+
+// dummyHash is compared against when the username does not exist so that
+// unknown and known usernames take the same time to reject.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type service struct {
+	repository Repository
+	cfg        config.Auth
+	cost       int
+	now        func() time.Time
+}
+
+type Service interface {
+	Register(request RegisterRequest) (RegisterResponse, error)
+	Authenticate(username, password string) (User, error)
+	Get(id int) (User, error)
+	ChangePassword(userID int, request ChangePasswordRequest) error
+}
+
+func NewService(repository Repository, cfg config.Auth) Service {
+	return service{
+		repository: repository,
+		cfg:        cfg,
+		cost:       bcrypt.DefaultCost,
+		now:        time.Now,
+	}
+}
+
+func (s service) Register(request RegisterRequest) (RegisterResponse, error) {
+	request.Username = strings.TrimSpace(request.Username)
+	if request.Username == "" {
+		return RegisterResponse{}, ErrInvalidUsername
+	}
+	if err := validatePassword(request.Password); err != nil {
+		return RegisterResponse{}, err
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	request.Email = strings.TrimSpace(request.Email)
+	if request.Name == "" || request.Email == "" {
+		return RegisterResponse{}, ErrInvalidSpender
+	}
+	if !spender.ValidEmail(request.Email) {
+		return RegisterResponse{}, spender.ErrInvalidEmail
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), s.cost)
+	if err != nil {
+		return RegisterResponse{}, err
+	}
+
+	u, err := s.repository.Create(request, string(hash))
+	if err != nil {
+		return RegisterResponse{}, err
+	}
+
+	return RegisterResponse{ID: u.ID, Username: u.Username, SpenderID: u.SpenderID}, nil
+}
+
+// Authenticate verifies the password and applies the lockout policy: after
+// cfg.MaxFailedAttempts consecutive failures the account is rejected for
+// cfg.LockoutDuration regardless of the password supplied.
+func (s service) Authenticate(username, password string) (User, error) {
+	u, err := s.repository.FindByUsername(username)
+	if errors.Is(err, ErrNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	if err := s.verify(u, password); err != nil {
+		return User{}, err
+	}
+
+	return u, nil
+}
+
+func (s service) Get(id int) (User, error) {
+	return s.repository.FindByID(id)
+}
+
+// ChangePassword replaces the password once the old one is verified. The
+// repository revokes every refresh token of the user with it, so sessions
+// opened with the old password end when their access token expires.
+func (s service) ChangePassword(userID int, request ChangePasswordRequest) error {
+	if err := validatePassword(request.NewPassword); err != nil {
+		return err
+	}
+
+	u, err := s.repository.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.verify(u, request.OldPassword); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), s.cost)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdatePassword(u.ID, string(hash))
+}
+
+func (s service) verify(u User, password string) error {
+	now := s.now()
+	if u.LockedUntil != nil {
+		if u.Locked(now) {
+			return ErrLocked
+		}
+		if err := s.repository.ResetFailedAttempts(u.ID); err != nil {
+			return err
+		}
+		u.FailedAttempts = 0
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		if err := s.repository.RecordFailedAttempt(u.ID, s.cfg.MaxFailedAttempts, now.Add(s.cfg.LockoutDuration)); err != nil {
+			return err
+		}
+		return ErrInvalidCredentials
+	}
+
+	if u.FailedAttempts > 0 {
+		if err := s.repository.ResetFailedAttempts(u.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return ErrInvalidPassword
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/config"
+	"github.com/KKGo-Software-engineering/workshop-summer/api/spender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(request RegisterRequest, passwordHash string) (User, error) {
+	args := m.Called(request, passwordHash)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockRepository) FindByUsername(username string) (User, error) {
+	args := m.Called(username)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockRepository) FindByID(id int) (User, error) {
+	args := m.Called(id)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockRepository) UpdatePassword(id int, passwordHash string) error {
+	args := m.Called(id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RecordFailedAttempt(id int, maxAttempts int, lockUntil time.Time) error {
+	args := m.Called(id, maxAttempts, lockUntil)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ResetFailedAttempts(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+var (
+	testAuthConfig = config.Auth{MaxFailedAttempts: 5, LockoutDuration: 15 * time.Minute}
+	testNow        = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+)
+
+func newTestService(repo Repository) service {
+	return service{
+		repository: repo,
+		cfg:        testAuthConfig,
+		cost:       bcrypt.MinCost,
+		now:        func() time.Time { return testNow },
+	}
+}
+
+func hashOf(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(hash)
+}
+
+func TestService_Register(t *testing.T) {
+	t.Run("should hash password and create user", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		request := RegisterRequest{Username: "hongjot", Password: "p@ssw0rd!", Name: "HongJot", Email: "hong@jot.ok"}
+
+		mockRepo.On("Create", request, mock.MatchedBy(func(hash string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hash), []byte("p@ssw0rd!")) == nil
+		})).Return(User{ID: 1, Username: "hongjot", SpenderID: 2}, nil)
+
+		result, err := s.Register(request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, RegisterResponse{ID: 1, Username: "hongjot", SpenderID: 2}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should validate request", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			request  RegisterRequest
+			expected error
+		}{
+			{"missing username", RegisterRequest{Password: "p@ssw0rd!", Name: "a", Email: "a@b.c"}, ErrInvalidUsername},
+			{"short password", RegisterRequest{Username: "a", Password: "short", Name: "a", Email: "a@b.c"}, ErrInvalidPassword},
+			{"missing spender", RegisterRequest{Username: "a", Password: "p@ssw0rd!"}, ErrInvalidSpender},
+			{"invalid email", RegisterRequest{Username: "a", Password: "p@ssw0rd!", Name: "a", Email: "a-at-b"}, spender.ErrInvalidEmail},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockRepository)
+				s := newTestService(mockRepo)
+
+				_, err := s.Register(tt.request)
+
+				assert.Equal(t, tt.expected, err)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	hash := hashOf(t, "p@ssw0rd!")
+	lockedUntil := testNow.Add(time.Minute)
+	expiredLock := testNow.Add(-time.Minute)
+
+	t.Run("should return user when password matches", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		u := User{ID: 1, Username: "hongjot", PasswordHash: hash, SpenderID: 2}
+		mockRepo.On("FindByUsername", "hongjot").Return(u, nil)
+
+		result, err := s.Authenticate("hongjot", "p@ssw0rd!")
+
+		assert.NoError(t, err)
+		assert.Equal(t, u, result)
+		mockRepo.AssertNotCalled(t, "ResetFailedAttempts", mock.Anything)
+	})
+
+	t.Run("should reset failure counter after successful login", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "hongjot").Return(User{ID: 1, PasswordHash: hash, FailedAttempts: 3}, nil)
+		mockRepo.On("ResetFailedAttempts", 1).Return(nil).Once()
+
+		_, err := s.Authenticate("hongjot", "p@ssw0rd!")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should record failed attempt when password is wrong", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "hongjot").Return(User{ID: 1, PasswordHash: hash}, nil)
+		mockRepo.On("RecordFailedAttempt", 1, 5, testNow.Add(15*time.Minute)).Return(nil).Once()
+
+		_, err := s.Authenticate("hongjot", "wrong")
+
+		assert.Equal(t, ErrInvalidCredentials, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject locked account even with correct password", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "hongjot").Return(User{ID: 1, PasswordHash: hash, FailedAttempts: 5, LockedUntil: &lockedUntil}, nil)
+
+		_, err := s.Authenticate("hongjot", "p@ssw0rd!")
+
+		assert.Equal(t, ErrLocked, err)
+		mockRepo.AssertNotCalled(t, "RecordFailedAttempt", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should unlock account once lockout has expired", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "hongjot").Return(User{ID: 1, PasswordHash: hash, FailedAttempts: 5, LockedUntil: &expiredLock}, nil)
+		mockRepo.On("ResetFailedAttempts", 1).Return(nil).Once()
+
+		_, err := s.Authenticate("hongjot", "p@ssw0rd!")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return invalid credentials when user does not exist", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "ghost").Return(User{}, ErrNotFound)
+
+		_, err := s.Authenticate("ghost", "p@ssw0rd!")
+
+		assert.Equal(t, ErrInvalidCredentials, err)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByUsername", "hongjot").Return(User{}, errors.New("db down"))
+
+		_, err := s.Authenticate("hongjot", "p@ssw0rd!")
+
+		assert.EqualError(t, err, "db down")
+	})
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	hash := hashOf(t, "p@ssw0rd!")
+
+	t.Run("should store hash of new password", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByID", 1).Return(User{ID: 1, PasswordHash: hash}, nil)
+		mockRepo.On("UpdatePassword", 1, mock.MatchedBy(func(hash string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hash), []byte("n3w-p@ssw0rd")) == nil
+		})).Return(nil).Once()
+
+		err := s.ChangePassword(1, ChangePasswordRequest{OldPassword: "p@ssw0rd!", NewPassword: "n3w-p@ssw0rd"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject wrong old password", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+		mockRepo.On("FindByID", 1).Return(User{ID: 1, PasswordHash: hash}, nil)
+		mockRepo.On("RecordFailedAttempt", 1, 5, mock.Anything).Return(nil).Once()
+
+		err := s.ChangePassword(1, ChangePasswordRequest{OldPassword: "wrong", NewPassword: "n3w-p@ssw0rd"})
+
+		assert.Equal(t, ErrInvalidCredentials, err)
+		mockRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject weak new password", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		s := newTestService(mockRepo)
+
+		err := s.ChangePassword(1, ChangePasswordRequest{OldPassword: "p@ssw0rd!", NewPassword: "short"})
+
+		assert.Equal(t, ErrInvalidPassword, err)
+	})
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"time"
+
+	"github.com/KKGo-Software-engineering/workshop-summer/api/auth"
+)
+
+type User struct {
+	ID             int
+	Username       string
+	PasswordHash   string
+	SpenderID      int
+	Role           string
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+func (u User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+func (u User) Identity() auth.Identity {
+	return auth.Identity{
+		UserID:    u.ID,
+		Username:  u.Username,
+		SpenderID: u.SpenderID,
+		Role:      u.Role,
+	}
+}
+
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+}
+
+type RegisterResponse struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	SpenderID int    `json:"spender_id"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
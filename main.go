@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,23 +18,20 @@ import (
 )
 
 func main() {
-	env := config.Env("ENV")
-	cfg := config.Parse(env)
+	storage := flag.String("storage", config.StoragePostgres, "where data is kept: postgres or memory")
+	flag.Parse()
 
-	db, err := sql.Open("postgres", cfg.Database.PostgresURI)
-	if err != nil {
-		log.Fatal(err)
-	}
-	if err := migration.ApplyMigrations(db); err != nil {
-		log.Fatal(err)
-	}
+	env := config.Env("ENV")
+	cfg := config.Parse(env, *storage)
 
 	logger, err := zap.NewProduction()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	e := api.New(db, cfg, logger)
+	e := api.New(newStorage(cfg), cfg, logger)
+	e.Jobs.Start()
+	e.Scheduler.Start()
 
 	go func() { // comment here to simulate slow endpoint then Ctrl+C to stop the server
 		if err := e.Start(":" + cfg.Server.Port); err != nil && err != http.ErrServerClosed {
@@ -54,5 +52,28 @@ func main() {
 	if err := e.Shutdown(ctx); err != nil {
 		logger.Fatal("shutting down the server:", zap.Error(err))
 	}
+	// Jobs get whatever is left of the same deadline to finish.
+	if err := e.Jobs.Shutdown(ctx); err != nil {
+		logger.Warn("job workers did not drain in time, unfinished jobs will be retried", zap.Error(err))
+	}
+	if err := e.Scheduler.Shutdown(ctx); err != nil {
+		logger.Warn("recurring transaction scheduler did not stop in time", zap.Error(err))
+	}
 	logger.Info("server shutdown gracefully")
 }
+
+func newStorage(cfg config.Config) api.Storage {
+	if cfg.Server.Storage == config.StorageMemory {
+		return api.NewMemoryStorage()
+	}
+
+	db, err := sql.Open("postgres", cfg.Database.PostgresURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := migration.ApplyMigrations(db); err != nil {
+		log.Fatal(err)
+	}
+
+	return api.NewPostgresStorage(db)
+}
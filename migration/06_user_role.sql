@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "users"
+  ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'spender'
+  CHECK (role IN ('admin', 'spender', 'service'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "users" DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails are unique regardless of case. Spenders whose emails differ only in
+-- case cannot be merged automatically, as each owns transactions and a login,
+-- so the migration stops and names them; rename or merge them by hand and
+-- run it again.
+DO $$
+DECLARE
+  duplicates TEXT;
+BEGIN
+  SELECT string_agg(email, ', ') INTO duplicates FROM (
+    SELECT LOWER(email) AS email FROM "spender" GROUP BY LOWER(email) HAVING COUNT(*) > 1
+  ) AS d;
+  IF duplicates IS NOT NULL THEN
+    RAISE EXCEPTION 'spender emails that differ only in case: %', duplicates
+      USING HINT = 'rename or merge these spenders, then run the migration again';
+  END IF;
+END;
+$$;
+CREATE UNIQUE INDEX IF NOT EXISTS spender_email_key ON "spender" (LOWER(email));
+
+-- transaction_archive keeps the transactions of spenders deleted with
+-- policy=archive.
+CREATE TABLE IF NOT EXISTS "transaction_archive" (
+  id INT PRIMARY KEY,
+  date TIMESTAMP WITH TIME ZONE,
+  amount DECIMAL(10,2),
+  category VARCHAR(50),
+  transaction_type VARCHAR(20),
+  note VARCHAR(255),
+  image_url VARCHAR(255),
+  spender_id INT,
+  archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS transaction_archive_spender_id_idx ON "transaction_archive" (spender_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "transaction_archive";
+DROP INDEX IF EXISTS spender_email_key;
+-- +goose StatementEnd
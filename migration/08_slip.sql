@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "slip" (
+  id SERIAL PRIMARY KEY,
+  spender_id INT NOT NULL REFERENCES "spender" (id) ON DELETE CASCADE,
+  key VARCHAR(128) NOT NULL UNIQUE,
+  sha256 CHAR(64) NOT NULL,
+  content_type VARCHAR(100) NOT NULL,
+  size BIGINT NOT NULL,
+  filename VARCHAR(255) NOT NULL DEFAULT '',
+  transaction_id INT REFERENCES "transaction" (id) ON DELETE SET NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- Not unique: ?force=true stores the same content again on purpose.
+CREATE INDEX IF NOT EXISTS slip_spender_id_sha256_idx ON "slip" (spender_id, sha256);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "slip";
+-- +goose StatementEnd
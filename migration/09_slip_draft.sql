@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transactions read from a slip's QR code start as drafts for the spender
+-- to review.
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'confirmed';
+
+ALTER TABLE "slip" ADD COLUMN IF NOT EXISTS reference VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS slip_spender_id_reference_idx ON "slip" (spender_id, reference) WHERE reference <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS slip_spender_id_reference_idx;
+ALTER TABLE "slip" DROP COLUMN IF EXISTS reference;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- extracted_at marks slips whose extraction result has been applied, so a
+-- redelivered webhook does not create a second transaction.
+ALTER TABLE "slip" ADD COLUMN IF NOT EXISTS extracted_at TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "slip" DROP COLUMN IF EXISTS extracted_at;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- job is the background work queue. Workers claim pending jobs with
+-- FOR UPDATE SKIP LOCKED; a running job whose lease expired is claimed
+-- again.
+CREATE TABLE IF NOT EXISTS "job" (
+  id SERIAL PRIMARY KEY,
+  kind VARCHAR(50) NOT NULL,
+  ref VARCHAR(128) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'dead')),
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  locked_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS job_claim_idx ON "job" (run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS job_kind_ref_idx ON "job" (kind, ref);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "job";
+-- +goose StatementEnd
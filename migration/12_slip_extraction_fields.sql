@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- extraction keeps the fields read from a slip with their confidence, so
+-- the spender can see why a transaction was left as a draft.
+ALTER TABLE "slip" ADD COLUMN IF NOT EXISTS extraction JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "slip" DROP COLUMN IF EXISTS extraction;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Drafts read from slips are confirmed or rejected by the spender; only
+-- confirmed transactions count towards summaries. The constraint is dropped
+-- first so the migration can be re-run.
+ALTER TABLE "transaction" DROP CONSTRAINT IF EXISTS transaction_status_check;
+ALTER TABLE "transaction" ADD CONSTRAINT transaction_status_check CHECK (status IN ('draft', 'confirmed', 'rejected'));
+CREATE INDEX IF NOT EXISTS transaction_spender_id_status_idx ON "transaction" (spender_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS transaction_spender_id_status_idx;
+ALTER TABLE "transaction" DROP CONSTRAINT IF EXISTS transaction_status_check;
+-- +goose StatementEnd
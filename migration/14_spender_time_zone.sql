@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Reports bucket a spender's transactions by its local calendar.
+ALTER TABLE "spender" ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Bangkok';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "spender" DROP COLUMN IF EXISTS time_zone;
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+-- System categories have no spender_id; custom ones belong to one spender.
+CREATE TABLE IF NOT EXISTS "category" (
+  id SERIAL PRIMARY KEY,
+  parent_id INT REFERENCES "category" (id) ON DELETE CASCADE,
+  spender_id INT REFERENCES "spender" (id) ON DELETE CASCADE,
+  name_en VARCHAR(50) NOT NULL,
+  name_th VARCHAR(50) NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS category_name_en_key ON "category" (COALESCE(spender_id, 0), LOWER(name_en));
+CREATE INDEX IF NOT EXISTS category_parent_id_idx ON "category" (parent_id);
+
+-- Keep in sync with category.Defaults.
+INSERT INTO "category" (id, parent_id, name_en, name_th) VALUES
+  (1, NULL, 'Food', 'อาหาร'),
+  (2, 1, 'Groceries', 'ของชำ'),
+  (3, 1, 'Dining out', 'ทานอาหารนอกบ้าน'),
+  (4, 1, 'Drinks', 'เครื่องดื่ม'),
+  (5, NULL, 'Transport', 'การเดินทาง'),
+  (6, 5, 'Fuel', 'น้ำมัน'),
+  (7, 5, 'Public transport', 'ขนส่งสาธารณะ'),
+  (8, 5, 'Taxi', 'แท็กซี่'),
+  (9, NULL, 'Housing', 'ที่อยู่อาศัย'),
+  (10, 9, 'Rent', 'ค่าเช่า'),
+  (11, 9, 'Utilities', 'ค่าน้ำค่าไฟ'),
+  (12, NULL, 'Bills', 'ค่าบริการ'),
+  (13, 12, 'Phone and internet', 'ค่าโทรศัพท์และอินเทอร์เน็ต'),
+  (14, NULL, 'Shopping', 'ช้อปปิ้ง'),
+  (15, NULL, 'Health', 'สุขภาพ'),
+  (16, NULL, 'Entertainment', 'บันเทิง'),
+  (17, NULL, 'Education', 'การศึกษา'),
+  (18, NULL, 'Salary', 'เงินเดือน'),
+  (19, NULL, 'Bonus', 'โบนัส'),
+  (20, NULL, 'Investment', 'การลงทุน'),
+  (21, NULL, 'Gifts', 'ของขวัญ'),
+  (22, NULL, 'Other', 'อื่นๆ')
+ON CONFLICT (id) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('category', 'id'), (SELECT MAX(id) FROM "category"));
+
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS category_id INT REFERENCES "category" (id) ON DELETE SET NULL;
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS category_id INT;
+CREATE INDEX IF NOT EXISTS transaction_category_id_idx ON "transaction" (category_id);
+
+-- Free-text categories matching a system category in English (any case) or
+-- Thai are mapped to it.
+UPDATE "transaction" t SET category_id = c.id
+FROM "category" c
+WHERE c.spender_id IS NULL AND TRIM(t.category) <> ''
+  AND (LOWER(TRIM(t.category)) = LOWER(c.name_en) OR TRIM(t.category) = c.name_th);
+
+-- The rest become custom categories of their spender, one per spelling
+-- ignoring case.
+INSERT INTO "category" (spender_id, name_en, name_th)
+SELECT DISTINCT ON (t.spender_id, LOWER(TRIM(t.category))) t.spender_id, TRIM(t.category), TRIM(t.category)
+FROM "transaction" t JOIN "spender" s ON s.id = t.spender_id
+WHERE t.category_id IS NULL AND TRIM(t.category) <> ''
+ORDER BY t.spender_id, LOWER(TRIM(t.category)), t.id;
+
+UPDATE "transaction" t SET category_id = c.id
+FROM "category" c
+WHERE t.category_id IS NULL AND c.spender_id = t.spender_id AND LOWER(c.name_en) = LOWER(TRIM(t.category));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS transaction_category_id_idx;
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS category_id;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS "category";
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A budget without category_id limits the spender's spending overall.
+CREATE TABLE IF NOT EXISTS "budget" (
+  id SERIAL PRIMARY KEY,
+  spender_id INT NOT NULL REFERENCES "spender" (id) ON DELETE CASCADE,
+  category_id INT REFERENCES "category" (id) ON DELETE CASCADE,
+  period VARCHAR(10) NOT NULL CHECK (period IN ('monthly', 'weekly', 'custom')),
+  amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+  start_date DATE NOT NULL,
+  end_date DATE CHECK (end_date >= start_date),
+  rollover BOOLEAN NOT NULL DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS budget_spender_id_idx ON "budget" (spender_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "budget";
+-- +goose StatementEnd
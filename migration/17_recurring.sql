@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- next_index counts the occurrences generated or skipped so far and
+-- next_date is the date of the next one, NULL once the series has ended.
+-- The scheduler advances next_index with a compare-and-set in the same
+-- database transaction that inserts the occurrence, so every occurrence is
+-- generated once across restarts and replicas.
+CREATE TABLE IF NOT EXISTS "recurring" (
+  id SERIAL PRIMARY KEY,
+  spender_id INT NOT NULL REFERENCES "spender" (id) ON DELETE CASCADE,
+  frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+  every INT NOT NULL DEFAULT 1 CHECK (every > 0),
+  day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+  start_date DATE NOT NULL,
+  end_date DATE,
+  count INT CHECK (count > 0),
+  transaction_type VARCHAR(20) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+  category VARCHAR(50) NOT NULL DEFAULT '',
+  category_id INT REFERENCES "category" (id) ON DELETE SET NULL,
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  skipped DATE[] NOT NULL DEFAULT '{}',
+  next_index INT NOT NULL DEFAULT 0,
+  next_date DATE
+);
+CREATE INDEX IF NOT EXISTS recurring_spender_id_idx ON "recurring" (spender_id);
+CREATE INDEX IF NOT EXISTS recurring_next_date_idx ON "recurring" (next_date) WHERE next_date IS NOT NULL;
+
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS recurring_id INT REFERENCES "recurring" (id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS transaction_recurring_id_date_key ON "transaction" (recurring_id, date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS transaction_recurring_id_date_key;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS recurring_id;
+DROP TABLE IF EXISTS "recurring";
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An account is where a spender keeps money: cash, a bank account or a
+-- credit card. Its balance is the opening balance plus its confirmed
+-- transactions, so it is not stored.
+CREATE TABLE IF NOT EXISTS "account" (
+  id SERIAL PRIMARY KEY,
+  spender_id INT NOT NULL REFERENCES "spender" (id) ON DELETE CASCADE,
+  name VARCHAR(50) NOT NULL,
+  type VARCHAR(20) NOT NULL CHECK (type IN ('cash', 'bank', 'credit_card')),
+  currency CHAR(3) NOT NULL DEFAULT 'THB',
+  opening_balance DECIMAL(12,2) NOT NULL DEFAULT 0
+);
+CREATE UNIQUE INDEX IF NOT EXISTS account_spender_id_name_key ON "account" (spender_id, LOWER(name));
+
+-- A transfer is two transactions of type transfer, a debit from one account
+-- and a credit to the other, each pointing at its counterpart through
+-- transfer_id so deleting either removes both.
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS account_id INT REFERENCES "account" (id);
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS entry VARCHAR(6) CHECK (entry IN ('debit', 'credit'));
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS transfer_id INT REFERENCES "transaction" (id) ON DELETE CASCADE;
+ALTER TABLE "transaction" DROP CONSTRAINT IF EXISTS transaction_transfer_check;
+ALTER TABLE "transaction" ADD CONSTRAINT transaction_transfer_check CHECK ((transaction_type = 'transfer') = (entry IS NOT NULL AND account_id IS NOT NULL));
+CREATE INDEX IF NOT EXISTS transaction_account_id_idx ON "transaction" (account_id);
+
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS account_id INT;
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS entry VARCHAR(6);
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS transfer_id INT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS entry;
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS account_id;
+DROP INDEX IF EXISTS transaction_account_id_idx;
+ALTER TABLE "transaction" DROP CONSTRAINT IF EXISTS transaction_transfer_check;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS entry;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS "account";
+-- +goose StatementEnd
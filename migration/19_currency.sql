@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Transactions keep the amount in the currency they were made in; reports
+-- convert it to the spender's base currency.
+ALTER TABLE "spender" ADD COLUMN IF NOT EXISTS base_currency CHAR(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE "transaction" ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'THB';
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS currency CHAR(3);
+
+-- An exchange rate is what one unit of currency is worth in THB from
+-- effective_date until the next rate of the currency. THB itself is always 1.
+CREATE TABLE IF NOT EXISTS "exchange_rate" (
+  currency CHAR(3) NOT NULL CHECK (currency <> 'THB'),
+  effective_date DATE NOT NULL,
+  rate DECIMAL(18,8) NOT NULL CHECK (rate > 0),
+  PRIMARY KEY (currency, effective_date)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- rate_at is the THB value of one unit of code at the time at, or now when
+-- at is NULL. A missing rate raises HJ001 so the API can report it.
+CREATE OR REPLACE FUNCTION rate_at(code CHAR(3), at TIMESTAMPTZ) RETURNS DECIMAL AS $$
+DECLARE
+  found DECIMAL;
+BEGIN
+  IF code = 'THB' THEN
+    RETURN 1;
+  END IF;
+  SELECT rate INTO found FROM exchange_rate
+    WHERE currency = code AND effective_date <= COALESCE(at, now())::date
+    ORDER BY effective_date DESC LIMIT 1;
+  IF found IS NULL THEN
+    RAISE EXCEPTION 'no exchange rate for % at %', code, COALESCE(at, now()) USING ERRCODE = 'HJ001';
+  END IF;
+  RETURN found;
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- convert_currency converts amount from one currency to another at the rates
+-- effective at the time at.
+CREATE OR REPLACE FUNCTION convert_currency(amount DECIMAL, source CHAR(3), target CHAR(3), at TIMESTAMPTZ) RETURNS DECIMAL AS $$
+BEGIN
+  IF source = target THEN
+    RETURN amount;
+  END IF;
+  RETURN amount * rate_at(source, at) / rate_at(target, at);
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS convert_currency(DECIMAL, CHAR(3), CHAR(3), TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS rate_at(CHAR(3), TIMESTAMPTZ);
+DROP TABLE IF EXISTS "exchange_rate";
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS currency;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS currency;
+ALTER TABLE "spender" DROP COLUMN IF EXISTS base_currency;
+-- +goose StatementEnd
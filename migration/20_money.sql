@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- convert_currency rounds each converted amount to the satang, like the API
+-- does, so a total is the sum of amounts that could have been recorded.
+CREATE OR REPLACE FUNCTION convert_currency(amount DECIMAL, source CHAR(3), target CHAR(3), at TIMESTAMPTZ) RETURNS DECIMAL AS $$
+BEGIN
+  IF source = target THEN
+    RETURN amount;
+  END IF;
+  RETURN ROUND(amount * rate_at(source, at) / rate_at(target, at), 2);
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION convert_currency(amount DECIMAL, source CHAR(3), target CHAR(3), at TIMESTAMPTZ) RETURNS DECIMAL AS $$
+BEGIN
+  IF source = target THEN
+    RETURN amount;
+  END IF;
+  RETURN amount * rate_at(source, at) / rate_at(target, at);
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An archived transaction keeps its review status and the series it was
+-- recorded for, so archived drafts and rejected transactions stay apart from
+-- confirmed ones. Rows archived before this migration are left without.
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS status VARCHAR(20);
+ALTER TABLE "transaction_archive" ADD COLUMN IF NOT EXISTS recurring_id INT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS recurring_id;
+ALTER TABLE "transaction_archive" DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd